@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"tstypechecker/pkg/parser"
@@ -31,7 +32,13 @@ func runAST(cmd *cobra.Command, args []string) error {
 	// Parse file
 	ast, err := parser.ParseFile(filename)
 	if err != nil {
-		return fmt.Errorf("parse error: %w", err)
+		if ast == nil {
+			return fmt.Errorf("parse error: %w", err)
+		}
+		// Partial AST: show it, but report what was skipped
+		for _, se := range parser.SyntaxErrorsOf(err) {
+			fmt.Fprintf(os.Stderr, "syntax error: %s\n", se)
+		}
 	}
 	
 	if ast == nil {
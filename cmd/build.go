@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tstypechecker/pkg/checker"
+	"tstypechecker/pkg/config"
+	"tstypechecker/pkg/modules"
+)
+
+// checkBuild checks the project at path and every project it references, in
+// build order. Each project is checked with its own compiler options and
+// files, and resolves imports of its references through their outputs.
+func checkBuild(path string) error {
+	projects, err := config.LoadProjectGraph(path)
+	if err != nil {
+		return err
+	}
+
+	checkStart := time.Now()
+	var initDuration time.Duration
+	var files []string
+	errorsByFile := make(map[string][]checker.TypeError)
+	checked := make(map[string]bool)
+
+	for _, project := range projects {
+		projectFiles, err := project.Config.FileMatcher().Files()
+		if err != nil {
+			return fmt.Errorf("%s: %w", project.ConfigPath, err)
+		}
+
+		// A file shared by several projects is checked with the first one
+		var pending []string
+		for _, file := range projectFiles {
+			if !checked[file] {
+				checked[file] = true
+				pending = append(pending, file)
+			}
+		}
+
+		if listFiles {
+			for _, file := range pending {
+				fmt.Println(file)
+			}
+			continue
+		}
+		if outputFormat == "text" {
+			fmt.Printf("%s[Build] %s: %d file(s)%s\n", colorGray, displayPath(project.ConfigPath), len(pending), colorReset)
+		}
+
+		initStart := time.Now()
+		typeChecker := checker.NewWithModuleResolver(project.Config.ConfigDir)
+		configureChecker(typeChecker, project.Config)
+		typeChecker.SetProjectOutputs(projectOutputs(project))
+		// Files shared with an earlier project still declare globals here
+		typeChecker.LoadGlobalDeclarations(projectFiles)
+		initDuration += time.Since(initStart)
+
+		for file, errs := range checkFilesParallel(typeChecker, pending, project.Config) {
+			errorsByFile[file] = errs
+		}
+		printResolutionTraces(typeChecker)
+		files = append(files, pending...)
+	}
+
+	if listFiles {
+		return nil
+	}
+	return reportDirectoryResults(files, errorsByFile, initDuration, time.Since(checkStart)-initDuration)
+}
+
+// projectOutputs maps the output directories of the projects project
+// references, directly or not, back to their sources
+func projectOutputs(project *config.Project) []modules.ProjectOutput {
+	var outputs []modules.ProjectOutput
+	for _, dep := range project.Dependencies() {
+		for _, dir := range dep.OutputDirs() {
+			outputs = append(outputs, modules.ProjectOutput{OutputDir: dir, SourceDir: dep.SourceDir()})
+		}
+	}
+	return outputs
+}
+
+// displayPath shortens path to be relative to the working directory when it
+// is inside it
+func displayPath(path string) string {
+	cwd, _ := os.Getwd()
+	rel, err := filepath.Rel(cwd, path)
+	if err != nil || filepath.IsAbs(rel) || len(rel) >= len(path) {
+		return path
+	}
+	return rel
+}
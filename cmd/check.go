@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 
 	"tstypechecker/pkg/checker"
 	"tstypechecker/pkg/config"
+	"tstypechecker/pkg/modules"
 	"tstypechecker/pkg/parser"
 	"tstypechecker/pkg/symbols"
 
@@ -27,6 +29,12 @@ var (
 	codeInput    string
 	filename     string
 	cpuProfile   string
+	watchMode    bool
+	incremental  bool
+	listFiles    bool
+	buildMode    bool
+
+	traceResolution bool
 )
 
 var checkCmd = &cobra.Command{
@@ -38,11 +46,16 @@ var checkCmd = &cobra.Command{
 }
 
 func init() {
-	checkCmd.Flags().StringVarP(&outputFormat, "format", "f", "text", "Output format: text, json, toon")
+	checkCmd.Flags().StringVarP(&outputFormat, "format", "f", "text", "Output format: text, json, toon, sarif")
 	checkCmd.Flags().BoolVarP(&showAST, "ast", "a", false, "Show AST output")
 	checkCmd.Flags().StringVarP(&codeInput, "code", "c", "", "TypeScript code as text input (alternative to file path)")
 	checkCmd.Flags().StringVarP(&filename, "filename", "n", "stdin.ts", "Filename to use when checking code from text input")
 	checkCmd.Flags().StringVar(&cpuProfile, "cpuprofile", "", "Write CPU profile to file")
+	checkCmd.Flags().BoolVarP(&watchMode, "watch", "w", false, "Watch the directory and re-check changed files and their importers")
+	checkCmd.Flags().BoolVar(&incremental, "incremental", false, "Cache results in "+checker.BuildInfoFileName+" and skip unchanged files on the next run")
+	checkCmd.Flags().BoolVarP(&buildMode, "build", "b", false, "Check the project and the projects it references, in dependency order")
+	checkCmd.Flags().BoolVar(&listFiles, "listFiles", false, "Print the files that are part of the check and exit")
+	checkCmd.Flags().BoolVar(&traceResolution, "traceResolution", false, "Print every path tried while resolving each import and why it was rejected")
 }
 
 func runCheck(cmd *cobra.Command, args []string) error {
@@ -71,6 +84,13 @@ func runCheck(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("path argument is required when --code flag is not used")
 	}
 
+	if buildMode {
+		if len(args) > 1 || watchMode {
+			return fmt.Errorf("--build takes a single project and cannot be combined with --watch")
+		}
+		return checkBuild(args[0])
+	}
+
 	// If multiple paths are provided, process them individually
 	if len(args) > 1 {
 		return checkMultiplePaths(args)
@@ -91,6 +111,10 @@ func runCheck(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot access path: %w", err)
 	}
 
+	if watchMode && !info.IsDir() {
+		return fmt.Errorf("--watch requires a directory path")
+	}
+
 	// Determine root directory for module resolution
 	var rootDir string
 	if info.IsDir() {
@@ -100,21 +124,7 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	}
 
 	// Find tsconfig.json by walking up the directory tree
-	configDir := rootDir
-	for {
-		configPath := filepath.Join(configDir, "tsconfig.json")
-		if _, err := os.Stat(configPath); err == nil {
-			rootDir = configDir
-			break
-		}
-
-		parent := filepath.Dir(configDir)
-		if parent == configDir {
-			// Reached root, no tsconfig.json found
-			break
-		}
-		configDir = parent
-	}
+	rootDir = findProjectRoot(rootDir)
 
 	// Load tsconfig.json if it exists
 	tsConfig, err := config.LoadTSConfig(rootDir)
@@ -124,6 +134,10 @@ func runCheck(cmd *cobra.Command, args []string) error {
 		tsConfig = config.GetDefaultConfig()
 	}
 
+	if listFiles {
+		return printFileList(absPath, info.IsDir(), tsConfig)
+	}
+
 	// Measure initialization time (loading types, libs, etc.)
 	initStart := time.Now()
 
@@ -132,6 +146,14 @@ func runCheck(cmd *cobra.Command, args []string) error {
 
 	// Configure type checker
 	configureChecker(typeChecker, tsConfig)
+	if len(tsConfig.References) > 0 {
+		// Imports of referenced projects resolve through their outputs
+		projects, err := config.LoadProjectGraph(tsConfig.ConfigDir)
+		if err != nil {
+			return err
+		}
+		typeChecker.SetProjectOutputs(projectOutputs(projects[len(projects)-1]))
+	}
 
 	initDuration := time.Since(initStart)
 
@@ -146,9 +168,13 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	}
 
 	// Process files
+	if watchMode {
+		return watchDirectory(typeChecker, absPath, tsConfig, initDuration)
+	}
 	if info.IsDir() {
 		return checkDirectory(typeChecker, absPath, tsConfig, initDuration)
 	} else {
+		loadProjectGlobals(typeChecker, rootDir, tsConfig)
 		return checkFile(typeChecker, absPath)
 	}
 }
@@ -186,23 +212,16 @@ func checkMultiplePaths(paths []string) error {
 		filesToCheck = append(filesToCheck, absPath)
 	}
 
-	// Find tsconfig.json by walking up the directory tree from rootDir
-	configDir := rootDir
-	for {
-		configPath := filepath.Join(configDir, "tsconfig.json")
-		if _, err := os.Stat(configPath); err == nil {
-			rootDir = configDir
-			break
-		}
-
-		parent := filepath.Dir(configDir)
-		if parent == configDir {
-			// Reached root, no tsconfig.json found
-			break
+	if listFiles {
+		for _, file := range filesToCheck {
+			fmt.Println(file)
 		}
-		configDir = parent
+		return nil
 	}
 
+	// Find tsconfig.json by walking up the directory tree from rootDir
+	rootDir = findProjectRoot(rootDir)
+
 	// Load tsconfig.json if it exists
 	tsConfig, err := config.LoadTSConfig(rootDir)
 	if err != nil {
@@ -218,6 +237,7 @@ func checkMultiplePaths(paths []string) error {
 
 	// Configure type checker
 	configureChecker(typeChecker, tsConfig)
+	loadProjectGlobals(typeChecker, rootDir, tsConfig)
 
 	initDuration := time.Since(initStart)
 
@@ -239,20 +259,22 @@ func checkMultiplePaths(paths []string) error {
 	for _, file := range filesToCheck {
 		// Parse file
 		ast, parseErr := parser.ParseFile(file)
+		var errors []checker.TypeError
 		if parseErr != nil {
-			// Report parse error as a type error
-			allErrors = append(allErrors, parseErrorToTypeError(file, parseErr))
-			filesWithErrors++
-			continue
+			// Report syntax errors as type errors
+			errors = parseErrorsToTypeErrors(file, parseErr)
 		}
 
-		// Type check
-		errors := typeChecker.CheckFile(file, ast)
+		// Type check whatever could be parsed
+		if ast != nil {
+			errors = append(errors, newFileChecker(typeChecker).CheckFile(file, ast)...)
+		}
 		if len(errors) > 0 {
 			filesWithErrors++
 			allErrors = append(allErrors, errors...)
 		}
 	}
+	printResolutionTraces(typeChecker)
 
 	checkDuration := time.Since(checkStart)
 	totalDuration := initDuration + checkDuration
@@ -264,13 +286,24 @@ func checkMultiplePaths(paths []string) error {
 			reportErrorsJSON(allErrors)
 		case "toon":
 			reportErrorsTOON(allErrors)
+		case "sarif":
+			reportErrorsSARIF(allErrors)
 		default:
 			reportErrorsWithContext("", allErrors)
 			// Show timing info
 			fmt.Printf("\n%s[Timing] Initialization: %dms | Type checking: %dms | Total: %dms%s\n",
 				colorGray, initDuration.Milliseconds(), checkDuration.Milliseconds(), totalDuration.Milliseconds(), colorReset)
 		}
-		return fmt.Errorf("type checking failed")
+		if hasErrors(allErrors) {
+			return fmt.Errorf("type checking failed")
+		}
+		return nil
+	}
+
+	if outputFormat == "sarif" {
+		// An empty log still tells code scanning the run was clean
+		reportErrorsSARIF(nil)
+		return nil
 	}
 
 	fmt.Printf("\n%s[Timing] Initialization: %dms | Type checking: %dms | Total: %dms%s\n",
@@ -279,6 +312,25 @@ func checkMultiplePaths(paths []string) error {
 	return nil
 }
 
+// findProjectRoot walks up from dir looking for tsconfig.json and returns the
+// directory that contains it, or dir itself when none is found
+func findProjectRoot(dir string) string {
+	configDir := dir
+	for {
+		configPath := filepath.Join(configDir, "tsconfig.json")
+		if _, err := os.Stat(configPath); err == nil {
+			return configDir
+		}
+
+		parent := filepath.Dir(configDir)
+		if parent == configDir {
+			// Reached root, no tsconfig.json found
+			return dir
+		}
+		configDir = parent
+	}
+}
+
 func configureChecker(typeChecker *checker.TypeChecker, tsConfig *config.TSConfig) {
 	// Configure type checker with libs from tsconfig
 	libs := tsConfig.CompilerOptions.GetLib()
@@ -289,6 +341,13 @@ func configureChecker(typeChecker *checker.TypeChecker, tsConfig *config.TSConfi
 		typeChecker.SetPathAliases(tsConfig.CompilerOptions.BaseUrl, tsConfig.CompilerOptions.Paths)
 	}
 
+	if traceResolution {
+		typeChecker.SetTraceResolution(true)
+	}
+
+	// Configure package resolution from tsconfig
+	typeChecker.SetModuleResolution(tsConfig.CompilerOptions.GetModuleResolution(), tsConfig.CompilerOptions.CustomConditions)
+
 	// Configure type roots from tsconfig
 	if len(tsConfig.CompilerOptions.TypeRoots) > 0 {
 		typeChecker.SetTypeRoots(tsConfig.CompilerOptions.TypeRoots)
@@ -297,7 +356,7 @@ func configureChecker(typeChecker *checker.TypeChecker, tsConfig *config.TSConfi
 	// Configure type checker with tsconfig options
 	checkerConfig := &checker.CompilerConfig{
 		NoImplicitAny:                tsConfig.CompilerOptions.NoImplicitAny,
-		StrictNullChecks:             tsConfig.CompilerOptions.StrictNullChecks,
+		StrictNullChecks:             tsConfig.CompilerOptions.ShouldCheckNullability(),
 		StrictFunctionTypes:          tsConfig.CompilerOptions.StrictFunctionTypes,
 		NoUnusedLocals:               tsConfig.CompilerOptions.NoUnusedLocals,
 		NoUnusedParameters:           tsConfig.CompilerOptions.NoUnusedParameters,
@@ -310,6 +369,7 @@ func configureChecker(typeChecker *checker.TypeChecker, tsConfig *config.TSConfi
 		AllowUnusedLabels:            tsConfig.CompilerOptions.AllowUnusedLabels,
 		NoFallthroughCasesInSwitch:   tsConfig.CompilerOptions.NoFallthroughCasesInSwitch,
 		NoUncheckedIndexedAccess:     tsConfig.CompilerOptions.NoUncheckedIndexedAccess,
+		CheckJs:                      tsConfig.CompilerOptions.CheckJs,
 	}
 	typeChecker.SetConfig(checkerConfig)
 }
@@ -317,39 +377,144 @@ func configureChecker(typeChecker *checker.TypeChecker, tsConfig *config.TSConfi
 func checkDirectory(templateTc *checker.TypeChecker, dir string, tsConfig *config.TSConfig, initDuration time.Duration) error {
 	checkStart := time.Now()
 
-	var files []string
+	files, err := collectSourceFiles(dir, tsConfig)
+	if err != nil {
+		return err
+	}
 
-	// Walk directory and find TypeScript files
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	filesChecked := len(files)
+	if filesChecked == 0 {
+		if outputFormat == "sarif" {
+			reportErrorsSARIF(nil)
+			return nil
 		}
+		fmt.Printf("\n%s✓%s Checked 0 files. No TypeScript files found.\n", colorGreen, colorReset)
+		return nil
+	}
 
-		// Skip node_modules and hidden directories
-		if info.IsDir() && (info.Name() == "node_modules" || info.Name()[0] == '.') {
-			return filepath.SkipDir
-		}
+	// Global declarations of any file merge with the ones of every other file
+	templateTc.LoadGlobalDeclarations(files)
 
-		// Only process .ts and .tsx files (and .js if allowJs is enabled)
-		ext := filepath.Ext(path)
-		isTypeScriptFile := ext == ".ts" || ext == ".tsx"
-		isJavaScriptFile := ext == ".js" || ext == ".jsx"
+	var errorsByFile map[string][]checker.TypeError
+	if incremental {
+		errorsByFile = checkFilesIncremental(templateTc, files, tsConfig)
+	} else {
+		errorsByFile = checkFilesParallel(templateTc, files, tsConfig)
+	}
+	printResolutionTraces(templateTc)
+
+	checkDuration := time.Since(checkStart)
+	return reportDirectoryResults(files, errorsByFile, initDuration, checkDuration)
+}
 
-		if !info.IsDir() && (isTypeScriptFile || (isJavaScriptFile && tsConfig.CompilerOptions.AllowJs)) {
-			files = append(files, path)
+// checkFilesIncremental replays cached diagnostics for files whose content and
+// transitive dependencies are unchanged and checks only the remaining files
+func checkFilesIncremental(templateTc *checker.TypeChecker, files []string, tsConfig *config.TSConfig) map[string][]checker.TypeError {
+	resolver := templateTc.GetModuleResolver()
+	cachePath := filepath.Join(resolver.GetRootDir(), checker.BuildInfoFileName)
+	cache := checker.LoadIncrementalCache(cachePath, compilerOptionsHash(tsConfig))
+	cache.SetModuleResolver(resolver)
+	cache.SetDeclarationFiles(templateTc.DeclarationFiles())
+
+	errorsByFile := make(map[string][]checker.TypeError, len(files))
+	var stale []string
+	for _, file := range files {
+		if diagnostics, ok := cache.Lookup(file); ok {
+			errorsByFile[file] = diagnostics
+		} else {
+			stale = append(stale, file)
 		}
+	}
 
-		return nil
-	})
+	for path, errs := range checkFilesParallel(templateTc, stale, tsConfig) {
+		errorsByFile[path] = errs
+		cache.Record(path, resolver.ImportsOf(path), resolver.UnresolvedImportsOf(path), errs)
+	}
 
-	if err != nil {
-		return err
+	if os.Getenv("TSCHECK_VERBOSE") == "1" {
+		fmt.Printf("%s[Incremental] %d file(s) up to date, %d re-checked%s\n",
+			colorGray, len(files)-len(stale), len(stale), colorReset)
 	}
 
-	filesChecked := len(files)
-	if filesChecked == 0 {
-		fmt.Printf("\n%s✓%s Checked 0 files. No TypeScript files found.\n", colorGreen, colorReset)
-		return nil
+	cache.Prune(files)
+	if err := cache.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s⚠%s Failed to write %s: %v\n", colorYellow, colorReset, cachePath, err)
+	}
+
+	return errorsByFile
+}
+
+// compilerOptionsHash identifies the configuration diagnostics were produced
+// with, so changing tsconfig invalidates the incremental cache. The options
+// are hashed as JSON, which prints pointed-to values instead of addresses
+func compilerOptionsHash(tsConfig *config.TSConfig) string {
+	data, _ := json.Marshal(tsConfig.CompilerOptions)
+	return modules.SharedGlobalCache.CalculateHash(data)
+}
+
+// loadProjectGlobals binds the global declarations and the declared modules
+// of every file of the project in rootDir, which the checked files see even
+// when they are not checked themselves
+func loadProjectGlobals(templateTc *checker.TypeChecker, rootDir string, tsConfig *config.TSConfig) {
+	if files, err := collectSourceFiles(rootDir, tsConfig); err == nil {
+		templateTc.LoadGlobalDeclarations(files)
+	}
+}
+
+// newFileChecker returns a checker for a single file that sees the types and
+// global declarations loaded by templateTc without binding into its scopes
+func newFileChecker(templateTc *checker.TypeChecker) *checker.TypeChecker {
+	tc := checker.NewForWorker(templateTc.GetModuleResolver(), symbols.NewSymbolTable())
+	tc.CopyGlobalTypesFrom(templateTc)
+	tc.SetConfig(templateTc.GetConfig())
+	return tc
+}
+
+// collectSourceFiles returns the files that should be type checked in dir
+func collectSourceFiles(dir string, tsConfig *config.TSConfig) ([]string, error) {
+	return sourceFileMatcher(dir, tsConfig).Files()
+}
+
+// sourceFileMatcher selects the files checked in dir. The project root is
+// checked as tsconfig's include, exclude and files describe it; any other
+// directory replaces include and files, so every source file under it that
+// is not excluded is checked. .vue files whose scripts are JavaScript are
+// skipped later by the checker.
+func sourceFileMatcher(dir string, tsConfig *config.TSConfig) *config.FileMatcher {
+	if dir == tsConfig.ConfigDir {
+		return tsConfig.FileMatcher()
+	}
+	return tsConfig.DirectoryFileMatcher(dir)
+}
+
+// printFileList prints the files a check of path would cover, one per line
+func printFileList(path string, isDir bool, tsConfig *config.TSConfig) error {
+	files := []string{path}
+	if isDir {
+		var err error
+		if files, err = collectSourceFiles(path, tsConfig); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		fmt.Println(file)
+	}
+	return nil
+}
+
+// fileCheckResult holds the diagnostics produced for a single file
+type fileCheckResult struct {
+	path   string
+	errors []checker.TypeError
+}
+
+// checkFilesParallel type checks files with a pool of workers that share the
+// template checker's global types and module resolver. The result maps every
+// checked file to its diagnostics (nil when the file is clean).
+func checkFilesParallel(templateTc *checker.TypeChecker, files []string, tsConfig *config.TSConfig) map[string][]checker.TypeError {
+	errorsByFile := make(map[string][]checker.TypeError, len(files))
+	if len(files) == 0 {
+		return errorsByFile
 	}
 
 	// Prepare for parallel execution
@@ -359,12 +524,12 @@ func checkDirectory(templateTc *checker.TypeChecker, dir string, tsConfig *confi
 		numWorkers = 8
 	}
 	// Don't use more workers than files
-	if numWorkers > filesChecked {
-		numWorkers = filesChecked
+	if numWorkers > len(files) {
+		numWorkers = len(files)
 	}
 
-	jobs := make(chan string, filesChecked)
-	results := make(chan []checker.TypeError, filesChecked)
+	jobs := make(chan string, len(files))
+	results := make(chan fileCheckResult, len(files))
 	var wg sync.WaitGroup
 
 	// Get shared resolver from template
@@ -398,7 +563,7 @@ func checkDirectory(templateTc *checker.TypeChecker, dir string, tsConfig *confi
 				// Set compiler config
 				tc.SetConfig(&checker.CompilerConfig{
 					NoImplicitAny:                tsConfig.CompilerOptions.NoImplicitAny,
-					StrictNullChecks:             tsConfig.CompilerOptions.StrictNullChecks,
+					StrictNullChecks:             tsConfig.CompilerOptions.ShouldCheckNullability(),
 					StrictFunctionTypes:          tsConfig.CompilerOptions.StrictFunctionTypes,
 					NoUnusedLocals:               tsConfig.CompilerOptions.NoUnusedLocals,
 					NoUnusedParameters:           tsConfig.CompilerOptions.NoUnusedParameters,
@@ -411,19 +576,22 @@ func checkDirectory(templateTc *checker.TypeChecker, dir string, tsConfig *confi
 					AllowUnusedLabels:            tsConfig.CompilerOptions.AllowUnusedLabels,
 					NoFallthroughCasesInSwitch:   tsConfig.CompilerOptions.NoFallthroughCasesInSwitch,
 					NoUncheckedIndexedAccess:     tsConfig.CompilerOptions.NoUncheckedIndexedAccess,
+					CheckJs:                      tsConfig.CompilerOptions.CheckJs,
 				})
 
 				// Parse file
 				ast, parseErr := parser.ParseFile(path)
+				var errors []checker.TypeError
 				if parseErr != nil {
-					// Report parse error as a type error
-					results <- []checker.TypeError{parseErrorToTypeError(path, parseErr)}
-					continue
+					// Report syntax errors as type errors
+					errors = parseErrorsToTypeErrors(path, parseErr)
 				}
 
-				// Type check
-				errors := tc.CheckFile(path, ast)
-				results <- errors
+				// Type check whatever could be parsed
+				if ast != nil {
+					errors = append(errors, tc.CheckFile(path, ast)...)
+				}
+				results <- fileCheckResult{path: path, errors: errors}
 
 				// Return symbol table to pool for reuse
 				symbolPool.Put(st)
@@ -444,19 +612,29 @@ func checkDirectory(templateTc *checker.TypeChecker, dir string, tsConfig *confi
 	}()
 
 	// Collect results
+	for result := range results {
+		errorsByFile[result.path] = result.errors
+	}
+
+	return errorsByFile
+}
+
+// reportDirectoryResults prints the diagnostics of a directory check in the
+// selected output format and returns an error when any file has errors
+func reportDirectoryResults(files []string, errorsByFile map[string][]checker.TypeError, initDuration, checkDuration time.Duration) error {
+	filesChecked := len(files)
+	totalDuration := initDuration + checkDuration
+
+	// Keep the walk order so output is stable between runs
 	var allErrors []checker.TypeError
 	filesWithErrors := 0
-
-	for errs := range results {
-		if len(errs) > 0 {
+	for _, file := range files {
+		if errs := errorsByFile[file]; len(errs) > 0 {
 			filesWithErrors++
 			allErrors = append(allErrors, errs...)
 		}
 	}
 
-	checkDuration := time.Since(checkStart)
-	totalDuration := initDuration + checkDuration
-
 	// Report summary with timing breakdown
 	if len(allErrors) > 0 {
 		switch outputFormat {
@@ -464,6 +642,8 @@ func checkDirectory(templateTc *checker.TypeChecker, dir string, tsConfig *confi
 			reportErrorsJSON(allErrors)
 		case "toon":
 			reportErrorsTOON(allErrors)
+		case "sarif":
+			reportErrorsSARIF(allErrors)
 		default:
 			reportErrorsWithContext("", allErrors)
 			// Show timing info and file count
@@ -471,33 +651,51 @@ func checkDirectory(templateTc *checker.TypeChecker, dir string, tsConfig *confi
 			fmt.Printf("\n%s[Timing] Initialization: %dms | Type checking: %dms | Total: %dms%s\n",
 				colorGray, initDuration.Milliseconds(), checkDuration.Milliseconds(), totalDuration.Milliseconds(), colorReset)
 		}
-		return fmt.Errorf("type checking failed")
+		if hasErrors(allErrors) {
+			return fmt.Errorf("type checking failed")
+		}
+		return nil
+	}
+
+	if outputFormat == "sarif" {
+		// An empty log still tells code scanning the run was clean
+		reportErrorsSARIF(nil)
+		return nil
 	}
 
 	fmt.Printf("\n%s[Timing] Initialization: %dms | Type checking: %dms | Total: %dms%s\n",
 		colorGray, initDuration.Milliseconds(), checkDuration.Milliseconds(), totalDuration.Milliseconds(), colorReset)
 	fmt.Printf("%s✓%s Checked %d files. No errors found.\n", colorGreen, colorReset, filesChecked)
 	return nil
-
 }
+
 func checkFile(tc *checker.TypeChecker, filename string) error {
 	startTime := time.Now()
 
 	// Parse file
 	ast, err := parser.ParseFile(filename)
+	var syntaxErrors []checker.TypeError
 	if err != nil {
+		syntaxErrors = parseErrorsToTypeErrors(filename, err)
+	}
+	if ast == nil {
 		// Report parse error as a type error instead of hard failing
-		errors := []checker.TypeError{parseErrorToTypeError(filename, err)}
+		errors := syntaxErrors
 
 		if outputFormat == "json" {
 			reportErrorsJSON(errors)
 		} else if outputFormat == "toon" {
 			reportErrorsTOON(errors)
+		} else if outputFormat == "sarif" {
+			reportErrorsSARIF(errors)
 		} else {
 			reportErrorsWithContext(filename, errors)
 			fmt.Printf("\n%sFinished in %dms.%s\n", colorGray, time.Since(startTime).Milliseconds(), colorReset)
 		}
-		return fmt.Errorf("type checking failed")
+		if hasErrors(errors) {
+			return fmt.Errorf("type checking failed")
+		}
+		return nil
 	}
 
 	// Show AST if requested
@@ -509,8 +707,9 @@ func checkFile(tc *checker.TypeChecker, filename string) error {
 		fmt.Printf("AST for %s:\n%s\n", filename, astJSON)
 	}
 
-	// Type check
-	errors := tc.CheckFile(filename, ast)
+	// Type check the intact parts of the file
+	errors := append(syntaxErrors, newFileChecker(tc).CheckFile(filename, ast)...)
+	printResolutionTraces(tc)
 
 	elapsed := time.Since(startTime)
 	elapsedMs := elapsed.Milliseconds()
@@ -522,11 +721,22 @@ func checkFile(tc *checker.TypeChecker, filename string) error {
 			reportErrorsJSON(errors)
 		case "toon":
 			reportErrorsTOON(errors)
+		case "sarif":
+			reportErrorsSARIF(errors)
 		default:
 			reportErrorsWithContext(filename, errors)
 			fmt.Printf("\n%sFinished in %dms.%s\n", colorGray, elapsedMs, colorReset)
 		}
-		return fmt.Errorf("type checking failed")
+		if hasErrors(errors) {
+			return fmt.Errorf("type checking failed")
+		}
+		return nil
+	}
+
+	if outputFormat == "sarif" {
+		// An empty log still tells code scanning the run was clean
+		reportErrorsSARIF(nil)
+		return nil
 	}
 
 	// Success message
@@ -566,6 +776,13 @@ func checkCodeInput(code string, name string) error {
 		typeChecker.SetPathAliases(tsConfig.CompilerOptions.BaseUrl, tsConfig.CompilerOptions.Paths)
 	}
 
+	if traceResolution {
+		typeChecker.SetTraceResolution(true)
+	}
+
+	// Configure package resolution from tsconfig
+	typeChecker.SetModuleResolution(tsConfig.CompilerOptions.GetModuleResolution(), tsConfig.CompilerOptions.CustomConditions)
+
 	// Configure type roots from tsconfig
 	if len(tsConfig.CompilerOptions.TypeRoots) > 0 {
 		typeChecker.SetTypeRoots(tsConfig.CompilerOptions.TypeRoots)
@@ -574,7 +791,7 @@ func checkCodeInput(code string, name string) error {
 	// Configure type checker with tsconfig options
 	checkerConfig := &checker.CompilerConfig{
 		NoImplicitAny:                tsConfig.CompilerOptions.NoImplicitAny,
-		StrictNullChecks:             tsConfig.CompilerOptions.StrictNullChecks,
+		StrictNullChecks:             tsConfig.CompilerOptions.ShouldCheckNullability(),
 		StrictFunctionTypes:          tsConfig.CompilerOptions.StrictFunctionTypes,
 		NoUnusedLocals:               tsConfig.CompilerOptions.NoUnusedLocals,
 		NoUnusedParameters:           tsConfig.CompilerOptions.NoUnusedParameters,
@@ -587,6 +804,7 @@ func checkCodeInput(code string, name string) error {
 		AllowUnusedLabels:            tsConfig.CompilerOptions.AllowUnusedLabels,
 		NoFallthroughCasesInSwitch:   tsConfig.CompilerOptions.NoFallthroughCasesInSwitch,
 		NoUncheckedIndexedAccess:     tsConfig.CompilerOptions.NoUncheckedIndexedAccess,
+		CheckJs:                      tsConfig.CompilerOptions.CheckJs,
 	}
 	typeChecker.SetConfig(checkerConfig)
 
@@ -597,19 +815,28 @@ func checkCodeInput(code string, name string) error {
 
 	// Parse code from string
 	ast, err := parser.ParseCode(code, name)
+	var syntaxErrors []checker.TypeError
 	if err != nil {
+		syntaxErrors = parseErrorsToTypeErrors(name, err)
+	}
+	if ast == nil {
 		// Report parse error as a type error
-		errors := []checker.TypeError{parseErrorToTypeError(name, err)}
+		errors := syntaxErrors
 
 		if outputFormat == "json" {
 			reportErrorsJSON(errors)
 		} else if outputFormat == "toon" {
 			reportErrorsTOON(errors)
+		} else if outputFormat == "sarif" {
+			reportErrorsSARIF(errors)
 		} else {
 			reportErrorsWithContextFromCode(name, code, errors)
 			fmt.Printf("\n%sFinished in %dms.%s\n", colorGray, time.Since(startTime).Milliseconds(), colorReset)
 		}
-		return fmt.Errorf("type checking failed")
+		if hasErrors(errors) {
+			return fmt.Errorf("type checking failed")
+		}
+		return nil
 	}
 
 	// Show AST if requested
@@ -621,8 +848,9 @@ func checkCodeInput(code string, name string) error {
 		fmt.Printf("AST for %s:\n%s\n", name, astJSON)
 	}
 
-	// Type check
-	errors := typeChecker.CheckFile(name, ast)
+	// Type check the intact parts of the code
+	errors := append(syntaxErrors, typeChecker.CheckFile(name, ast)...)
+	printResolutionTraces(typeChecker)
 
 	elapsed := time.Since(startTime)
 	elapsedMs := elapsed.Milliseconds()
@@ -634,11 +862,22 @@ func checkCodeInput(code string, name string) error {
 			reportErrorsJSON(errors)
 		case "toon":
 			reportErrorsTOON(errors)
+		case "sarif":
+			reportErrorsSARIF(errors)
 		default:
 			reportErrorsWithContextFromCode(name, code, errors)
 			fmt.Printf("\n%sFinished in %dms.%s\n", colorGray, elapsedMs, colorReset)
 		}
-		return fmt.Errorf("type checking failed")
+		if hasErrors(errors) {
+			return fmt.Errorf("type checking failed")
+		}
+		return nil
+	}
+
+	if outputFormat == "sarif" {
+		// An empty log still tells code scanning the run was clean
+		reportErrorsSARIF(nil)
+		return nil
 	}
 
 	// Success message
@@ -647,6 +886,28 @@ func checkCodeInput(code string, name string) error {
 }
 
 // parseErrorToTypeError converts a parser error to a checker.TypeError with TS1005 code
+// parseErrorsToTypeErrors converts the error returned by the parser into one
+// TS1005 diagnostic per syntax error
+func parseErrorsToTypeErrors(filename string, err error) []checker.TypeError {
+	syntaxErrors := parser.SyntaxErrorsOf(err)
+	if len(syntaxErrors) == 0 {
+		return []checker.TypeError{parseErrorToTypeError(filename, err)}
+	}
+
+	result := make([]checker.TypeError, 0, len(syntaxErrors))
+	for _, se := range syntaxErrors {
+		result = append(result, checker.TypeError{
+			File:     filename,
+			Line:     se.Position.Line,
+			Column:   se.Position.Column,
+			Message:  fmt.Sprintf("Parse error: %s", se.Message),
+			Code:     "TS1005",
+			Severity: "error",
+		})
+	}
+	return result
+}
+
 func parseErrorToTypeError(filename string, err error) checker.TypeError {
 	line := 1
 	column := 1
@@ -792,6 +1053,17 @@ func reportErrorsTOON(errors []checker.TypeError) {
 	}
 }
 
+// hasErrors reports whether any diagnostic is an error, so that a run with
+// only warnings still succeeds
+func hasErrors(diagnostics []checker.TypeError) bool {
+	for _, d := range diagnostics {
+		if d.Severity != "warning" {
+			return true
+		}
+	}
+	return false
+}
+
 func reportErrorsWithContext(filename string, errors []checker.TypeError) {
 	if len(errors) == 0 {
 		return
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tstypechecker/pkg/config"
+)
+
+func TestCompilerOptionsHashIsStable(t *testing.T) {
+	dir := t.TempDir()
+	tsconfig := `{"compilerOptions": {"strict": true, "allowUnreachableCode": false}}`
+	if err := os.WriteFile(filepath.Join(dir, "tsconfig.json"), []byte(tsconfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	load := func() *config.TSConfig {
+		c, err := config.LoadTSConfig(dir)
+		if err != nil {
+			t.Fatalf("LoadTSConfig failed: %v", err)
+		}
+		return c
+	}
+	first, second := compilerOptionsHash(load()), compilerOptionsHash(load())
+	if first != second {
+		t.Errorf("hashes of the same tsconfig differ: %s and %s", first, second)
+	}
+}
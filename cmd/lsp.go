@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"tstypechecker/pkg/checker"
+	"tstypechecker/pkg/config"
+	"tstypechecker/pkg/lsp"
+	"tstypechecker/pkg/parser"
+
+	"github.com/spf13/cobra"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Start a Language Server Protocol server over stdio",
+	Long: `Start a Language Server Protocol server that speaks JSON-RPC over stdin/stdout.
+Libs and node_modules types are loaded once and reused for every check, and
+diagnostics are computed from the editor's in-memory buffers.`,
+	Args: cobra.NoArgs,
+	RunE: runLSP,
+}
+
+func runLSP(cmd *cobra.Command, args []string) error {
+	server := lsp.NewServer(os.Stdin, os.Stdout, &lspWorkspace{})
+	return server.Serve()
+}
+
+// lspWorkspace keeps a long-lived template checker and creates a fresh
+// per-check worker from it, the same way checkDirectory does
+type lspWorkspace struct {
+	rootDir    string
+	tsConfig   *config.TSConfig
+	templateTc *checker.TypeChecker
+}
+
+func (w *lspWorkspace) Load(rootDir string) error {
+	if rootDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("cannot determine workspace root: %w", err)
+		}
+		rootDir = cwd
+	}
+
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return fmt.Errorf("invalid workspace root: %w", err)
+	}
+	w.rootDir = findProjectRoot(absRoot)
+
+	tsConfig, err := config.LoadTSConfig(w.rootDir)
+	if err != nil {
+		// Log to stderr: stdout is reserved for the protocol
+		fmt.Fprintf(os.Stderr, "tscheck lsp: failed to load tsconfig.json: %v. Using default configuration.\n", err)
+		tsConfig = config.GetDefaultConfig()
+	}
+	w.tsConfig = tsConfig
+
+	w.templateTc = checker.NewWithModuleResolver(w.rootDir)
+	configureChecker(w.templateTc, w.tsConfig)
+	// Open buffers declare their own modules again when they are checked
+	loadProjectGlobals(w.templateTc, w.rootDir, w.tsConfig)
+	return nil
+}
+
+func (w *lspWorkspace) Check(path string, content string) []checker.TypeError {
+	resolver := w.templateTc.GetModuleResolver()
+	// Importers of this document must see the buffer, not the file on disk
+	resolver.SetOverlay(path, content)
+
+	// Half-typed buffers are the norm: report the syntax errors and still
+	// check the statements that parsed
+	file, err := parser.ParseCode(content, path)
+	var errors []checker.TypeError
+	if err != nil {
+		errors = parseErrorsToTypeErrors(path, err)
+	}
+	if file == nil {
+		return errors
+	}
+
+	return append(errors, newFileChecker(w.templateTc).CheckFile(path, file)...)
+}
+
+func (w *lspWorkspace) Close(path string) {
+	if w.templateTc != nil {
+		w.templateTc.GetModuleResolver().RemoveOverlay(path)
+	}
+}
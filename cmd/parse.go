@@ -55,7 +55,7 @@ func runParse(cmd *cobra.Command, args []string) error {
 				fileCount++
 				_, parseErr := parser.ParseFile(path)
 				if parseErr != nil {
-					allErrors = append(allErrors, parseErrorToTypeError(path, parseErr))
+					allErrors = append(allErrors, parseErrorsToTypeErrors(path, parseErr)...)
 				}
 			}
 			return nil
@@ -64,7 +64,7 @@ func runParse(cmd *cobra.Command, args []string) error {
 		fileCount = 1
 		_, parseErr := parser.ParseFile(absPath)
 		if parseErr != nil {
-			allErrors = append(allErrors, parseErrorToTypeError(absPath, parseErr))
+			allErrors = append(allErrors, parseErrorsToTypeErrors(absPath, parseErr)...)
 		}
 	}
 
@@ -19,4 +19,6 @@ func init() {
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(astCmd)
 	rootCmd.AddCommand(parseCmd)
+	rootCmd.AddCommand(lspCmd)
+	rootCmd.AddCommand(showConfigCmd)
 }
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"tstypechecker/pkg/checker"
+)
+
+// SARIF 2.1.0 log, limited to the properties tscheck fills in
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifSrcRoot = "SRCROOT"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// reportErrorsSARIF prints errors as a SARIF log. Files under the working
+// directory are reported relative to it through the SRCROOT base, so the log
+// does not depend on where the repository is checked out.
+func reportErrorsSARIF(errors []checker.TypeError) {
+	cwd, _ := os.Getwd()
+	data, err := json.MarshalIndent(buildSARIFLog(errors, cwd), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode SARIF: %v\n", err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// buildSARIFLog converts errors into a single-run SARIF log with one rule per
+// diagnostic code
+func buildSARIFLog(errors []checker.TypeError, cwd string) *sarifLog {
+	// Rules are sorted by code so the log is stable between runs
+	levels := make(map[string]string)
+	for _, e := range errors {
+		if _, seen := levels[e.Code]; !seen {
+			levels[e.Code] = sarifLevel(e.Severity)
+		}
+	}
+	codes := make([]string, 0, len(levels))
+	for code := range levels {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	rules := make([]sarifRule, 0, len(codes))
+	ruleIndex := make(map[string]int, len(codes))
+	for i, code := range codes {
+		ruleIndex[code] = i
+		rules = append(rules, sarifRule{
+			ID:                   code,
+			Name:                 code,
+			DefaultConfiguration: sarifConfiguration{Level: levels[code]},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(errors))
+	for _, e := range errors {
+		results = append(results, sarifResult{
+			RuleID:    e.Code,
+			RuleIndex: ruleIndex[e.Code],
+			Level:     sarifLevel(e.Severity),
+			Message:   sarifMessage{Text: e.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifact(e.File, cwd),
+					Region:           sarifRegion{StartLine: e.Line, StartColumn: e.Column},
+				},
+			}},
+		})
+	}
+
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "tscheck", Rules: rules}},
+		Results: results,
+	}
+	if cwd != "" {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			sarifSrcRoot: {URI: fileURI(cwd) + "/"},
+		}
+	}
+
+	return &sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+}
+
+// sarifArtifact locates file relative to cwd when it is inside it and by
+// absolute file URI otherwise
+func sarifArtifact(file, cwd string) sarifArtifactLocation {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return sarifArtifactLocation{URI: filepath.ToSlash(file)}
+	}
+	if cwd != "" {
+		if rel, err := filepath.Rel(cwd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			uri := (&url.URL{Path: filepath.ToSlash(rel)}).String()
+			return sarifArtifactLocation{URI: uri, URIBaseID: sarifSrcRoot}
+		}
+	}
+	return sarifArtifactLocation{URI: fileURI(abs)}
+}
+
+// fileURI converts an absolute path to a file:// URI
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows drive paths: C:/src -> file:///C:/src
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// sarifLevel maps a diagnostic severity to a SARIF result level
+func sarifLevel(severity string) string {
+	switch severity {
+	case "error", "warning":
+		return severity
+	default:
+		return "note"
+	}
+}
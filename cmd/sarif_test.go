@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"tstypechecker/pkg/checker"
+)
+
+func TestBuildSARIFLog(t *testing.T) {
+	cwd := t.TempDir()
+	errors := []checker.TypeError{
+		{File: filepath.Join(cwd, "src", "a.ts"), Line: 3, Column: 7, Message: "Cannot find name 'x'.", Code: "TS2304", Severity: "error"},
+		{File: filepath.Join(cwd, "src", "my file.ts"), Line: 1, Column: 1, Message: "Type 'string' is not assignable to type 'number'.", Code: "TS2322", Severity: "error"},
+		{File: filepath.Join(cwd, "src", "a.ts"), Line: 5, Column: 2, Message: "Cannot find name 'y'.", Code: "TS2304", Severity: "warning"},
+	}
+
+	log := buildSARIFLog(errors, cwd)
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log header: version %q, %d runs", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+
+	rules := run.Tool.Driver.Rules
+	if len(rules) != 2 || rules[0].ID != "TS2304" || rules[1].ID != "TS2322" {
+		t.Fatalf("rules = %+v, want TS2304 and TS2322", rules)
+	}
+
+	if len(run.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(run.Results))
+	}
+	second := run.Results[1]
+	if second.RuleID != "TS2322" || second.RuleIndex != 1 {
+		t.Errorf("result rule = %s (%d), want TS2322 (1)", second.RuleID, second.RuleIndex)
+	}
+	location := second.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "src/my%20file.ts" || location.ArtifactLocation.URIBaseID != "SRCROOT" {
+		t.Errorf("artifact = %+v, want src/my%%20file.ts under SRCROOT", location.ArtifactLocation)
+	}
+	if location.Region.StartLine != 1 || location.Region.StartColumn != 1 {
+		t.Errorf("region = %+v, want 1:1", location.Region)
+	}
+	if run.Results[2].Level != "warning" {
+		t.Errorf("level = %q, want warning", run.Results[2].Level)
+	}
+}
+
+func TestBuildSARIFLogOutsideWorkingDirectory(t *testing.T) {
+	errors := []checker.TypeError{{File: "/elsewhere/b.ts", Line: 1, Column: 1, Code: "TS2304", Severity: "error"}}
+
+	log := buildSARIFLog(errors, t.TempDir())
+	artifact := log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation
+	if artifact.URI != "file:///elsewhere/b.ts" || artifact.URIBaseID != "" {
+		t.Errorf("artifact = %+v, want an absolute file URI", artifact)
+	}
+}
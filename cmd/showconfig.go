@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"tstypechecker/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var showConfigCmd = &cobra.Command{
+	Use:   "showConfig [path]",
+	Short: "Print the effective tsconfig.json",
+	Long:  `Print the configuration of a project as JSON once every config it extends is merged, along with the files it selects.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runShowConfig,
+}
+
+func runShowConfig(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+
+	// A directory means the tsconfig.json it or one of its parents holds
+	configPath := absPath
+	if info, err := os.Stat(absPath); err != nil {
+		return fmt.Errorf("cannot access path: %w", err)
+	} else if info.IsDir() {
+		configPath = filepath.Join(findProjectRoot(absPath), "tsconfig.json")
+	}
+	if _, err := os.Stat(configPath); err != nil {
+		return fmt.Errorf("cannot find a tsconfig.json file at %s", absPath)
+	}
+
+	tsConfig, err := config.LoadTSConfig(configPath)
+	if err != nil {
+		return err
+	}
+	data, err := tsConfig.ShowConfig()
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"tstypechecker/pkg/checker"
+	"tstypechecker/pkg/modules"
+)
+
+// printResolutionTraces prints how every import resolved since the last call
+// when --traceResolution is set. JSON and SARIF runs write the traces as JSON
+// to stderr so stdout stays a single document.
+func printResolutionTraces(tc *checker.TypeChecker) {
+	if !traceResolution {
+		return
+	}
+	traces := tc.ResolutionTraces()
+
+	if outputFormat == "json" || outputFormat == "sarif" {
+		if traces == nil {
+			traces = []modules.ResolutionTrace{}
+		}
+		data, _ := json.MarshalIndent(traces, "", "  ")
+		fmt.Fprintln(os.Stderr, string(data))
+		return
+	}
+
+	for _, trace := range traces {
+		fmt.Printf("%s======== Resolving module '%s' from '%s'. ========%s\n", colorCyan, trace.Specifier, displayPath(trace.FromFile), colorReset)
+		for _, step := range trace.Steps {
+			if step.Candidate == "" {
+				fmt.Printf("  [%s] %s\n", step.Rule, step.Result)
+				continue
+			}
+			fmt.Printf("  [%s] %s: %s\n", step.Rule, displayPath(step.Candidate), step.Result)
+		}
+		if trace.Resolved != "" {
+			fmt.Printf("%s======== Module name '%s' was successfully resolved to '%s'. ========%s\n", colorGreen, trace.Specifier, displayPath(trace.Resolved), colorReset)
+		} else {
+			fmt.Printf("%s======== Module name '%s' was not resolved. ========%s\n", colorRed, trace.Specifier, colorReset)
+		}
+	}
+}
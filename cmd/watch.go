@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"tstypechecker/pkg/checker"
+	"tstypechecker/pkg/config"
+	"tstypechecker/pkg/watch"
+)
+
+// watchDirectory checks dir once and then keeps the template checker alive,
+// re-checking only changed files and the files that import them
+func watchDirectory(templateTc *checker.TypeChecker, dir string, tsConfig *config.TSConfig, initDuration time.Duration) error {
+	resolver := templateTc.GetModuleResolver()
+
+	// Initial full check
+	checkStart := time.Now()
+	matcher := sourceFileMatcher(dir, tsConfig)
+	files, err := matcher.Files()
+	if err != nil {
+		return err
+	}
+	templateTc.LoadGlobalDeclarations(files)
+	errorsByFile := checkFilesParallel(templateTc, files, tsConfig)
+	printResolutionTraces(templateTc)
+	_ = reportDirectoryResults(files, errorsByFile, initDuration, time.Since(checkStart))
+
+	watcher, err := watch.New(dir)
+	if err != nil {
+		return fmt.Errorf("cannot watch %s: %w", dir, err)
+	}
+	defer watcher.Close()
+
+	fmt.Printf("\n%sWatching for file changes in %s...%s\n", colorGray, dir, colorReset)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case changed, ok := <-watcher.Events():
+			if !ok {
+				return nil
+			}
+
+			checkStart := time.Now()
+			toCheck := make(map[string]bool)
+
+			for _, path := range changed {
+				// Drop stale resolutions and parsed modules for the file
+				resolver.InvalidateFile(path)
+
+				_, statErr := os.Stat(path)
+				exists := statErr == nil
+
+				if matcher.Match(path) {
+					if exists {
+						toCheck[path] = true
+						if _, known := errorsByFile[path]; !known {
+							files = append(files, path)
+						}
+					} else {
+						// Deleted: forget its diagnostics
+						delete(errorsByFile, path)
+						files = removePath(files, path)
+					}
+				}
+
+				// Files importing the changed one may have new errors (or lose old ones)
+				for _, importer := range resolver.ImportersOf(path) {
+					if _, known := errorsByFile[importer]; known {
+						toCheck[importer] = true
+					}
+				}
+			}
+
+			if len(toCheck) == 0 {
+				continue
+			}
+
+			batch := make([]string, 0, len(toCheck))
+			for path := range toCheck {
+				batch = append(batch, path)
+			}
+			sort.Strings(batch)
+
+			for path, errs := range checkFilesParallel(templateTc, batch, tsConfig) {
+				errorsByFile[path] = errs
+			}
+			printResolutionTraces(templateTc)
+
+			fmt.Printf("\n%s[%s] Re-checked %d file(s) after %d change(s)%s\n",
+				colorGray, time.Now().Format("15:04:05"), len(batch), len(changed), colorReset)
+			_ = reportDirectoryResults(files, errorsByFile, 0, time.Since(checkStart))
+			fmt.Printf("\n%sWatching for file changes...%s\n", colorGray, colorReset)
+		}
+	}
+}
+
+// removePath returns paths without target, preserving order
+func removePath(paths []string, target string) []string {
+	result := paths[:0]
+	for _, p := range paths {
+		if p != target {
+			result = append(result, p)
+		}
+	}
+	return result
+}
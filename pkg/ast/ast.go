@@ -21,17 +21,46 @@ func (p Position) String() string {
 }
 
 type File struct {
-	Name     string
-	Source   string
-	Body     []Statement
-	Position Position
-	EndPos   Position
+	Name       string
+	Source     string
+	Body       []Statement
+	Directives []*CommentDirective // @ts-ignore, @ts-expect-error, @ts-nocheck, @ts-check
+	Position   Position
+	EndPos     Position
+
+	// Vue single file components
+	ScriptLang  string // lang attribute of the <script> blocks ("js" when absent)
+	ScriptSetup bool   // the component has a <script setup> block
 }
 
 func (f *File) Type() string  { return "File" }
 func (f *File) Pos() Position { return f.Position }
 func (f *File) End() Position { return f.EndPos }
 
+// HasDirective reports whether the file contains a comment directive of the given kind
+func (f *File) HasDirective(kind string) bool {
+	for _, d := range f.Directives {
+		if d.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Comment directive kinds
+const (
+	DirectiveIgnore      = "ts-ignore"
+	DirectiveExpectError = "ts-expect-error"
+	DirectiveNoCheck     = "ts-nocheck"
+	DirectiveCheck       = "ts-check"
+)
+
+// CommentDirective is a // @ts-... comment that changes how diagnostics are reported
+type CommentDirective struct {
+	Kind     string
+	Position Position // Start of the comment
+}
+
 type Statement interface {
 	Node
 	stmtNode()
@@ -51,6 +80,7 @@ type Declaration interface {
 type VariableDeclaration struct {
 	Kind     string // "var", "let", "const"
 	Decls    []*VariableDeclarator
+	Declare  bool // declare let x: number
 	Position Position
 	EndPos   Position
 }
@@ -64,6 +94,7 @@ type VariableDeclarator struct {
 	ID             *Identifier
 	TypeAnnotation TypeNode
 	Init           Expression
+	Definite       bool // let x!: number
 	Position       Position
 	EndPos         Position
 }
@@ -313,10 +344,12 @@ func (i *IndexSignature) typeMember()   {}
 
 // ModuleDeclaration represents an ambient module declaration (declare module 'name' { ... })
 type ModuleDeclaration struct {
-	Name     string // Module name (from string literal)
-	Body     []Statement
-	Position Position
-	EndPos   Position
+	Name      string // Module name (from string literal)
+	Global    bool   // declare global { ... }: declarations added to the global scope
+	Shorthand bool   // declare module 'name';: everything imported from it is any
+	Body      []Statement
+	Position  Position
+	EndPos    Position
 }
 
 func (m *ModuleDeclaration) Type() string  { return "ModuleDeclaration" }
@@ -621,6 +654,35 @@ func (f *ForStatement) Pos() Position { return f.Position }
 func (f *ForStatement) End() Position { return f.EndPos }
 func (f *ForStatement) stmtNode()     {}
 
+// ForOfStatement represents a for...of loop (or for await...of)
+type ForOfStatement struct {
+	Left     Node // Can be VariableDeclaration or ExpressionStatement
+	Right    Expression
+	Body     Statement
+	Await    bool
+	Position Position
+	EndPos   Position
+}
+
+func (f *ForOfStatement) Type() string  { return "ForOfStatement" }
+func (f *ForOfStatement) Pos() Position { return f.Position }
+func (f *ForOfStatement) End() Position { return f.EndPos }
+func (f *ForOfStatement) stmtNode()     {}
+
+// ForInStatement represents a for...in loop
+type ForInStatement struct {
+	Left     Node // Can be VariableDeclaration or ExpressionStatement
+	Right    Expression
+	Body     Statement
+	Position Position
+	EndPos   Position
+}
+
+func (f *ForInStatement) Type() string  { return "ForInStatement" }
+func (f *ForInStatement) Pos() Position { return f.Position }
+func (f *ForInStatement) End() Position { return f.EndPos }
+func (f *ForInStatement) stmtNode()     {}
+
 // WhileStatement represents a while loop
 type WhileStatement struct {
 	Test     Expression
@@ -634,6 +696,19 @@ func (w *WhileStatement) Pos() Position { return w.Position }
 func (w *WhileStatement) End() Position { return w.EndPos }
 func (w *WhileStatement) stmtNode()     {}
 
+// DoWhileStatement represents a do...while loop
+type DoWhileStatement struct {
+	Body     Statement
+	Test     Expression
+	Position Position
+	EndPos   Position
+}
+
+func (d *DoWhileStatement) Type() string  { return "DoWhileStatement" }
+func (d *DoWhileStatement) Pos() Position { return d.Position }
+func (d *DoWhileStatement) End() Position { return d.EndPos }
+func (d *DoWhileStatement) stmtNode()     {}
+
 // TryStatement represents try-catch-finally
 type TryStatement struct {
 	Block     *BlockStatement
@@ -803,6 +878,7 @@ type TypeParameter struct {
 	Name       *Identifier
 	Constraint TypeNode // extends clause
 	Default    TypeNode // default type
+	Const      bool     // const T: arguments are inferred as with 'as const'
 	Position   Position
 	EndPos     Position
 }
@@ -891,10 +967,11 @@ func (f *FunctionExpression) exprNode()     {}
 
 // NewExpression represents a new expression (new Class())
 type NewExpression struct {
-	Callee    Expression
-	Arguments []Expression
-	Position  Position
-	EndPos    Position
+	Callee        Expression
+	TypeArguments []TypeNode // new Map<string, number>()
+	Arguments     []Expression
+	Position      Position
+	EndPos        Position
 }
 
 func (n *NewExpression) Type() string  { return "NewExpression" }
@@ -1083,3 +1160,86 @@ func (t *TypePredicate) Type() string  { return "TypePredicate" }
 func (t *TypePredicate) Pos() Position { return t.Position }
 func (t *TypePredicate) End() Position { return t.EndPos }
 func (t *TypePredicate) typeNode()     {}
+
+// JSXElement represents <Name attr={value}>children</Name> or <Name />
+type JSXElement struct {
+	Name          Expression // Identifier (div, my-element, svg:path) or MemberExpression (Foo.Bar)
+	TypeArguments []TypeNode // <List<number> items={...} />
+	Attributes    []JSXAttributeNode
+	Children      []Expression // JSXText, JSXExpressionContainer, JSXElement or JSXFragment
+	SelfClosing   bool
+	Position      Position
+	EndPos        Position
+}
+
+func (j *JSXElement) Type() string  { return "JSXElement" }
+func (j *JSXElement) Pos() Position { return j.Position }
+func (j *JSXElement) End() Position { return j.EndPos }
+func (j *JSXElement) exprNode()     {}
+
+// JSXFragment represents <>children</>
+type JSXFragment struct {
+	Children []Expression
+	Position Position
+	EndPos   Position
+}
+
+func (j *JSXFragment) Type() string  { return "JSXFragment" }
+func (j *JSXFragment) Pos() Position { return j.Position }
+func (j *JSXFragment) End() Position { return j.EndPos }
+func (j *JSXFragment) exprNode()     {}
+
+// JSXAttributeNode is an interface for nodes that can appear in a JSX opening tag
+type JSXAttributeNode interface {
+	Node
+	jsxAttributeNode()
+}
+
+// JSXAttribute represents name="value", name={expr} or a bare name (true)
+type JSXAttribute struct {
+	Name     *Identifier
+	Value    Expression // Literal, JSXExpressionContainer, JSXElement or nil for a bare name
+	Position Position
+	EndPos   Position
+}
+
+func (j *JSXAttribute) Type() string      { return "JSXAttribute" }
+func (j *JSXAttribute) Pos() Position     { return j.Position }
+func (j *JSXAttribute) End() Position     { return j.EndPos }
+func (j *JSXAttribute) jsxAttributeNode() {}
+
+// JSXSpreadAttribute represents {...props} in a JSX opening tag
+type JSXSpreadAttribute struct {
+	Argument Expression
+	Position Position
+	EndPos   Position
+}
+
+func (j *JSXSpreadAttribute) Type() string      { return "JSXSpreadAttribute" }
+func (j *JSXSpreadAttribute) Pos() Position     { return j.Position }
+func (j *JSXSpreadAttribute) End() Position     { return j.EndPos }
+func (j *JSXSpreadAttribute) jsxAttributeNode() {}
+
+// JSXExpressionContainer represents {expr} in an attribute value or among children
+type JSXExpressionContainer struct {
+	Expression Expression // nil for an empty container such as {/* comment */}
+	Position   Position
+	EndPos     Position
+}
+
+func (j *JSXExpressionContainer) Type() string  { return "JSXExpressionContainer" }
+func (j *JSXExpressionContainer) Pos() Position { return j.Position }
+func (j *JSXExpressionContainer) End() Position { return j.EndPos }
+func (j *JSXExpressionContainer) exprNode()     {}
+
+// JSXText represents literal text between JSX tags
+type JSXText struct {
+	Value    string
+	Position Position
+	EndPos   Position
+}
+
+func (j *JSXText) Type() string  { return "JSXText" }
+func (j *JSXText) Pos() Position { return j.Position }
+func (j *JSXText) End() Position { return j.EndPos }
+func (j *JSXText) exprNode()     {}
@@ -0,0 +1,284 @@
+package ast
+
+import "reflect"
+
+// Inspect traverses the tree rooted at node in depth-first order. It calls
+// f(node) and, when f returns true, inspects every child of node and then
+// calls f(nil), like go/ast.Inspect.
+func Inspect(node Node, f func(Node) bool) {
+	if isNilNode(node) || !f(node) {
+		return
+	}
+	for _, child := range Children(node) {
+		Inspect(child, f)
+	}
+	f(nil)
+}
+
+// Children returns the direct children of node in source order, skipping
+// absent ones
+func Children(node Node) []Node {
+	var c children
+	switch n := node.(type) {
+	case *File:
+		for _, stmt := range n.Body {
+			c.add(stmt)
+		}
+	case *VariableDeclaration:
+		for _, decl := range n.Decls {
+			c.add(decl)
+		}
+	case *VariableDeclarator:
+		c.add(n.ID, n.TypeAnnotation, n.Init)
+	case *FunctionDeclaration:
+		c.add(n.ID)
+		c.types(n.TypeParameters)
+		c.params(n.Params)
+		c.add(n.ReturnType, n.Body)
+	case *BlockStatement:
+		for _, stmt := range n.Body {
+			c.add(stmt)
+		}
+	case *ReturnStatement:
+		c.add(n.Argument)
+	case *ExpressionStatement:
+		c.add(n.Expression)
+	case *IfStatement:
+		c.add(n.Test, n.Consequent, n.Alternate)
+	case *SwitchStatement:
+		c.add(n.Discriminant)
+		for _, sc := range n.Cases {
+			c.add(sc)
+		}
+	case *SwitchCase:
+		c.add(n.Test)
+		for _, stmt := range n.Consequent {
+			c.add(stmt)
+		}
+	case *ImportDeclaration:
+		for i := range n.Specifiers {
+			c.add(&n.Specifiers[i])
+		}
+		c.add(n.Source)
+	case *ImportSpecifier:
+		c.add(n.Imported, n.Local)
+	case *ExportDeclaration:
+		c.add(n.Declaration)
+		for i := range n.Specifiers {
+			c.add(&n.Specifiers[i])
+		}
+		c.add(n.Exported, n.Source)
+	case *ExportSpecifier:
+		c.add(n.Local, n.Exported)
+	case *TypeAliasDeclaration:
+		c.add(n.ID)
+		c.types(n.TypeParameters)
+		c.add(n.TypeAnnotation)
+	case *InterfaceDeclaration:
+		c.add(n.ID)
+		c.types(n.TypeParameters)
+		c.types(n.Extends)
+		for _, member := range n.Members {
+			c.add(member)
+		}
+	case InterfaceProperty:
+		c.add(n.Key, n.Value)
+	case *InterfaceProperty:
+		c.add(n.Key, n.Value)
+	case *CallSignature:
+		for i := range n.Parameters {
+			c.add(&n.Parameters[i])
+		}
+		c.add(n.ReturnType)
+	case *IndexSignature:
+		c.add(n.KeyType, n.ValueType)
+	case *ModuleDeclaration:
+		for _, stmt := range n.Body {
+			c.add(stmt)
+		}
+	case *CallExpression:
+		c.add(n.Callee)
+		c.types(n.TypeArguments)
+		c.exprs(n.Arguments)
+	case *MemberExpression:
+		c.add(n.Object, n.Property)
+	case *AsExpression:
+		c.add(n.Expression, n.TypeAnnotation)
+	case *SatisfiesExpression:
+		c.add(n.Expression, n.TypeAnnotation)
+	case *ConditionalExpression:
+		c.add(n.Test, n.Consequent, n.Alternate)
+	case *BinaryExpression:
+		c.add(n.Left, n.Right)
+	case *Parameter:
+		c.add(n.ID, n.ParamType, n.Default)
+	case *TypeReference:
+		c.types(n.TypeArguments)
+	case *UnionType:
+		c.types(n.Types)
+	case *IntersectionType:
+		c.types(n.Types)
+	case *TupleType:
+		c.types(n.Elements)
+	case *RestType:
+		c.add(n.TypeAnnotation)
+	case *FunctionType:
+		c.types(n.TypeParameters)
+		c.params(n.Params)
+		c.add(n.Return)
+	case *ObjectTypeLiteral:
+		for _, member := range n.Members {
+			c.add(member)
+		}
+	case *ArrayExpression:
+		c.exprs(n.Elements)
+	case *ObjectExpression:
+		for _, prop := range n.Properties {
+			c.add(prop)
+		}
+	case *Property:
+		c.add(n.Key, n.Value)
+	case *SpreadElement:
+		c.add(n.Argument)
+	case *ArrowFunctionExpression:
+		c.params(n.Params)
+		c.add(n.ReturnType, n.Body)
+	case *FunctionExpression:
+		c.add(n.ID)
+		c.params(n.Params)
+		c.add(n.ReturnType, n.Body)
+	case *ForStatement:
+		c.add(n.Init, n.Test, n.Update, n.Body)
+	case *ForOfStatement:
+		c.add(n.Left, n.Right, n.Body)
+	case *ForInStatement:
+		c.add(n.Left, n.Right, n.Body)
+	case *WhileStatement:
+		c.add(n.Test, n.Body)
+	case *DoWhileStatement:
+		c.add(n.Body, n.Test)
+	case *TryStatement:
+		c.add(n.Block, n.Handler, n.Finalizer)
+	case *CatchClause:
+		c.add(n.Param, n.Body)
+	case *ThrowStatement:
+		c.add(n.Argument)
+	case *BreakStatement:
+		c.add(n.Label)
+	case *ContinueStatement:
+		c.add(n.Label)
+	case *AssignmentExpression:
+		c.add(n.Left, n.Right)
+	case *UnaryExpression:
+		c.add(n.Argument)
+	case *MappedType:
+		c.add(n.TypeParameter, n.Constraint, n.NameType, n.MappedType)
+	case *ConditionalType:
+		c.add(n.CheckType, n.ExtendsType, n.TrueType, n.FalseType)
+	case *InferType:
+		c.add(n.TypeParameter)
+	case *TemplateLiteralType:
+		c.types(n.Types)
+	case *IndexedAccessType:
+		c.add(n.ObjectType, n.IndexType)
+	case *TypeParameter:
+		c.add(n.Name, n.Constraint, n.Default)
+	case *ClassDeclaration:
+		c.add(n.ID)
+		c.types(n.TypeParameters)
+		c.add(n.SuperClass)
+		c.types(n.Implements)
+		for _, member := range n.Body {
+			c.add(member)
+		}
+	case *MethodDefinition:
+		c.add(n.Key, n.Value)
+	case *PropertyDefinition:
+		c.add(n.Key, n.TypeAnnotation, n.Value)
+	case *NewExpression:
+		c.add(n.Callee)
+		c.types(n.TypeArguments)
+		c.exprs(n.Arguments)
+	case *ClassExpression:
+		c.add(n.Class)
+	case *YieldExpression:
+		c.add(n.Argument)
+	case *TaggedTemplateExpression:
+		c.add(n.Tag, n.Quasi)
+	case *TemplateLiteral:
+		c.exprs(n.Expressions)
+	case *EnumDeclaration:
+		c.add(n.Name)
+		for _, member := range n.Members {
+			c.add(member)
+		}
+	case *EnumMember:
+		c.add(n.Name, n.Value)
+	case *TypeQuery:
+		c.add(n.ExprName)
+	case *TypeOperator:
+		c.add(n.Target)
+	case *NamespaceDeclaration:
+		c.add(n.Name)
+		for _, stmt := range n.Body {
+			c.add(stmt)
+		}
+	case *TypePredicate:
+		c.add(n.ParameterName, n.TargetType)
+	case *JSXElement:
+		c.add(n.Name)
+		c.types(n.TypeArguments)
+		for _, attr := range n.Attributes {
+			c.add(attr)
+		}
+		c.exprs(n.Children)
+	case *JSXFragment:
+		c.exprs(n.Children)
+	case *JSXAttribute:
+		c.add(n.Name, n.Value)
+	case *JSXSpreadAttribute:
+		c.add(n.Argument)
+	case *JSXExpressionContainer:
+		c.add(n.Expression)
+	}
+	return c
+}
+
+// children collects the present children of a node
+type children []Node
+
+func (c *children) add(nodes ...Node) {
+	for _, node := range nodes {
+		if !isNilNode(node) {
+			*c = append(*c, node)
+		}
+	}
+}
+
+func (c *children) exprs(exprs []Expression) {
+	for _, expr := range exprs {
+		c.add(expr)
+	}
+}
+
+func (c *children) types(nodes []TypeNode) {
+	for _, node := range nodes {
+		c.add(node)
+	}
+}
+
+func (c *children) params(params []*Parameter) {
+	for _, param := range params {
+		c.add(param)
+	}
+}
+
+// isNilNode reports whether node is absent, including a nil pointer stored
+// in an interface field
+func isNilNode(node Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,116 @@
+package checker
+
+import "testing"
+
+func TestAmbientModules(t *testing.T) {
+	files := map[string]string{
+		"node_modules/lib-a/package.json": `{"name": "lib-a", "types": "index.d.ts"}`,
+		"node_modules/lib-a/index.d.ts": `export interface Options { debug: boolean }
+export function create(options: Options): string;
+`,
+		"env.d.ts": `declare module "*.svg" {
+  const src: string;
+  export default src;
+}
+declare module "untyped-lib";
+declare module "config-lib" {
+  export const port: number;
+}
+`,
+		"augment.ts": `declare module "lib-a" {
+  interface Options { verbose: boolean }
+  export function extra(): number;
+}
+export {};
+`,
+		"node_modules/lib-b/package.json": `{"name": "lib-b", "types": "index.d.ts"}`,
+		"node_modules/lib-b/index.d.ts": `export interface Options { a: number }
+export declare function create(options: Options): string;
+`,
+		"augment-b.ts": `declare module "lib-b" {
+  interface Options { extra: string }
+}
+export {};
+`,
+		"augment-config.ts": `declare module "config-lib" {
+  export const host: string;
+}
+declare module "ghost-lib" {
+  export const ghost: number;
+}
+export {};
+`,
+	}
+	runProjectCases(t, files, []diagnosticCase{
+		{
+			name: "wildcard pattern",
+			code: `import logo from "./logo.svg";
+import missing from "./missing.png";
+const size: number = logo;
+`,
+			want: []diagnostic{
+				{line: 2, code: "TS2307"}, // no declaration matches .png
+				{line: 3, code: "TS2322"}, // *.svg exports a string
+			},
+		},
+		{
+			name: "shorthand declaration",
+			code: `import anything, { whatever } from "untyped-lib";
+anything.run(whatever);
+`,
+		},
+		{
+			name: "declared module",
+			code: `import { port } from "config-lib";
+const name: string = port;
+`,
+			want: []diagnostic{
+				{line: 2, code: "TS2322"}, // port is a number
+			},
+		},
+		{
+			name: "augmentation of a declared module",
+			code: `import { host, port } from "config-lib";
+const address: number = host;
+`,
+			want: []diagnostic{
+				{line: 2, code: "TS2322"}, // the augmentation adds host to config-lib
+			},
+		},
+		{
+			// Only scripts declare ambient modules
+			name: "augmentation of a missing module",
+			code: `import { ghost } from "ghost-lib";
+declare module "phantom-lib" {
+  export const phantom: number;
+}
+export const value = ghost;
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS2307"},
+				{line: 2, code: "TS2664", message: "Invalid module name in augmentation, module 'phantom-lib' cannot be found."},
+			},
+		},
+		{
+			name: "augmentation",
+			code: `import { create, extra, Options } from "lib-a";
+const options: Options = { debug: true, verbose: true };
+const partial: Options = { debug: true };
+const count: string = extra();
+create(options);
+`,
+			want: []diagnostic{
+				{line: 3, code: "TS2322"}, // the augmentation adds verbose to Options
+				{line: 4, code: "TS2322"}, // the augmentation adds extra to lib-a
+			},
+		},
+		{
+			// The augmentation alone must not stand for an interface that
+			// exists in a package the parser could not read
+			name: "augmentation of an export declare package",
+			code: `import { Options } from "lib-b";
+const options: Options = { a: 1, extra: "x" };
+`,
+		},
+	})
+}
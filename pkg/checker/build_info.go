@@ -0,0 +1,255 @@
+package checker
+
+import (
+	"encoding/gob"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"tstypechecker/pkg/modules"
+)
+
+// buildInfoVersion is bumped whenever the cache format or checker output changes
+const buildInfoVersion = "3"
+
+// BuildInfoFileName is the incremental cache file written under the project root
+const BuildInfoFileName = ".tscheck.buildinfo"
+
+// BuildInfo is the persisted state of an incremental check (tsbuildinfo equivalent)
+type BuildInfo struct {
+	Version     string
+	OptionsHash string                    // Hash of the options the diagnostics were produced with
+	Files       map[string]*FileBuildInfo // Keyed by absolute file path
+
+	// Declaration files loaded outside imports -> content hash at check time
+	Declarations map[string]string
+}
+
+// FileBuildInfo records what a file looked like when it was last checked
+type FileBuildInfo struct {
+	Hash        string            // Content hash of the file
+	Imports     map[string]string // Resolved import path -> content hash at check time
+	Unresolved  []string          // Imported specifiers that did not resolve at check time
+	Diagnostics []TypeError
+}
+
+// IncrementalCache decides which files can be skipped and replays their diagnostics
+type IncrementalCache struct {
+	path   string
+	info   *BuildInfo
+	mu     sync.Mutex
+	hashes map[string]string // Current content hashes, computed lazily
+	fresh  map[string]bool   // Memoized up-to-date results for this run
+
+	// Resolves the specifiers that did not resolve when a file was recorded
+	resolver *modules.ModuleResolver
+}
+
+// LoadIncrementalCache reads the cache at path. A missing, unreadable or
+// outdated cache (different version or options) yields an empty one.
+func LoadIncrementalCache(path string, optionsHash string) *IncrementalCache {
+	cache := &IncrementalCache{
+		path:   path,
+		hashes: make(map[string]string),
+		fresh:  make(map[string]bool),
+	}
+
+	if f, err := os.Open(path); err == nil {
+		var info BuildInfo
+		if gob.NewDecoder(f).Decode(&info) == nil &&
+			info.Version == buildInfoVersion && info.OptionsHash == optionsHash && info.Files != nil {
+			cache.info = &info
+		}
+		f.Close()
+	}
+
+	if cache.info == nil {
+		cache.info = &BuildInfo{
+			Version:     buildInfoVersion,
+			OptionsHash: optionsHash,
+			Files:       make(map[string]*FileBuildInfo),
+		}
+	}
+
+	return cache
+}
+
+// SetModuleResolver sets the resolver that tells whether imports that did not
+// resolve when a file was recorded resolve now. Without one they are assumed
+// to still fail.
+func (c *IncrementalCache) SetModuleResolver(resolver *modules.ModuleResolver) {
+	c.resolver = resolver
+}
+
+// SetDeclarationFiles records the declaration files the program loads outside
+// imports, such as lib files, node_modules types and global .d.ts files. Every
+// file sees their declarations, so if any of them changed, appeared or
+// disappeared since the cache was written, no file is up to date.
+func (c *IncrementalCache) SetDeclarationFiles(files []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hashes := make(map[string]string, len(files))
+	for _, file := range files {
+		hashes[file] = c.currentHash(file)
+	}
+	if !maps.Equal(hashes, c.info.Declarations) {
+		c.info.Files = make(map[string]*FileBuildInfo)
+		c.fresh = make(map[string]bool)
+	}
+	c.info.Declarations = hashes
+}
+
+// Lookup returns the cached diagnostics of file when neither the file nor any
+// of its transitive dependencies changed since it was recorded
+func (c *IncrementalCache) Lookup(file string) ([]TypeError, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.info.Files[file]
+	if !ok {
+		return nil, false
+	}
+	if upToDate, _ := c.isUpToDate(file, make(map[string]int)); !upToDate {
+		return nil, false
+	}
+	return entry.Diagnostics, true
+}
+
+// noCycle is the depth isUpToDate returns when its answer does not depend on
+// a file still being visited
+const noCycle = int(^uint(0) >> 1)
+
+// isUpToDate compares the recorded hashes of file and its imports with the
+// current ones, following imports that are themselves recorded. visiting maps
+// the files being visited to their depth. A file reached again through an
+// import cycle is assumed up to date until its own visit finishes, so the
+// returned depth is that of the shallowest file the answer assumed up to date;
+// the answer is only memoized once that file is file itself.
+func (c *IncrementalCache) isUpToDate(file string, visiting map[string]int) (bool, int) {
+	if result, ok := c.fresh[file]; ok {
+		return result, noCycle
+	}
+	if depth, ok := visiting[file]; ok {
+		return true, depth
+	}
+	depth := len(visiting)
+	visiting[file] = depth
+
+	low := noCycle
+	entry := c.info.Files[file]
+	upToDate := entry != nil && c.currentHash(file) == entry.Hash && !c.resolvesNow(file, entry.Unresolved)
+	if upToDate {
+		for dep, hash := range entry.Imports {
+			if c.currentHash(dep) != hash {
+				upToDate = false
+				break
+			}
+			if _, recorded := c.info.Files[dep]; recorded {
+				depUpToDate, depLow := c.isUpToDate(dep, visiting)
+				low = min(low, depLow)
+				if !depUpToDate {
+					upToDate = false
+					break
+				}
+			}
+		}
+	}
+
+	delete(visiting, file)
+	// A stale file is stale whatever the rest of its cycle turns out to be
+	if !upToDate || low >= depth {
+		c.fresh[file] = upToDate
+		low = noCycle
+	}
+	return upToDate, low
+}
+
+// resolvesNow reports whether any of the specifiers file failed to import
+// resolves now, for instance because the missing module was created
+func (c *IncrementalCache) resolvesNow(file string, specifiers []string) bool {
+	if c.resolver == nil {
+		return false
+	}
+	for _, specifier := range specifiers {
+		if _, err := c.resolver.ResolveModule(specifier, file); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Record stores the result of checking file together with its resolved
+// imports and the specifiers it imports that did not resolve
+func (c *IncrementalCache) Record(file string, imports []string, unresolved []string, diagnostics []TypeError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The file was just re-read from disk, so drop any hash computed before the check
+	delete(c.hashes, file)
+	delete(c.fresh, file)
+
+	importHashes := make(map[string]string, len(imports))
+	for _, dep := range imports {
+		importHashes[dep] = c.currentHash(dep)
+	}
+
+	c.info.Files[file] = &FileBuildInfo{
+		Hash:        c.currentHash(file),
+		Imports:     importHashes,
+		Unresolved:  unresolved,
+		Diagnostics: diagnostics,
+	}
+}
+
+// Prune forgets every file that is not in files (deleted or excluded)
+func (c *IncrementalCache) Prune(files []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keep := make(map[string]bool, len(files))
+	for _, f := range files {
+		keep[f] = true
+	}
+	for f := range c.info.Files {
+		if !keep[f] {
+			delete(c.info.Files, f)
+		}
+	}
+}
+
+// Save writes the cache atomically to its path
+func (c *IncrementalCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".tscheck-buildinfo-*")
+	if err != nil {
+		return err
+	}
+	tmp.Chmod(0644)
+	if err := gob.NewEncoder(tmp).Encode(c.info); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// currentHash returns the content hash of path, or "" if it cannot be read
+func (c *IncrementalCache) currentHash(path string) string {
+	if hash, ok := c.hashes[path]; ok {
+		return hash
+	}
+	hash := ""
+	if content, err := os.ReadFile(path); err == nil {
+		hash = modules.SharedGlobalCache.CalculateHash(content)
+	}
+	c.hashes[path] = hash
+	return hash
+}
@@ -0,0 +1,158 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tstypechecker/pkg/modules"
+	"tstypechecker/pkg/symbols"
+)
+
+func TestIncrementalCacheTracksTransitiveDependencies(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.ts")
+	b := filepath.Join(dir, "b.ts")
+	c := filepath.Join(dir, "c.ts")
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(a, "import { b } from './b';")
+	write(b, "import { c } from './c'; export const b = c;")
+	write(c, "export const c = 1;")
+
+	cachePath := filepath.Join(dir, BuildInfoFileName)
+	cache := LoadIncrementalCache(cachePath, "opts")
+	diag := []TypeError{{File: a, Line: 1, Column: 1, Message: "boom", Code: "TS2304", Severity: "error"}}
+	cache.Record(a, []string{b}, nil, diag)
+	cache.Record(b, []string{c}, nil, nil)
+	cache.Record(c, nil, nil, nil)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	cache = LoadIncrementalCache(cachePath, "opts")
+	replayed, ok := cache.Lookup(a)
+	if !ok || len(replayed) != 1 || replayed[0].Message != "boom" {
+		t.Fatalf("expected cached diagnostics for unchanged file, got %v (ok=%v)", replayed, ok)
+	}
+
+	// Changing a transitive dependency invalidates the importer
+	write(c, "export const c = 2;")
+	cache = LoadIncrementalCache(cachePath, "opts")
+	if _, ok := cache.Lookup(a); ok {
+		t.Error("expected a.ts to be stale after c.ts changed")
+	}
+
+	// Different options discard the cache entirely
+	write(c, "export const c = 1;")
+	cache = LoadIncrementalCache(cachePath, "other")
+	if _, ok := cache.Lookup(c); ok {
+		t.Error("expected cache to be ignored when options change")
+	}
+}
+
+func TestIncrementalCacheImportCycle(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.ts")
+	b := filepath.Join(dir, "b.ts")
+	x := filepath.Join(dir, "x.ts")
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(a, "import { b } from './b'; import { x } from './x'; export const a = 1;")
+	write(b, "import { a } from './a'; export const b = a;")
+	write(x, "export const x = 1;")
+
+	cachePath := filepath.Join(dir, BuildInfoFileName)
+	cache := LoadIncrementalCache(cachePath, "opts")
+	cache.Record(a, []string{b, x}, nil, nil)
+	cache.Record(b, []string{a}, nil, nil)
+	cache.Record(x, nil, nil, nil)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// b reaches the stale x only through a, which imports it back
+	write(x, "export const x = 2;")
+	cache = LoadIncrementalCache(cachePath, "opts")
+	if _, ok := cache.Lookup(a); ok {
+		t.Error("expected a.ts to be stale after x.ts changed")
+	}
+	if _, ok := cache.Lookup(b); ok {
+		t.Error("expected b.ts to be stale after x.ts changed")
+	}
+}
+
+func TestIncrementalCacheUnresolvedImports(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.ts")
+	if err := os.WriteFile(a, []byte("import { b } from './b';"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cachePath := filepath.Join(dir, BuildInfoFileName)
+	cache := LoadIncrementalCache(cachePath, "opts")
+	diag := []TypeError{{File: a, Line: 1, Column: 1, Message: "Cannot find module './b'", Code: "TS2307", Severity: "error"}}
+	cache.Record(a, nil, []string{"./b"}, diag)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	cache = LoadIncrementalCache(cachePath, "opts")
+	cache.SetModuleResolver(modules.NewModuleResolver(dir, symbols.NewSymbolTable()))
+	if _, ok := cache.Lookup(a); !ok {
+		t.Fatal("expected cached diagnostics while ./b is missing")
+	}
+
+	// Creating the missing module invalidates the importer
+	if err := os.WriteFile(filepath.Join(dir, "b.ts"), []byte("export const b = 1;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cache = LoadIncrementalCache(cachePath, "opts")
+	cache.SetModuleResolver(modules.NewModuleResolver(dir, symbols.NewSymbolTable()))
+	if _, ok := cache.Lookup(a); ok {
+		t.Error("expected a.ts to be stale after ./b was created")
+	}
+}
+
+func TestIncrementalCacheGlobalDeclarations(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.ts")
+	globals := filepath.Join(dir, "globals.d.ts")
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(a, "const settings: Settings = { theme: 1 };")
+	write(globals, "interface Settings { theme: string }")
+
+	// a.ts sees globals.d.ts without importing it
+	run := func() *IncrementalCache {
+		tc := NewWithModuleResolver(dir)
+		tc.LoadGlobalDeclarations([]string{a, globals})
+		cache := LoadIncrementalCache(filepath.Join(dir, BuildInfoFileName), "opts")
+		cache.SetDeclarationFiles(tc.DeclarationFiles())
+		return cache
+	}
+
+	cache := run()
+	cache.Record(a, nil, nil, []TypeError{{File: a, Line: 1, Column: 7, Message: "boom", Code: "TS2322", Severity: "error"}})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, ok := run().Lookup(a); !ok {
+		t.Fatal("expected cached diagnostics while globals.d.ts is unchanged")
+	}
+
+	// Editing the global declaration invalidates every file
+	write(globals, "interface Settings { theme: number }")
+	if _, ok := run().Lookup(a); ok {
+		t.Error("expected a.ts to be stale after globals.d.ts changed")
+	}
+}
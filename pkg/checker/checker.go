@@ -3,6 +3,7 @@ package checker
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,8 +33,9 @@ type TypeChecker struct {
 	destructuringInfer *DestructuringInferencer // Inferencer for destructured parameters
 	currentFunction    ast.Node                 // Track current function for return type checking (FunctionDeclaration, FunctionExpression, ArrowFunctionExpression)
 	config             *CompilerConfig          // Compiler configuration
-	typeGuards         map[string]bool          // Track variables under type guards (instanceof Function)
+	typeGuards         map[string]bool          // Track variables under type guards (typeof x === "function")
 	loadedLibFiles     map[string]bool          // Track loaded lib files to avoid duplicates
+	declarationFiles   map[string]bool          // Files whose declarations were loaded outside imports
 	pkgTypeCache       *TypeCache               // Cache for package types
 	loadStats          *LoadStats               // Statistics for type loading
 	lazyLibMap         map[string]string        // Map of global symbol name -> lib file name
@@ -49,6 +51,8 @@ type TypeChecker struct {
 	restValidator        *RestParameterValidator
 	typeNarrowing        *TypeNarrowing
 	controlFlowNarrowing *ControlFlowNarrowing
+	jsx                  *jsxNamespace // JSX namespace of the current .tsx/.jsx file
+	jsxLibraries         *jsxLibraryCache
 }
 
 // CompilerConfig holds the compiler options for type checking
@@ -63,10 +67,11 @@ type CompilerConfig struct {
 	StrictBindCallApply          bool
 	StrictPropertyInitialization bool
 	AlwaysStrict                 bool
-	AllowUnreachableCode         bool
+	AllowUnreachableCode         *bool // nil reports unreachable code as a warning
 	AllowUnusedLabels            bool
 	NoFallthroughCasesInSwitch   bool
 	NoUncheckedIndexedAccess     bool
+	CheckJs                      bool
 }
 
 // TypeError represents a type checking error
@@ -110,6 +115,7 @@ func New() *TypeChecker {
 		lazyLibMap:         getCommonGlobalMap(),
 		profiler:           NewPerformanceProfiler(),
 		conversionStack:    make(map[ast.TypeNode]bool),
+		jsxLibraries:       newJSXLibraryCache(),
 	}
 
 	// Initialize validators
@@ -138,10 +144,11 @@ func getDefaultConfig() *CompilerConfig {
 		StrictBindCallApply:          false,
 		StrictPropertyInitialization: false,
 		AlwaysStrict:                 false,
-		AllowUnreachableCode:         true,
+		AllowUnreachableCode:         nil,
 		AllowUnusedLabels:            true,
 		NoFallthroughCasesInSwitch:   false,
 		NoUncheckedIndexedAccess:     false,
+		CheckJs:                      false,
 	}
 }
 
@@ -175,6 +182,7 @@ func NewWithModuleResolver(rootDir string) *TypeChecker {
 		lazyLibMap:         getCommonGlobalMap(),
 		profiler:           NewPerformanceProfiler(),
 		conversionStack:    make(map[ast.TypeNode]bool),
+		jsxLibraries:       newJSXLibraryCache(),
 	}
 
 	// Start profiling if enabled
@@ -248,6 +256,7 @@ func NewWithSharedModuleResolver(resolver *modules.ModuleResolver) *TypeChecker
 		loadedLibFiles:     make(map[string]bool),
 		profiler:           NewPerformanceProfiler(),
 		conversionStack:    make(map[ast.TypeNode]bool),
+		jsxLibraries:       newJSXLibraryCache(),
 	}
 
 	// Note: Types are NOT loaded here to avoid redundant I/O in worker threads.
@@ -273,21 +282,13 @@ func (tc *TypeChecker) CheckFile(filename string, file *ast.File) []TypeError {
 	tc.symbolTable.ClearErrors()
 	tc.currentFile = filename
 
-	// Check if file is a module (has imports or exports)
-	isModule := false
-	for _, stmt := range file.Body {
-		if _, ok := stmt.(*ast.ImportDeclaration); ok {
-			isModule = true
-			break
-		}
-		if _, ok := stmt.(*ast.ExportDeclaration); ok {
-			isModule = true
-			break
-		}
+	// Files opted out with // @ts-nocheck (or unchecked JS files) report nothing
+	if !tc.shouldCheckFile(file, filename) {
+		return tc.errors
 	}
 
-	// If it is a module, create a new scope
-	if isModule {
+	// If it is a module (has imports or exports), create a new scope
+	if isModuleFile(file) {
 		tc.symbolTable.EnterScope(file)
 		defer tc.symbolTable.ExitScope()
 	}
@@ -297,6 +298,9 @@ func (tc *TypeChecker) CheckFile(filename string, file *ast.File) []TypeError {
 	binder.SetParameterTypeInferencer(tc.destructuringInfer)
 	binder.BindFile(file)
 
+	// Unused declarations are found before imports replace the bound symbols
+	unused := tc.unusedDeclarations(file, filename, tc.symbolTable.Current)
+
 	// Load TypeScript lib files on first check (lazy loading)
 	// This ensures standard JavaScript globals like Intl, Promise, etc. are available
 	if len(tc.loadedLibFiles) == 0 {
@@ -306,13 +310,27 @@ func (tc *TypeChecker) CheckFile(filename string, file *ast.File) []TypeError {
 		tc.LoadTypeScriptLibsWithSnapshot([]string{"ES2020", "DOM"})
 	}
 
-	// Process imports and add imported symbols to the symbol table
+	// Process imports and add imported symbols to the symbol table, after
+	// the modules the file declares or augments
 	if tc.moduleResolver != nil {
+		tc.declareModules(filename, file)
 		tc.processImports(file, filename)
 	}
 
+	// Resolve JSX.IntrinsicElements and JSX.Element for .tsx and .jsx files
+	tc.loadJSXNamespace(file, filename)
+
+	// Declare the <script setup> compiler macros of .vue files
+	tc.loadVueMacros(file, filename)
+
 	// Perform additional type checking
 	tc.checkFile(file, filename)
+	tc.checkControlFlow(file, filename)
+	tc.checkDefiniteAssignment(file, filename)
+	tc.errors = append(tc.errors, unused...)
+
+	// Honor // @ts-ignore and // @ts-expect-error comments
+	tc.errors = tc.applyCommentDirectives(file, filename, tc.errors)
 
 	return tc.errors
 }
@@ -388,9 +406,20 @@ func (tc *TypeChecker) checkExpression(expr ast.Expression, filename string) {
 	case *ast.MemberExpression:
 		tc.checkMemberExpression(e, filename)
 	case *ast.BinaryExpression:
-		// Check both operands
+		// Check both operands. The right operand of && and || only runs when
+		// the left one is truthy or falsy, narrowed accordingly.
 		tc.checkExpression(e.Left, filename)
-		tc.checkExpression(e.Right, filename)
+		if e.Operator == "&&" || e.Operator == "||" {
+			thenNarrowing, elseNarrowing := tc.typeNarrowing.AnalyzeCondition(e.Left)
+			if e.Operator == "||" {
+				thenNarrowing = elseNarrowing
+			}
+			restore := tc.typeNarrowing.ApplyNarrowing(thenNarrowing)
+			tc.checkExpression(e.Right, filename)
+			restore()
+		} else {
+			tc.checkExpression(e.Right, filename)
+		}
 
 		// For now, we don't do type checking on binary expressions
 		// In a full implementation, we would check if the types are compatible
@@ -447,6 +476,15 @@ func (tc *TypeChecker) checkExpression(expr ast.Expression, filename string) {
 		return
 	case *ast.SatisfiesExpression:
 		tc.checkSatisfiesExpression(e, filename)
+	case *ast.JSXElement:
+		tc.checkJSXElement(e, filename)
+	case *ast.JSXFragment:
+		tc.checkJSXChildren(e.Children, filename)
+	case *ast.JSXExpressionContainer:
+		tc.checkExpression(e.Expression, filename)
+	case *ast.JSXText:
+		// Text between tags is always valid
+		return
 	case *ast.AsExpression:
 		// Check the underlying expression
 		tc.checkExpression(e.Expression, filename)
@@ -513,6 +551,7 @@ func (tc *TypeChecker) checkAssignmentExpression(assign *ast.AssignmentExpressio
 							tc.addError(filename, assign.Left.Pos().Line, assign.Left.Pos().Column,
 								fmt.Sprintf("Cannot assign to '%s' because it is a read-only property.", prop.Name),
 								"TS2540", "error")
+							return
 						}
 					}
 				}
@@ -523,13 +562,28 @@ func (tc *TypeChecker) checkAssignmentExpression(assign *ast.AssignmentExpressio
 				tc.addError(filename, assign.Left.Pos().Line, assign.Left.Pos().Column,
 					"Index signature in type 'readonly any[]' only permits reading.",
 					"TS2542", "error")
+				return
+			}
+			// Handle readonly tuple element assignment: tuple[0] = val
+			if index, ok := member.Property.(*ast.Literal); ok && objType.Kind == types.TupleType && objType.IsReadonly {
+				tc.addError(filename, assign.Left.Pos().Line, assign.Left.Pos().Column,
+					fmt.Sprintf("Cannot assign to '%v' because it is a read-only property.", index.Value),
+					"TS2540", "error")
+				return
 			}
 		}
 	}
 
 	// Type checking - verify that right is assignable to left (only for simple assignments)
+	leftId, isVariable := assign.Left.(*ast.Identifier)
 	if assign.Operator == "=" {
 		leftType := tc.getExpressionType(assign.Left)
+		// A variable takes any value of its declared type, whatever it was narrowed to
+		if isVariable {
+			if declared := tc.typeNarrowing.declaredType(leftId.Name); declared != nil {
+				leftType = declared
+			}
+		}
 
 		var rightType *types.Type
 		if tc.needsLiteralType(leftType) {
@@ -565,8 +619,13 @@ func (tc *TypeChecker) checkAssignmentExpression(assign *ast.AssignmentExpressio
 			tc.addError(filename, assign.Right.Pos().Line, assign.Right.Pos().Column, msg, "TS2322", "error")
 		}
 
-		// Note: We don't update the type cache here because in TypeScript,
-		// a variable's type is fixed at declaration and cannot be changed by assignment
+		// The declared type of a variable is fixed, but until the next
+		// assignment it is narrowed to the members the value belongs to
+		if isVariable {
+			tc.typeNarrowing.NarrowByAssignment(leftId.Name, rightType)
+		}
+	} else if isVariable {
+		tc.typeNarrowing.WidenAssigned(assign)
 	}
 	// For compound assignments (+=, -=, etc.), we skip type checking for now
 	// In a full implementation, we would check operator compatibility
@@ -585,14 +644,20 @@ func (tc *TypeChecker) checkConditionalExpression(cond *ast.ConditionalExpressio
 		tc.checkExpression(cond.Test, filename)
 	}
 
+	thenNarrowing, elseNarrowing := tc.typeNarrowing.AnalyzeCondition(cond.Test)
+
 	// Check the consequent expression (true branch)
 	if cond.Consequent != nil {
+		restore := tc.typeNarrowing.ApplyNarrowing(thenNarrowing)
 		tc.checkExpression(cond.Consequent, filename)
+		restore()
 	}
 
 	// Check the alternate expression (false branch)
 	if cond.Alternate != nil {
+		restore := tc.typeNarrowing.ApplyNarrowing(elseNarrowing)
 		tc.checkExpression(cond.Alternate, filename)
+		restore()
 	}
 }
 
@@ -760,7 +825,7 @@ func (tc *TypeChecker) checkCallExpression(call *ast.CallExpression, filename st
 				return
 			}
 
-			// Check if variable is under a type guard (instanceof Function)
+			// Check if variable is under a type guard (typeof x === "function")
 			isUnderTypeGuard := tc.typeGuards[id.Name]
 
 			// Skip callability check for parameters - they may have function types
@@ -787,6 +852,8 @@ func (tc *TypeChecker) checkCallExpression(call *ast.CallExpression, filename st
 					msg := fmt.Sprintf("This expression is not callable. Type '%s' has no call signatures.", id.Name)
 					msg += "\n  Sugerencia: Verifica que estés llamando a una función y no a una variable"
 					tc.addError(filename, call.Pos().Line, call.Pos().Column, msg, "TS2349", "error")
+				} else if symbolType.Kind == types.ObjectType && len(symbolType.CallSignatures) > 0 {
+					tc.checkCallSignatureArguments(call, symbolType.CallSignatures, filename)
 				}
 			} else {
 				// Check parameter count and types
@@ -1069,6 +1136,55 @@ func (tc *TypeChecker) checkArgumentTypes(args []ast.Expression, params []*ast.P
 	}
 }
 
+// checkCallSignatureArguments validates a call through a value typed by call
+// signatures, such as the emit function of a Vue component. With several
+// signatures the call must match one of them.
+func (tc *TypeChecker) checkCallSignatureArguments(call *ast.CallExpression, signatures []*types.Type, filename string) {
+	var firstMismatch ast.Expression
+	var firstActual, firstExpected *types.Type
+	for _, sig := range signatures {
+		if len(call.Arguments) > len(sig.Parameters) {
+			continue
+		}
+		matches := true
+		for i, arg := range call.Arguments {
+			expected := sig.Parameters[i]
+			if expected == nil || expected.Kind == types.AnyType || expected.Kind == types.TypeParameterType {
+				continue
+			}
+			var actual *types.Type
+			if tc.needsLiteralType(expected) {
+				actual = tc.inferLiteralType(arg)
+			} else {
+				actual = tc.inferencer.InferType(arg)
+			}
+			if actual.Kind == types.AnyType || actual.Kind == types.UnknownType || tc.isAssignableTo(actual, expected) {
+				continue
+			}
+			matches = false
+			if firstMismatch == nil {
+				firstMismatch, firstActual, firstExpected = arg, actual, expected
+			}
+			break
+		}
+		if matches {
+			return
+		}
+	}
+
+	if len(signatures) > 1 {
+		tc.addError(filename, call.Pos().Line, call.Pos().Column, "No overload matches this call.", "TS2769", "error")
+		return
+	}
+	if firstMismatch == nil {
+		msg := fmt.Sprintf("Expected %d arguments, but got %d.", len(signatures[0].Parameters), len(call.Arguments))
+		tc.addError(filename, call.Pos().Line, call.Pos().Column, msg, "TS2554", "error")
+		return
+	}
+	msg := fmt.Sprintf("Argument of type '%s' is not assignable to parameter of type '%s'.", firstActual.String(), firstExpected.String())
+	tc.addError(filename, firstMismatch.Pos().Line, firstMismatch.Pos().Column, msg, "TS2345", "error")
+}
+
 func (tc *TypeChecker) checkArrowFunction(arrow *ast.ArrowFunctionExpression, filename string) {
 	// Check if async arrow function is used without Promise support
 	if arrow.Async {
@@ -1550,6 +1666,9 @@ func (tc *TypeChecker) CopyGlobalTypesFrom(source *TypeChecker) {
 	for path, loaded := range source.loadedLibFiles {
 		tc.loadedLibFiles[path] = loaded
 	}
+
+	// Share the parsed React declarations
+	tc.jsxLibraries = source.jsxLibraries
 }
 
 // Clear releases memory by clearing internal caches.
@@ -1893,10 +2012,10 @@ func (tc *TypeChecker) processImportWithModule(importDecl *ast.ImportDeclaration
 					if symbol.UpdateCache != nil {
 						symbol.UpdateCache(resolvedType)
 					}
-				} else if _, ok := symbol.Node.(*ast.InterfaceDeclaration); ok {
-					// For interfaces, create an object type placeholder
-					// In a full implementation, we would parse the interface body
-					objType := types.NewObjectType(name, nil)
+				} else if interfaceDecl, ok := symbol.Node.(*ast.InterfaceDeclaration); ok {
+					// The members of the interface, with those augmentations of
+					// its module add
+					objType := tc.convertInterfaceToType(interfaceDecl)
 					tc.typeAliasCache[name] = objType
 					if symbol.UpdateCache != nil {
 						symbol.UpdateCache(objType)
@@ -2066,6 +2185,26 @@ func normalizeLiteralValue(value interface{}) interface{} {
 	return value
 }
 
+// literalValuesEqual compares the values of two literal types. A number
+// literal inferred with 'as const' has a float64 value, while one written in
+// the source keeps its text.
+func literalValuesEqual(a, b interface{}) bool {
+	a, b = normalizeLiteralValue(a), normalizeLiteralValue(b)
+	if a == b {
+		return true
+	}
+	if text, ok := a.(string); ok {
+		a, b = b, text
+	}
+	number, isNumber := a.(float64)
+	text, isText := b.(string)
+	if !isNumber || !isText {
+		return false
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	return err == nil && value == number
+}
+
 // isAssignableTo checks if sourceType can be assigned to targetType
 func (tc *TypeChecker) isAssignableTo(sourceType, targetType *types.Type) bool {
 	// Check cache first for performance
@@ -2105,6 +2244,11 @@ func (tc *TypeChecker) isAssignableToUncached(sourceType, targetType *types.Type
 		return true
 	}
 
+	// Never has no values, so it is assignable to every type
+	if sourceType.Kind == types.NeverType {
+		return true
+	}
+
 	// IntersectionType handling
 
 	// Case 1: Source is IntersectionType (A & B)
@@ -2225,9 +2369,7 @@ func (tc *TypeChecker) isAssignableToUncached(sourceType, targetType *types.Type
 		if sourceType.Kind == types.LiteralType {
 			// Normalize both values to handle quote inconsistencies
 			// (ast.Literal stores "red", ast.LiteralType stores "\"red\"")
-			normalizedSource := normalizeLiteralValue(sourceType.Value)
-			normalizedTarget := normalizeLiteralValue(targetType.Value)
-			return normalizedSource == normalizedTarget
+			return literalValuesEqual(sourceType.Value, targetType.Value)
 		}
 
 		// For object types, check structural compatibility
@@ -2428,6 +2570,15 @@ func (tc *TypeChecker) inferLiteralType(expr ast.Expression) *types.Type {
 		}
 	}
 
+	// Negative number literals: -1
+	if unary, ok := expr.(*ast.UnaryExpression); ok && unary.Operator == "-" {
+		if lit, ok := unary.Argument.(*ast.Literal); ok {
+			if text, ok := lit.Value.(string); ok && text != "" && lit.Raw == text && text[0] >= '0' && text[0] <= '9' {
+				return types.NewLiteralType("-" + text)
+			}
+		}
+	}
+
 	// Handle object expressions with literal property values (for discriminated unions)
 	if objExpr, ok := expr.(*ast.ObjectExpression); ok {
 		properties := make(map[string]*types.Type)
@@ -3025,10 +3176,10 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 			typeName := strings.TrimPrefix(t.Name, "keyof ")
 			if symbol, exists := tc.symbolTable.ResolveSymbol(typeName); exists {
 				if symbol.Type == symbols.InterfaceSymbol && symbol.Node != nil {
-					if interfaceDecl, ok := symbol.Node.(*ast.InterfaceDeclaration); ok {
+					if _, ok := symbol.Node.(*ast.InterfaceDeclaration); ok {
 						// Create a union of string literals for each property key
 						var unionTypes []*types.Type
-						for _, member := range interfaceDecl.Members {
+						for _, member := range mergedMembers(tc.interfaceDeclarations(symbol)) {
 							if prop, ok := member.(ast.InterfaceProperty); ok {
 								unionTypes = append(unionTypes, types.NewLiteralType(prop.Key.Name))
 							}
@@ -3038,6 +3189,14 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 						}
 					}
 				}
+				// keyof Routes where type Routes = typeof routes
+				if symbol.Type == symbols.TypeAliasSymbol && symbol.Node != nil {
+					if aliasDecl, ok := symbol.Node.(*ast.TypeAliasDeclaration); ok && len(aliasDecl.TypeParameters) == 0 {
+						if keys := keyofType(tc.convertTypeNode(aliasDecl.TypeAnnotation)); keys.Kind != types.KeyOfType {
+							return keys
+						}
+					}
+				}
 			}
 			// Fallback if resolution fails - create a KeyOfType with a placeholder
 			// This allows resolving keyof T where T is a generic type parameter
@@ -3225,7 +3384,7 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 					var stringIndexType *types.Type
 					var numberIndexType *types.Type
 
-					for _, member := range interfaceDecl.Members {
+					for _, member := range mergedMembers(tc.interfaceDeclarations(symbol)) {
 						switch m := member.(type) {
 						case ast.InterfaceProperty:
 							propName := m.Key.Name
@@ -3273,6 +3432,7 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 					objType.CallSignatures = callSignatures
 					objType.StringIndexType = stringIndexType
 					objType.NumberIndexType = numberIndexType
+					tc.mergeLibInterface(symbol, objType)
 					return objType
 				} else if symbol.Type == symbols.ClassSymbol {
 					if symbol.Node == nil {
@@ -3324,53 +3484,22 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 			}
 			if symbol.Type == symbols.InterfaceSymbol {
 				if symbol.Node != nil {
-					if interfaceDecl, ok := symbol.Node.(*ast.InterfaceDeclaration); ok {
-
-						// Convert interface members
-						properties := make(map[string]*types.Type)
-						var callSignatures []*types.Type
-						var stringIndexType *types.Type
-						var numberIndexType *types.Type
-
-						for _, member := range interfaceDecl.Members {
-							switch m := member.(type) {
-							case ast.InterfaceProperty:
-								propName := m.Key.Name
-								propType := tc.convertTypeNode(m.Value)
-								if m.Optional {
-									propType = types.NewUnionType([]*types.Type{propType, types.Undefined})
-								}
-								properties[propName] = propType
-							case *ast.CallSignature:
-								// Convert call signature to FunctionType
-								params := make([]*types.Type, len(m.Parameters))
-								for i := range m.Parameters {
-									params[i] = types.Any
-								}
-								returnType := tc.convertTypeNode(m.ReturnType)
-								callSignatures = append(callSignatures, types.NewFunctionType(params, returnType))
-							case *ast.IndexSignature:
-								valueType := tc.convertTypeNode(m.ValueType)
-								keyType := tc.convertTypeNode(m.KeyType)
-
-								if keyType.Kind == types.StringType {
-									stringIndexType = valueType
-								} else if keyType.Kind == types.NumberType {
-									numberIndexType = valueType
-								}
-							}
-						}
-
-						objType := types.NewObjectType(t.Name, properties)
-						objType.CallSignatures = callSignatures
-						objType.StringIndexType = stringIndexType
-						objType.NumberIndexType = numberIndexType
+					if _, ok := symbol.Node.(*ast.InterfaceDeclaration); ok {
+						objType := tc.interfaceObjectType(t.Name, tc.interfaceDeclarations(symbol))
+						tc.mergeLibInterface(symbol, objType)
 						return objType
 					}
 				}
 			}
 		}
 
+		// Interfaces of namespaces, such as NS.Props or Express.Request
+		if strings.Contains(t.Name, ".") {
+			if decls := tc.namespaceInterfaceDeclarations(t.Name); len(decls) > 0 {
+				return tc.interfaceObjectType(t.Name, decls)
+			}
+		}
+
 		// For other type references without type arguments, create a basic object type
 		return types.NewObjectType(t.Name, nil)
 
@@ -3451,6 +3580,7 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 		properties := make(map[string]*types.Type)
 		var stringIndexType *types.Type
 		var numberIndexType *types.Type
+		var callSignatures []*types.Type
 
 		for _, member := range t.Members {
 			switch m := member.(type) {
@@ -3462,6 +3592,16 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 				}
 
 				properties[m.Key.Name] = propType
+			case *ast.CallSignature:
+				params := make([]*types.Type, len(m.Parameters))
+				for i, param := range m.Parameters {
+					if param.ParamType != nil {
+						params[i] = tc.convertTypeNode(param.ParamType)
+					} else {
+						params[i] = types.Any
+					}
+				}
+				callSignatures = append(callSignatures, types.NewFunctionType(params, tc.convertTypeNode(m.ReturnType)))
 			case *ast.IndexSignature:
 				valueType := tc.convertTypeNode(m.ValueType)
 				keyType := tc.convertTypeNode(m.KeyType)
@@ -3474,6 +3614,7 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 			}
 		}
 		objType := types.NewObjectType("", properties)
+		objType.CallSignatures = callSignatures
 		objType.StringIndexType = stringIndexType
 		objType.NumberIndexType = numberIndexType
 		return objType
@@ -3483,20 +3624,8 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 		objectType := tc.convertTypeNode(t.ObjectType)
 		indexType := tc.convertTypeNode(t.IndexType)
 
-		// If indexType is a literal string, try to get that property from objectType
-		if indexType.Kind == types.LiteralType {
-			if propName, ok := indexType.Value.(string); ok {
-				// Strip quotes if present
-				if len(propName) >= 2 && ((propName[0] == '"' && propName[len(propName)-1] == '"') || (propName[0] == '\'' && propName[len(propName)-1] == '\'')) {
-					propName = propName[1 : len(propName)-1]
-				}
-
-				if objectType.Kind == types.ObjectType && objectType.Properties != nil {
-					if propType, exists := objectType.Properties[propName]; exists {
-						return propType
-					}
-				}
-			}
+		if resolved := indexedAccessType(objectType, indexType); resolved != nil {
+			return resolved
 		}
 
 		// If we can't resolve it, return an IndexedAccessType
@@ -3534,6 +3663,12 @@ func (tc *TypeChecker) convertTypeNode(typeNode ast.TypeNode) *types.Type {
 		}
 		return inferredType
 
+	case *ast.TypeOperator:
+		if t.Operator == "keyof" {
+			return keyofType(tc.convertTypeNode(t.Target))
+		}
+		return tc.convertTypeNode(t.Target)
+
 	case *ast.InferType:
 		// Handle infer T - create an InferType placeholder
 		return types.NewInferType(t.TypeParameter.Name)
@@ -0,0 +1,316 @@
+package checker
+
+import (
+	"strings"
+
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/parser"
+	"tstypechecker/pkg/symbols"
+	"tstypechecker/pkg/types"
+)
+
+// LoadGlobalDeclarations binds the declarations project files add to the
+// global scope, the declare global { ... } blocks and the declarations of the
+// .d.ts files that are not modules. Checkers that copy the global types of
+// this one merge them with the global declarations of the file they check.
+// It also declares the modules of the declare module 'name' { ... } blocks,
+// so that imports in any file see them.
+func (tc *TypeChecker) LoadGlobalDeclarations(files []string) {
+	binder := symbols.NewBinder(tc.symbolTable)
+	for _, path := range files {
+		// Syntax errors elsewhere in the file still leave its declarations
+		file, _ := parser.ParseFile(path)
+		if file == nil {
+			continue
+		}
+		isDeclarationFile := strings.HasSuffix(path, ".d.ts")
+		if !isDeclarationFile && !hasModuleDeclarations(file) {
+			continue
+		}
+		tc.declareModules(path, file)
+		tc.addDeclarationFile(path)
+
+		if isDeclarationFile && !isModuleFile(file) {
+			binder.BindFile(file)
+			continue
+		}
+		globals := &ast.File{Name: file.Name}
+		for _, stmt := range file.Body {
+			if module, ok := stmt.(*ast.ModuleDeclaration); ok && module.Global {
+				globals.Body = append(globals.Body, module)
+			}
+		}
+		binder.BindFile(globals)
+	}
+}
+
+// declareModules registers the declare module 'name' { ... } blocks of a file
+// with the module resolver. In a script they declare ambient modules, in a
+// module they augment the modules they name.
+func (tc *TypeChecker) declareModules(filename string, file *ast.File) {
+	if tc.moduleResolver == nil {
+		return
+	}
+	var decls []*ast.ModuleDeclaration
+	for _, stmt := range file.Body {
+		if module, ok := stmt.(*ast.ModuleDeclaration); ok && !module.Global {
+			decls = append(decls, module)
+		}
+	}
+	tc.moduleResolver.DeclareModules(filename, decls, isModuleFile(file))
+}
+
+// hasModuleDeclarations reports whether a file has declare global or declare
+// module blocks
+func hasModuleDeclarations(file *ast.File) bool {
+	for _, stmt := range file.Body {
+		if _, ok := stmt.(*ast.ModuleDeclaration); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isModuleFile reports whether a file is a module, that is, whether it has
+// imports or exports
+func isModuleFile(file *ast.File) bool {
+	for _, stmt := range file.Body {
+		switch stmt.(type) {
+		case *ast.ImportDeclaration, *ast.ExportDeclaration:
+			return true
+		}
+	}
+	return false
+}
+
+// checkGlobalDeclaration checks the body of a declare global { ... } block in
+// the global scope, where the binder put its declarations
+func (tc *TypeChecker) checkGlobalDeclaration(decl *ast.ModuleDeclaration, filename string) {
+	current := tc.symbolTable.Current
+	tc.symbolTable.Current = tc.symbolTable.Global
+	for _, stmt := range decl.Body {
+		tc.checkStatement(stmt, filename)
+	}
+	tc.symbolTable.Current = current
+}
+
+// interfaceDeclarations returns every declaration merged into an interface
+// symbol, in source order. A global interface also merges with the global
+// declarations other files of the project make.
+func (tc *TypeChecker) interfaceDeclarations(symbol *symbols.Symbol) []*ast.InterfaceDeclaration {
+	decls := declarationsOf(symbol)
+	if tc.isGlobalScope(symbol.Scope) {
+		for scope := symbol.Scope.Parent; scope != nil; scope = scope.Parent {
+			if outer, exists := scope.Symbols[symbol.Name]; exists && outer.Type == symbols.InterfaceSymbol {
+				decls = append(declarationsOf(outer), decls...)
+			}
+		}
+	}
+	return decls
+}
+
+// declarationsOf returns the interface declarations of a symbol
+func declarationsOf(symbol *symbols.Symbol) []*ast.InterfaceDeclaration {
+	var decls []*ast.InterfaceDeclaration
+	if decl, ok := symbol.Node.(*ast.InterfaceDeclaration); ok {
+		decls = append(decls, decl)
+	}
+	for _, node := range symbol.Declarations {
+		if decl, ok := node.(*ast.InterfaceDeclaration); ok {
+			decls = append(decls, decl)
+		}
+	}
+	return decls
+}
+
+// isGlobalScope reports whether scope is the global scope of the file being
+// checked or one of the global scopes it copies
+func (tc *TypeChecker) isGlobalScope(scope *symbols.Scope) bool {
+	for global := tc.symbolTable.Global; global != nil; global = global.Parent {
+		if scope == global {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaceInterfaceDeclarations returns the declarations of the interface a
+// qualified name such as NS.Props or Express.Request refers to, from every
+// block of the namespaces on the way, the global ones other files of the
+// project declare included
+func (tc *TypeChecker) namespaceInterfaceDeclarations(qualifiedName string) []*ast.InterfaceDeclaration {
+	parts := strings.Split(qualifiedName, ".")
+	symbol, exists := tc.symbolTable.ResolveSymbol(parts[0])
+	if !exists {
+		return nil
+	}
+	var namespaces []*ast.NamespaceDeclaration
+	for _, node := range tc.symbolNodes(symbol) {
+		if ns, ok := node.(*ast.NamespaceDeclaration); ok {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	var decls []*ast.InterfaceDeclaration
+	for i, name := range parts[1:] {
+		last := i == len(parts)-2
+		var inner []*ast.NamespaceDeclaration
+		for _, ns := range namespaces {
+			for _, stmt := range ns.Body {
+				if export, ok := stmt.(*ast.ExportDeclaration); ok {
+					stmt = export.Declaration
+				}
+				switch decl := stmt.(type) {
+				case *ast.NamespaceDeclaration:
+					if !last && decl.Name != nil && decl.Name.Name == name {
+						inner = append(inner, decl)
+					}
+				case *ast.InterfaceDeclaration:
+					if last && decl.ID != nil && decl.ID.Name == name {
+						decls = append(decls, decl)
+					}
+				}
+			}
+		}
+		namespaces = inner
+	}
+	return decls
+}
+
+// symbolNodes returns every declaration of a symbol. A global symbol also
+// has the declarations other files of the project make.
+func (tc *TypeChecker) symbolNodes(symbol *symbols.Symbol) []ast.Node {
+	var nodes []ast.Node
+	if tc.isGlobalScope(symbol.Scope) {
+		for scope := symbol.Scope.Parent; scope != nil; scope = scope.Parent {
+			if outer, exists := scope.Symbols[symbol.Name]; exists {
+				nodes = append(nodes, outer.Node)
+				nodes = append(nodes, outer.Declarations...)
+			}
+		}
+	}
+	nodes = append(nodes, symbol.Node)
+	return append(nodes, symbol.Declarations...)
+}
+
+// interfaceObjectType converts the merged members of the declarations of an
+// interface
+func (tc *TypeChecker) interfaceObjectType(name string, decls []*ast.InterfaceDeclaration) *types.Type {
+	properties := make(map[string]*types.Type)
+	var callSignatures []*types.Type
+	var stringIndexType *types.Type
+	var numberIndexType *types.Type
+
+	for _, member := range mergedMembers(decls) {
+		switch m := member.(type) {
+		case ast.InterfaceProperty:
+			propType := tc.convertTypeNode(m.Value)
+			if m.Optional {
+				propType = types.NewUnionType([]*types.Type{propType, types.Undefined})
+			}
+			properties[m.Key.Name] = propType
+		case *ast.CallSignature:
+			// Convert call signature to FunctionType
+			params := make([]*types.Type, len(m.Parameters))
+			for i := range m.Parameters {
+				params[i] = types.Any
+			}
+			callSignatures = append(callSignatures, types.NewFunctionType(params, tc.convertTypeNode(m.ReturnType)))
+		case *ast.IndexSignature:
+			valueType := tc.convertTypeNode(m.ValueType)
+			keyType := tc.convertTypeNode(m.KeyType)
+			if keyType.Kind == types.StringType {
+				stringIndexType = valueType
+			} else if keyType.Kind == types.NumberType {
+				numberIndexType = valueType
+			}
+		}
+	}
+
+	objType := types.NewObjectType(name, properties)
+	objType.CallSignatures = callSignatures
+	objType.StringIndexType = stringIndexType
+	objType.NumberIndexType = numberIndexType
+	return objType
+}
+
+// mergedMembers returns the members of all the declarations of an interface
+func mergedMembers(decls []*ast.InterfaceDeclaration) []ast.TypeMember {
+	var members []ast.TypeMember
+	for _, decl := range decls {
+		members = append(members, decl.Members...)
+	}
+	return members
+}
+
+// mergedExtends returns the interfaces all the declarations of an interface
+// extend
+func mergedExtends(decls []*ast.InterfaceDeclaration) []ast.TypeNode {
+	var extends []ast.TypeNode
+	for _, decl := range decls {
+		extends = append(extends, decl.Extends...)
+	}
+	return extends
+}
+
+// mergedDeclarations returns the declarations merged into the interface decl
+// is the first declaration of, or just decl
+func (tc *TypeChecker) mergedDeclarations(decl *ast.InterfaceDeclaration) ([]*ast.InterfaceDeclaration, *symbols.Symbol) {
+	if symbol, exists := tc.symbolTable.ResolveSymbol(decl.ID.Name); exists && symbol.Node == decl {
+		return tc.interfaceDeclarations(symbol), symbol
+	}
+	return []*ast.InterfaceDeclaration{decl}, nil
+}
+
+// mergeLibInterface adds to the type of a global interface the members the
+// lib declares for it, so that augmenting Window keeps what lib.dom.d.ts has
+func (tc *TypeChecker) mergeLibInterface(symbol *symbols.Symbol, objType *types.Type) {
+	if symbol == nil || !tc.isGlobalScope(symbol.Scope) || tc.globalEnv == nil {
+		return
+	}
+	libType, exists := tc.globalEnv.GetType(symbol.Name)
+	if !exists || libType == nil || libType.Kind != types.ObjectType {
+		return
+	}
+	for name, propType := range libType.Properties {
+		if _, declared := objType.Properties[name]; !declared {
+			objType.Properties[name] = propType
+		}
+	}
+	objType.CallSignatures = append(objType.CallSignatures, libType.CallSignatures...)
+	if objType.StringIndexType == nil {
+		objType.StringIndexType = libType.StringIndexType
+	}
+	if objType.NumberIndexType == nil {
+		objType.NumberIndexType = libType.NumberIndexType
+	}
+}
+
+// isMergedDeclaration reports whether decl is a later declaration merged into
+// the symbol of an earlier one with the same name
+func (tc *TypeChecker) isMergedDeclaration(name string, decl ast.Node) bool {
+	symbol, exists := tc.symbolTable.ResolveSymbol(name)
+	if !exists {
+		return false
+	}
+	for _, node := range symbol.Declarations {
+		if node == decl {
+			return true
+		}
+	}
+	return false
+}
+
+// withNamespaceMembers returns a copy of the type of a function, class or enum
+// a namespace merges with, with the members the namespace exports added to it
+func withNamespaceMembers(t *types.Type, members map[string]*types.Type) *types.Type {
+	merged := *t
+	merged.Properties = make(map[string]*types.Type, len(t.Properties)+len(members))
+	for name, propType := range t.Properties {
+		merged.Properties[name] = propType
+	}
+	for name, propType := range members {
+		merged.Properties[name] = propType
+	}
+	return &merged
+}
@@ -135,10 +135,11 @@ func (tc *TypeChecker) checkVariableDeclaration(decl *ast.VariableDeclaration, f
 							fmt.Sprintf("Type '%s' is not assignable to type '%s'.", typeToCheck.String(), declaredType.String()),
 							"TS2322", "error")
 					}
-					// Store the declared type (not the inferred type) in the cache
+					// Store the declared type (not the inferred type) in the cache,
+					// narrowed like an assignment until the variable is assigned
 					tc.typeCache[declarator] = declaredType
 					tc.typeCache[declarator.ID] = declaredType
-					tc.varTypeCache[declarator.ID.Name] = declaredType
+					tc.varTypeCache[declarator.ID.Name] = tc.typeNarrowing.assignmentReduction(declaredType, typeToCheck)
 				} else {
 					// No type annotation, store the inferred type
 					finalType := inferredType
@@ -146,7 +147,8 @@ func (tc *TypeChecker) checkVariableDeclaration(decl *ast.VariableDeclaration, f
 					// Apply widening for literal types if it's not a const declaration
 					// let x = false; -> x is boolean, not false
 					// let y = "hello"; -> y is string, not "hello"
-					if decl.Kind != "const" && inferredType.Kind == types.LiteralType {
+					// let z = "hello" as const; -> z is "hello"
+					if decl.Kind != "const" && inferredType.Kind == types.LiteralType && !isConstAssertion(declarator.Init) {
 						switch inferredType.Value.(type) {
 						case bool:
 							finalType = types.Boolean
@@ -782,6 +784,13 @@ func (tc *TypeChecker) checkEnumDeclaration(decl *ast.EnumDeclaration, filename
 		// Create the enum type
 		enumType := types.NewObjectType(decl.Name.Name, properties)
 
+		// A later enum block adds its members to the enum of the first one
+		if tc.isMergedDeclaration(decl.Name.Name, decl) {
+			if existing, exists := tc.typeAliasCache[decl.Name.Name]; exists && existing.Kind == types.ObjectType && existing.Properties != nil {
+				enumType, properties = existing, existing.Properties
+			}
+		}
+
 		// Populate properties with the enum type itself (simplification)
 		// In TypeScript, enum members are of type Enum.Member, which is a subtype of Enum
 		for _, member := range decl.Members {
@@ -887,6 +896,14 @@ func (tc *TypeChecker) checkNamespaceDeclaration(decl *ast.NamespaceDeclaration,
 
 		// Create namespace type as an ObjectType with the exported members
 		namespaceType := types.NewObjectType("typeof "+decl.Name.Name, namespaceMembers)
+
+		// A namespace merged with a function, class, enum or an earlier
+		// namespace adds its members to the value declared there
+		if tc.isMergedDeclaration(decl.Name.Name, decl) {
+			if existing, exists := tc.varTypeCache[decl.Name.Name]; exists && existing != nil {
+				namespaceType = withNamespaceMembers(existing, namespaceMembers)
+			}
+		}
 		tc.varTypeCache[decl.Name.Name] = namespaceType
 	}
 }
@@ -1011,3 +1028,13 @@ func (tc *TypeChecker) validateClassExpressionConstructor(classExpr *ast.ClassEx
 		}
 	}
 }
+
+// isConstAssertion reports whether expr is an 'as const' assertion
+func isConstAssertion(expr ast.Expression) bool {
+	asExpr, ok := expr.(*ast.AsExpression)
+	if !ok {
+		return false
+	}
+	typeRef, ok := asExpr.TypeAnnotation.(*ast.TypeReference)
+	return ok && typeRef.Name == "const"
+}
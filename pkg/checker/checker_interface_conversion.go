@@ -13,8 +13,11 @@ func (tc *TypeChecker) convertInterfaceToType(decl *ast.InterfaceDeclaration) *t
 	var stringIndexType *types.Type
 	var numberIndexType *types.Type
 
+	// Every declaration of the interface adds its members to it
+	decls, symbol := tc.mergedDeclarations(decl)
+
 	// First, process extended interfaces to inherit their properties
-	for _, extendType := range decl.Extends {
+	for _, extendType := range mergedExtends(decls) {
 		if typeRef, ok := extendType.(*ast.TypeReference); ok {
 			// Resolve the parent interface
 			if symbol, exists := tc.symbolTable.ResolveSymbol(typeRef.Name); exists {
@@ -41,7 +44,7 @@ func (tc *TypeChecker) convertInterfaceToType(decl *ast.InterfaceDeclaration) *t
 	}
 
 	// Then, process own members (which can override inherited properties)
-	for _, member := range decl.Members {
+	for _, member := range mergedMembers(decls) {
 		switch m := member.(type) {
 		case ast.InterfaceProperty:
 			propType := tc.convertTypeNode(m.Value)
@@ -89,5 +92,6 @@ func (tc *TypeChecker) convertInterfaceToType(decl *ast.InterfaceDeclaration) *t
 	objType.CallSignatures = callSignatures
 	objType.StringIndexType = stringIndexType
 	objType.NumberIndexType = numberIndexType
+	tc.mergeLibInterface(symbol, objType)
 	return objType
 }
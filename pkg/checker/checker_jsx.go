@@ -0,0 +1,661 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/parser"
+	"tstypechecker/pkg/types"
+)
+
+// jsxNamespace holds the interfaces declared in the JSX namespaces visible to
+// a file, merged by name: IntrinsicElements lists the lowercase tags and
+// Element is the type of every JSX expression
+type jsxNamespace struct {
+	interfaces map[string][]*ast.InterfaceDeclaration
+
+	// Interfaces and type aliases of any namespace by unqualified name, which
+	// the props types of intrinsic elements are expanded through
+	library map[string][]ast.Statement
+}
+
+func newJSXNamespace() *jsxNamespace {
+	return &jsxNamespace{
+		interfaces: make(map[string][]*ast.InterfaceDeclaration),
+		library:    make(map[string][]ast.Statement),
+	}
+}
+
+// collect adds the JSX namespaces found in stmts, looking inside other
+// namespaces (React.JSX) and global augmentations (declare global { namespace JSX })
+func (ns *jsxNamespace) collect(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.NamespaceDeclaration:
+			if s.Name != nil && s.Name.Name == "JSX" {
+				ns.addInterfaces(s.Body)
+			} else {
+				ns.collect(s.Body)
+			}
+		case *ast.BlockStatement:
+			ns.collect(s.Body)
+		case *ast.ModuleDeclaration:
+			if s.Global {
+				ns.collect(s.Body)
+			}
+		case *ast.ExportDeclaration:
+			if s.Declaration != nil {
+				ns.collect([]ast.Statement{s.Declaration})
+			}
+		case *ast.InterfaceDeclaration:
+			ns.addLibraryType(s.ID, s)
+		case *ast.TypeAliasDeclaration:
+			ns.addLibraryType(s.ID, s)
+		}
+	}
+}
+
+func (ns *jsxNamespace) addInterfaces(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		if export, ok := stmt.(*ast.ExportDeclaration); ok {
+			stmt = export.Declaration
+		}
+		if decl, ok := stmt.(*ast.InterfaceDeclaration); ok && decl.ID != nil {
+			ns.interfaces[decl.ID.Name] = append(ns.interfaces[decl.ID.Name], decl)
+			ns.addLibraryType(decl.ID, decl)
+		}
+	}
+}
+
+func (ns *jsxNamespace) addLibraryType(id *ast.Identifier, decl ast.Statement) {
+	if id != nil {
+		ns.library[id.Name] = append(ns.library[id.Name], decl)
+	}
+}
+
+// has reports whether the namespace declares the interface name
+func (ns *jsxNamespace) has(name string) bool {
+	return len(ns.interfaces[name]) > 0
+}
+
+// members returns the properties of the named interface, including those of
+// the JSX interfaces it extends (IntrinsicElements extends React.JSX.IntrinsicElements).
+// open is set when an index signature accepts any name.
+func (ns *jsxNamespace) members(name string) (props map[string]ast.TypeNode, open bool) {
+	props = make(map[string]ast.TypeNode)
+	visited := make(map[string]bool)
+
+	var walk func(name string)
+	walk = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, decl := range ns.interfaces[name] {
+			for _, member := range decl.Members {
+				switch m := member.(type) {
+				case ast.InterfaceProperty:
+					props[m.Key.Name] = m.Value
+				case *ast.IndexSignature:
+					open = true
+				}
+			}
+			for _, parent := range decl.Extends {
+				if ref, ok := parent.(*ast.TypeReference); ok {
+					walk(ref.Name[strings.LastIndex(ref.Name, ".")+1:])
+				}
+			}
+		}
+	}
+	walk(name)
+	return props, open
+}
+
+// jsxLibraryCache keeps the parsed React declarations of a checker and of
+// the workers copied from it, parsing a file again when it changes on disk
+type jsxLibraryCache struct {
+	mu    sync.Mutex
+	files map[string]jsxLibrary
+}
+
+type jsxLibrary struct {
+	modTime time.Time
+	size    int64
+	file    *ast.File
+}
+
+func newJSXLibraryCache() *jsxLibraryCache {
+	return &jsxLibraryCache{files: make(map[string]jsxLibrary)}
+}
+
+// load returns the declarations in path, or nil when it cannot be read
+func (c *jsxLibraryCache) load(path string) *ast.File {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.files[path]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.file
+	}
+	// Syntax errors still leave a usable partial AST
+	file, _ := parser.ParseFile(path)
+	c.files[path] = jsxLibrary{modTime: info.ModTime(), size: info.Size(), file: file}
+	return file
+}
+
+// reactTypes returns the React declarations an import of react from filename
+// resolves to, through typeRoots, package exports, typesVersions and
+// symlinks like any other import, or nil when there are none
+func (tc *TypeChecker) reactTypes(filename string) *ast.File {
+	if tc.moduleResolver == nil || tc.jsxLibraries == nil {
+		return nil
+	}
+	path, err := tc.moduleResolver.ResolvePath("react", filename)
+	if err != nil || isJavaScriptFileName(path) {
+		return nil
+	}
+	return tc.jsxLibraries.load(path)
+}
+
+// loadJSXNamespace gathers the JSX namespace for a .tsx or .jsx file from its
+// own declarations and from @types/react, and makes JSX expressions infer to
+// JSX.Element
+func (tc *TypeChecker) loadJSXNamespace(file *ast.File, filename string) {
+	tc.jsx = nil
+	tc.inferencer.SetJSXElementType(nil)
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".tsx" && ext != ".jsx" {
+		return
+	}
+
+	ns := newJSXNamespace()
+	ns.collect(file.Body)
+	if absPath, err := filepath.Abs(filename); err == nil {
+		if react := tc.reactTypes(absPath); react != nil {
+			ns.collect(react.Body)
+		}
+	}
+	tc.jsx = ns
+
+	if ns.has("Element") {
+		props := make(map[string]*types.Type)
+		members, _ := ns.members("Element")
+		for name, typeNode := range members {
+			props[name] = tc.convertTypeNode(typeNode)
+		}
+		tc.inferencer.SetJSXElementType(types.NewObjectType("JSX.Element", props))
+	}
+}
+
+// checkJSXElement checks a JSX element: the tag must be a known intrinsic
+// element or a component in scope, and its attributes must match the props
+func (tc *TypeChecker) checkJSXElement(elem *ast.JSXElement, filename string) {
+	for _, attr := range elem.Attributes {
+		switch a := attr.(type) {
+		case *ast.JSXAttribute:
+			if a.Value != nil {
+				tc.checkExpression(a.Value, filename)
+			}
+		case *ast.JSXSpreadAttribute:
+			tc.checkExpression(a.Argument, filename)
+		}
+	}
+	tc.checkJSXChildren(elem.Children, filename)
+
+	if id, ok := elem.Name.(*ast.Identifier); ok && isIntrinsicJSXTag(id.Name) {
+		tc.checkIntrinsicJSXElement(elem, id, filename)
+		return
+	}
+
+	// Components are values: an unknown name is reported like any identifier
+	tc.checkExpression(elem.Name, filename)
+
+	componentType := tc.getExpressionType(elem.Name)
+	if componentType == nil || componentType.Kind != types.FunctionType {
+		return
+	}
+	tc.checkJSXComponentReturnType(elem, componentType, filename)
+
+	// Function components take their props as the first parameter
+	if len(componentType.Parameters) > 0 {
+		tc.checkJSXAttributes(elem, tc.jsxPropsWithTypeArgs(elem, componentType), filename)
+	}
+}
+
+// jsxPropsWithTypeArgs returns the props of a component, instantiated with
+// the type arguments after the tag name: <List<number> items={...} />. The
+// type parameters without one are inferred from the attributes by tsc; they
+// accept anything here.
+func (tc *TypeChecker) jsxPropsWithTypeArgs(elem *ast.JSXElement, componentType *types.Type) *types.Type {
+	propsType := componentType.Parameters[0]
+	if len(componentType.TypeParameters) == 0 {
+		return propsType
+	}
+	substitutions := make(map[string]*types.Type, len(componentType.TypeParameters))
+	for i, param := range componentType.TypeParameters {
+		if i < len(elem.TypeArguments) {
+			substitutions[param.Name] = tc.convertTypeNode(elem.TypeArguments[i])
+		} else {
+			substitutions[param.Name] = types.Any
+		}
+	}
+	return tc.substituteType(propsType, substitutions)
+}
+
+// checkJSXChildren checks the expressions among the children of an element or fragment
+func (tc *TypeChecker) checkJSXChildren(children []ast.Expression, filename string) {
+	for _, child := range children {
+		switch c := child.(type) {
+		case *ast.JSXText:
+			// Text is always valid
+		case *ast.JSXExpressionContainer:
+			if c.Expression != nil {
+				tc.checkExpression(c.Expression, filename)
+			}
+		default:
+			tc.checkExpression(child, filename)
+		}
+	}
+}
+
+// isIntrinsicJSXTag reports whether a tag names an intrinsic element (div,
+// my-element) rather than a component: it starts lowercase or contains '-'
+func isIntrinsicJSXTag(name string) bool {
+	return name != "" && (name[0] >= 'a' && name[0] <= 'z' || strings.Contains(name, "-"))
+}
+
+// checkIntrinsicJSXElement looks the tag up in JSX.IntrinsicElements
+func (tc *TypeChecker) checkIntrinsicJSXElement(elem *ast.JSXElement, tag *ast.Identifier, filename string) {
+	if tc.jsx == nil || !tc.jsx.has("IntrinsicElements") {
+		if tc.config.NoImplicitAny {
+			tc.addError(filename, elem.Pos().Line, elem.Pos().Column,
+				"JSX element implicitly has type 'any' because no interface 'JSX.IntrinsicElements' exists.",
+				"TS7026", "error")
+		}
+		return
+	}
+
+	elements, open := tc.jsx.members("IntrinsicElements")
+	propsNode, exists := elements[tag.Name]
+	if !exists {
+		if !open {
+			tc.addError(filename, tag.Pos().Line, tag.Pos().Column,
+				fmt.Sprintf("Property '%s' does not exist on type 'JSX.IntrinsicElements'.", tag.Name),
+				"TS2339", "error")
+		}
+		return
+	}
+
+	if _, ok := propsNode.(*ast.ObjectTypeLiteral); ok {
+		tc.checkJSXAttributes(elem, tc.convertTypeNode(propsNode), filename)
+		return
+	}
+	// Library element types such as React.DetailedHTMLProps<...> are not in
+	// scope, so they are expanded through the declarations of the library
+	tc.checkJSXAttributes(elem, tc.intrinsicJSXProps(propsNode), filename)
+}
+
+// jsxTypeArg is a type argument with the type parameters in scope where it
+// was written
+type jsxTypeArg struct {
+	node ast.TypeNode
+	env  map[string]jsxTypeArg
+}
+
+// jsxPropsExpander collects the props of a library element type, following
+// its type aliases, intersections and the interfaces it extends
+type jsxPropsExpander struct {
+	tc       *TypeChecker
+	props    map[string]*types.Type
+	visiting map[ast.Statement]bool
+}
+
+// intrinsicJSXProps returns the props type of an intrinsic element declared
+// by the library. When part of it cannot be expanded the type accepts any
+// other attribute, so that none is reported against an incomplete list.
+func (tc *TypeChecker) intrinsicJSXProps(node ast.TypeNode) *types.Type {
+	e := &jsxPropsExpander{tc: tc, props: make(map[string]*types.Type), visiting: make(map[ast.Statement]bool)}
+	complete := e.expand(node, nil)
+	props := types.NewObjectType(jsxTypeNodeString(node), e.props)
+	if !complete {
+		props.StringIndexType = types.Any
+	}
+	return props
+}
+
+// expand adds the props of node, reporting whether it was fully expanded
+func (e *jsxPropsExpander) expand(node ast.TypeNode, env map[string]jsxTypeArg) bool {
+	switch n := node.(type) {
+	case *ast.ObjectTypeLiteral:
+		return e.addMembers(n.Members, env)
+	case *ast.IntersectionType:
+		complete := true
+		for _, part := range n.Types {
+			complete = e.expand(part, env) && complete
+		}
+		return complete
+	case *ast.TypeReference:
+		if arg, ok := env[n.Name]; ok {
+			return e.expand(arg.node, arg.env)
+		}
+		decls := e.tc.jsx.library[n.Name[strings.LastIndex(n.Name, ".")+1:]]
+		if len(decls) == 0 {
+			return false
+		}
+		args := make([]jsxTypeArg, len(n.TypeArguments))
+		for i, arg := range n.TypeArguments {
+			args[i] = jsxTypeArg{node: arg, env: env}
+		}
+		complete := true
+		for _, decl := range decls {
+			if e.visiting[decl] {
+				continue
+			}
+			e.visiting[decl] = true
+			switch d := decl.(type) {
+			case *ast.InterfaceDeclaration:
+				scope := bindJSXTypeArgs(d.TypeParameters, args)
+				complete = e.addMembers(d.Members, scope) && complete
+				for _, parent := range d.Extends {
+					complete = e.expand(parent, scope) && complete
+				}
+			case *ast.TypeAliasDeclaration:
+				complete = e.expand(d.TypeAnnotation, bindJSXTypeArgs(d.TypeParameters, args)) && complete
+			}
+			delete(e.visiting, decl)
+		}
+		return complete
+	}
+	return false
+}
+
+// addMembers adds the properties among members. An index signature leaves
+// the props open.
+func (e *jsxPropsExpander) addMembers(members []ast.TypeMember, env map[string]jsxTypeArg) bool {
+	complete := true
+	for _, member := range members {
+		switch m := member.(type) {
+		case ast.InterfaceProperty:
+			propType := e.convert(m.Value, env)
+			if m.Optional {
+				propType = types.NewUnionType([]*types.Type{propType, types.Undefined})
+			}
+			e.props[m.Key.Name] = propType
+		case *ast.IndexSignature:
+			complete = false
+		}
+	}
+	return complete
+}
+
+// convert returns the type of a prop. Primitives, literals and the type
+// aliases of the library are followed; anything else, such as an event
+// handler or an interface that is not in scope, is any.
+func (e *jsxPropsExpander) convert(node ast.TypeNode, env map[string]jsxTypeArg) *types.Type {
+	switch n := node.(type) {
+	case *ast.UnionType:
+		parts := make([]*types.Type, 0, len(n.Types))
+		for _, part := range n.Types {
+			partType := e.convert(part, env)
+			if partType.Kind == types.AnyType {
+				return types.Any
+			}
+			parts = append(parts, partType)
+		}
+		return types.NewUnionType(parts)
+	case *ast.LiteralType:
+		return e.tc.convertTypeNode(n)
+	case *ast.TypeReference:
+		if arg, ok := env[n.Name]; ok {
+			return e.convert(arg.node, arg.env)
+		}
+		switch n.Name {
+		case "string", "number", "boolean", "bigint", "undefined", "null":
+			return e.tc.convertTypeNode(n)
+		}
+		for _, decl := range e.tc.jsx.library[n.Name[strings.LastIndex(n.Name, ".")+1:]] {
+			if alias, ok := decl.(*ast.TypeAliasDeclaration); ok && len(alias.TypeParameters) == 0 && !e.visiting[alias] {
+				e.visiting[alias] = true
+				defer delete(e.visiting, alias)
+				return e.convert(alias.TypeAnnotation, nil)
+			}
+		}
+	}
+	return types.Any
+}
+
+// bindJSXTypeArgs maps type parameters to the arguments given, or to their
+// defaults
+func bindJSXTypeArgs(params []ast.TypeNode, args []jsxTypeArg) map[string]jsxTypeArg {
+	scope := make(map[string]jsxTypeArg, len(params))
+	for i, p := range params {
+		param, ok := p.(*ast.TypeParameter)
+		if !ok || param.Name == nil {
+			continue
+		}
+		if i < len(args) {
+			scope[param.Name.Name] = args[i]
+		} else if param.Default != nil {
+			scope[param.Name.Name] = jsxTypeArg{node: param.Default, env: scope}
+		}
+	}
+	return scope
+}
+
+// jsxTypeNodeString renders a props type reference as tsc names it in
+// messages, without the namespace: DetailedHTMLProps<HTMLAttributes<HTMLDivElement>, HTMLDivElement>
+func jsxTypeNodeString(node ast.TypeNode) string {
+	ref, ok := node.(*ast.TypeReference)
+	if !ok {
+		return ""
+	}
+	name := ref.Name[strings.LastIndex(ref.Name, ".")+1:]
+	if len(ref.TypeArguments) == 0 {
+		return name
+	}
+	args := make([]string, len(ref.TypeArguments))
+	for i, arg := range ref.TypeArguments {
+		args[i] = jsxTypeNodeString(arg)
+	}
+	return name + "<" + strings.Join(args, ", ") + ">"
+}
+
+// checkJSXComponentReturnType reports function components that return
+// something React cannot render. Declarations with JSX.ElementType (React
+// 18.2+ with TypeScript 5.1+) accept any ReactNode, so the check is skipped.
+func (tc *TypeChecker) checkJSXComponentReturnType(elem *ast.JSXElement, componentType *types.Type, filename string) {
+	if tc.jsx == nil || !tc.jsx.has("Element") || tc.jsx.has("ElementType") || componentType.ReturnType == nil {
+		return
+	}
+
+	switch componentType.ReturnType.Kind {
+	case types.NumberType, types.StringType, types.BooleanType, types.VoidType, types.LiteralType:
+		name := parser.JSXTagName(elem.Name)
+		tc.addError(filename, elem.Name.Pos().Line, elem.Name.Pos().Column,
+			fmt.Sprintf("'%s' cannot be used as a JSX component.\n  Its return type '%s' is not a valid JSX element.",
+				name, componentType.ReturnType.String()),
+			"TS2786", "error")
+	}
+}
+
+// checkJSXAttributes checks the attributes of an element against its props
+// type: every attribute must be a known prop of a compatible type, and every
+// required prop must be given
+func (tc *TypeChecker) checkJSXAttributes(elem *ast.JSXElement, propsType *types.Type, filename string) {
+	if propsType == nil || propsType.Kind != types.ObjectType || len(propsType.Properties) == 0 {
+		return
+	}
+
+	propsName := propsType.String()
+	if propsType.Name == "" {
+		propsName = "{ " + strings.Join(tc.sortedPropNames(propsType.Properties), "; ") + " }"
+	}
+
+	given := make(map[string]bool)
+	var givenTypes []string
+	hasSpread := false
+
+	for _, attr := range elem.Attributes {
+		a, ok := attr.(*ast.JSXAttribute)
+		if !ok {
+			hasSpread = true
+			continue
+		}
+		name := a.Name.Name
+		given[name] = true
+
+		// key and ref are accepted by every element (IntrinsicAttributes)
+		if name == "key" || name == "ref" {
+			continue
+		}
+
+		expected, exists := propsType.Properties[name]
+		if !exists {
+			if propsType.StringIndexType != nil || strings.Contains(name, "-") {
+				// Hyphenated names (data-*, aria-*) are never checked against props
+				continue
+			}
+			actual := tc.jsxAttributesTypeString(elem)
+			tc.addError(filename, a.Name.Pos().Line, a.Name.Pos().Column,
+				fmt.Sprintf("Type '%s' is not assignable to type 'IntrinsicAttributes & %s'.\n  Property '%s' does not exist on type 'IntrinsicAttributes & %s'.",
+					actual, propsName, name, propsName),
+				"TS2322", "error")
+			continue
+		}
+
+		actual := tc.jsxAttributeValueType(a, expected)
+		givenTypes = append(givenTypes, name+": "+actual.String())
+		if actual.Kind == types.AnyType || actual.Kind == types.UnknownType {
+			continue
+		}
+		if !tc.isAssignableTo(actual, expected) {
+			// An optional prop is reported with its declared type, as tsc does
+			expectedName := expected.String()
+			if tc.isPropertyOptional(expected) {
+				expectedName = withoutUndefined(expected).String()
+			}
+			tc.addError(filename, a.Name.Pos().Line, a.Name.Pos().Column,
+				fmt.Sprintf("Type '%s' is not assignable to type '%s'.", actual.String(), expectedName),
+				"TS2322", "error")
+		}
+	}
+
+	// Elements with children pass them as the children prop
+	if len(elem.Children) > 0 {
+		given["children"] = true
+	}
+	if hasSpread {
+		// A spread may supply any of the remaining props
+		return
+	}
+
+	var missing []string
+	for _, name := range sortedKeys(propsType.Properties) {
+		if !given[name] && !tc.isPropertyOptional(propsType.Properties[name]) {
+			missing = append(missing, name)
+		}
+	}
+
+	pos := elem.Name.Pos()
+	actual := "{ " + strings.Join(givenTypes, "; ") + " }"
+	if len(givenTypes) == 0 {
+		actual = "{}"
+	}
+	switch {
+	case len(missing) == 1:
+		tc.addError(filename, pos.Line, pos.Column,
+			fmt.Sprintf("Property '%s' is missing in type '%s' but required in type '%s'.", missing[0], actual, propsName),
+			"TS2741", "error")
+	case len(missing) > 1:
+		tc.addError(filename, pos.Line, pos.Column,
+			fmt.Sprintf("Type '%s' is missing the following properties from type '%s': %s", actual, propsName, strings.Join(missing, ", ")),
+			"TS2739", "error")
+	}
+}
+
+// jsxAttributeValueType returns the type an attribute passes: strings for
+// quoted values, true for a bare name, and the expression type for {expr}
+func (tc *TypeChecker) jsxAttributeValueType(attr *ast.JSXAttribute, expected *types.Type) *types.Type {
+	switch v := attr.Value.(type) {
+	case nil:
+		return types.NewLiteralType(true)
+	case *ast.Literal:
+		if tc.needsLiteralType(expected) {
+			return tc.inferLiteralType(v)
+		}
+		return types.String
+	case *ast.JSXExpressionContainer:
+		if v.Expression == nil {
+			return types.Any
+		}
+		if tc.needsLiteralType(expected) {
+			return tc.inferLiteralType(v.Expression)
+		}
+		return tc.getExpressionType(v.Expression)
+	default:
+		return tc.getExpressionType(attr.Value)
+	}
+}
+
+// jsxAttributesTypeString renders the attributes of an element as an object
+// type, as in "Type '{ title: string; }' is not assignable to ..."
+func (tc *TypeChecker) jsxAttributesTypeString(elem *ast.JSXElement) string {
+	var parts []string
+	for _, attr := range elem.Attributes {
+		if a, ok := attr.(*ast.JSXAttribute); ok {
+			parts = append(parts, a.Name.Name+": "+tc.jsxAttributeValueType(a, types.Any).String())
+		}
+	}
+	if len(parts) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(parts, "; ") + " }"
+}
+
+// withoutUndefined removes undefined from a union
+func withoutUndefined(t *types.Type) *types.Type {
+	var rest []*types.Type
+	for _, member := range t.Types {
+		if member.Kind != types.UndefinedType {
+			rest = append(rest, member)
+		}
+	}
+	if len(rest) == 1 {
+		return rest[0]
+	}
+	return types.NewUnionType(rest)
+}
+
+func sortedKeys(props map[string]*types.Type) []string {
+	keys := make([]string, 0, len(props))
+	for name := range props {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedPropNames renders the properties of an anonymous props type, the
+// optional ones as name?: T with their declared type
+func (tc *TypeChecker) sortedPropNames(props map[string]*types.Type) []string {
+	var parts []string
+	for _, name := range sortedKeys(props) {
+		propType := props[name]
+		if tc.isPropertyOptional(propType) {
+			parts = append(parts, name+"?: "+withoutUndefined(propType).String())
+			continue
+		}
+		parts = append(parts, name+": "+propType.String())
+	}
+	return parts
+}
@@ -2,19 +2,47 @@ package checker
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/modules"
 	"tstypechecker/pkg/parser"
 	"tstypechecker/pkg/symbols"
 	"tstypechecker/pkg/types"
 )
 
+// addDeclarationFile records a file whose declarations were loaded into the
+// global scope rather than through an import
+func (tc *TypeChecker) addDeclarationFile(path string) {
+	if tc.declarationFiles == nil {
+		tc.declarationFiles = make(map[string]bool)
+	}
+	tc.declarationFiles[path] = true
+}
+
+// DeclarationFiles returns the files the checker loaded declarations from
+// outside imports: lib files, the types of node_modules packages, typeRoots
+// and the global declarations of the project
+func (tc *TypeChecker) DeclarationFiles() []string {
+	seen := make(map[string]bool, len(tc.declarationFiles)+len(tc.loadedLibFiles))
+	var files []string
+	for _, set := range []map[string]bool{tc.declarationFiles, tc.loadedLibFiles} {
+		for path := range set {
+			if !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
 // loadTypeScriptLibs loads TypeScript library definition files based on configured libs
 func (tc *TypeChecker) loadTypeScriptLibs(libs []string) {
 	// Get root directory
@@ -166,6 +194,36 @@ func (tc *TypeChecker) SetPathAliases(baseUrl string, paths map[string][]string)
 	}
 }
 
+// SetProjectOutputs configures the outputs of referenced projects for module resolution
+func (tc *TypeChecker) SetProjectOutputs(outputs []modules.ProjectOutput) {
+	if tc.moduleResolver != nil {
+		tc.moduleResolver.SetProjectOutputs(outputs)
+	}
+}
+
+// SetTraceResolution enables recording how every import is resolved
+func (tc *TypeChecker) SetTraceResolution(enabled bool) {
+	if tc.moduleResolver != nil {
+		tc.moduleResolver.SetTraceResolution(enabled)
+	}
+}
+
+// ResolutionTraces returns and clears the resolution traces recorded so far
+func (tc *TypeChecker) ResolutionTraces() []modules.ResolutionTrace {
+	if tc.moduleResolver == nil {
+		return nil
+	}
+	return tc.moduleResolver.ResolutionTraces()
+}
+
+// SetModuleResolution configures the moduleResolution algorithm and the
+// customConditions matched in package.json exports and imports
+func (tc *TypeChecker) SetModuleResolution(kind string, customConditions []string) {
+	if tc.moduleResolver != nil {
+		tc.moduleResolver.SetModuleResolution(kind, customConditions)
+	}
+}
+
 // SetTypeRoots configures type roots from tsconfig for declaration file resolution
 func (tc *TypeChecker) SetTypeRoots(typeRoots []string) {
 	if tc.moduleResolver != nil {
@@ -210,8 +268,7 @@ func (tc *TypeChecker) loadTypesPackages(nodeModulesDir string) {
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() {
-			pkgDir := filepath.Join(typesDir, entry.Name())
+		if pkgDir, ok := packageDir(typesDir, entry); ok {
 			tc.loadPackageWithCache(pkgDir, "@types/"+entry.Name())
 		}
 	}
@@ -225,12 +282,11 @@ func (tc *TypeChecker) loadBundledTypes(nodeModulesDir string) {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		pkgDir, ok := packageDir(nodeModulesDir, entry)
+		if !ok {
 			continue
 		}
 
-		pkgDir := filepath.Join(nodeModulesDir, entry.Name())
-
 		// Handle scoped packages (e.g., @vue, @angular, @types)
 		if strings.HasPrefix(entry.Name(), "@") {
 			// Skip @types as it's handled separadamente
@@ -254,6 +310,26 @@ func (tc *TypeChecker) loadBundledTypes(nodeModulesDir string) {
 	}
 }
 
+// packageDir returns the directory of a node_modules entry. pnpm and
+// workspaces install packages as symlinks, which are followed so a package
+// is loaded from its real location. Hidden entries such as .pnpm and .bin
+// are not packages.
+func packageDir(parent string, entry os.DirEntry) (string, bool) {
+	if strings.HasPrefix(entry.Name(), ".") {
+		return "", false
+	}
+	path := filepath.Join(parent, entry.Name())
+	if entry.Type()&os.ModeSymlink == 0 {
+		return path, entry.IsDir()
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+	info, err := os.Stat(realPath)
+	return realPath, err == nil && info.IsDir()
+}
+
 // loadScopedPackages loads packages from a scoped directory like @vue, @angular
 func (tc *TypeChecker) loadScopedPackages(scopeDir, scopeName string) {
 	entries, err := os.ReadDir(scopeDir)
@@ -262,11 +338,11 @@ func (tc *TypeChecker) loadScopedPackages(scopeDir, scopeName string) {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		pkgDir, ok := packageDir(scopeDir, entry)
+		if !ok {
 			continue
 		}
 
-		pkgDir := filepath.Join(scopeDir, entry.Name())
 		packageJSONPath := filepath.Join(pkgDir, "package.json")
 
 		// Read package.json to find types entry point
@@ -283,36 +359,21 @@ func (tc *TypeChecker) loadScopedPackages(scopeDir, scopeName string) {
 	}
 }
 
-// getPackageTypesFile reads package.json and returns the types file path
+// getPackageTypesFile reads package.json and returns the types file path,
+// from its "types" or "typings" field or the types of its "." export
 func (tc *TypeChecker) getPackageTypesFile(packageJSONPath string) string {
-	data, err := os.ReadFile(packageJSONPath)
-	if err != nil {
-		return ""
-	}
-
-	// Simple JSON parsing to extract "types", "typings", or "exports" fields
-	var pkg struct {
-		Types   string `json:"types"`
-		Typings string `json:"typings"`
-	}
-
-	if err := json.Unmarshal(data, &pkg); err != nil {
-		return ""
-	}
-
-	if pkg.Types != "" {
-		return pkg.Types
-	}
-	if pkg.Typings != "" {
-		return pkg.Typings
-	}
-
-	// Fallback: check for common type definition files
-	return ""
+	return modules.PackageTypesFile(filepath.Dir(packageJSONPath))
 }
 
 // loadPackageWithCache loads a package's types with caching support
 func (tc *TypeChecker) loadPackageWithCache(pkgDir, pkgName string) {
+	// The cache skips reading the package, so its manifest and types entry
+	// stand for it
+	tc.addDeclarationFile(filepath.Join(pkgDir, "package.json"))
+	if typesFile := modules.PackageTypesFile(pkgDir); typesFile != "" {
+		tc.addDeclarationFile(filepath.Join(pkgDir, typesFile))
+	}
+
 	// Try to load from cache first
 	if cached, err := tc.pkgTypeCache.Load(pkgDir); err == nil {
 		// Load cached types into global environment
@@ -408,6 +469,7 @@ func (tc *TypeChecker) loadDeclarationFiles(dir string) {
 
 	// Pass 1: Extract interfaces and types (they define callable signatures)
 	for _, path := range declarationFiles {
+		tc.addDeclarationFile(path)
 		tc.extractInterfacesFromFile(path)
 	}
 
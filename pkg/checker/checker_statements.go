@@ -36,8 +36,14 @@ func (tc *TypeChecker) checkStatement(stmt ast.Statement, filename string) {
 		tc.checkExportDeclaration(s, filename)
 	case *ast.ForStatement:
 		tc.checkForStatement(s, filename)
+	case *ast.ForOfStatement:
+		tc.checkForOfStatement(s, filename)
+	case *ast.ForInStatement:
+		tc.checkForInStatement(s, filename)
 	case *ast.WhileStatement:
 		tc.checkWhileStatement(s, filename)
+	case *ast.DoWhileStatement:
+		tc.checkDoWhileStatement(s, filename)
 	case *ast.TypeAliasDeclaration:
 		tc.checkTypeAliasDeclaration(s, filename)
 	case *ast.InterfaceDeclaration:
@@ -55,9 +61,18 @@ func (tc *TypeChecker) checkStatement(stmt ast.Statement, filename string) {
 	case *ast.ContinueStatement:
 		tc.checkContinueStatement(s, filename)
 	case *ast.ModuleDeclaration:
+		if s.Global {
+			tc.checkGlobalDeclaration(s, filename)
+			return
+		}
 		// Ambient module declarations (declare module 'name' { ... })
-		// These are type-only declarations and don't need runtime checking
-		// We just skip them silently
+		// These are type-only declarations and don't need runtime checking.
+		// In a module they augment a module, which must exist.
+		if tc.moduleResolver != nil && tc.moduleResolver.IsUnresolvedAugmentation(filename, s) {
+			tc.addError(filename, s.Position.Line, s.Position.Column,
+				fmt.Sprintf("Invalid module name in augmentation, module '%s' cannot be found.", s.Name),
+				"TS2664", "error")
+		}
 		return
 	case *ast.EnumDeclaration:
 		tc.checkEnumDeclaration(s, filename)
@@ -110,16 +125,13 @@ func (tc *TypeChecker) checkReturnStatement(ret *ast.ReturnStatement, filename s
 					tc.addError(filename, ret.Argument.Pos().Line, ret.Argument.Pos().Column, msg, "TS2322", "error")
 				}
 			} else {
-				// No declared return type - validate consistency between returns
+				// No declared return type - the inferred type is the union of
+				// the types of every return
 				existingReturnType, exists := tc.typeCache[tc.currentFunction]
-				if exists {
-					if !tc.isAssignableTo(returnType, existingReturnType) {
-						msg := fmt.Sprintf("Type '%s' is not assignable to type '%s'.", returnType.String(), existingReturnType.String())
-						msg += "\n  Sugerencia: Todas las rutas de retorno deben devolver el mismo tipo"
-						tc.addError(filename, ret.Argument.Pos().Line, ret.Argument.Pos().Column, msg, "TS2322", "error")
-					}
-				} else {
+				if !exists {
 					tc.typeCache[tc.currentFunction] = returnType
+				} else if !tc.isAssignableTo(returnType, existingReturnType) {
+					tc.typeCache[tc.currentFunction] = types.NewUnionType([]*types.Type{existingReturnType, returnType})
 				}
 			}
 		}
@@ -165,19 +177,8 @@ func (tc *TypeChecker) checkIfStatement(stmt *ast.IfStatement, filename string)
 				return
 			}
 
-			// Type guard 1: if (variable instanceof Function)
-			if binExpr.Operator == "instanceof" {
-				if leftId, ok := binExpr.Left.(*ast.Identifier); ok {
-					if rightId, ok := binExpr.Right.(*ast.Identifier); ok {
-						if rightId.Name == "Function" {
-							typeGuardVars = append(typeGuardVars, leftId.Name)
-							tc.typeGuards[leftId.Name] = true
-						}
-					}
-				}
-			}
-
-			// Type guard 2: if (typeof variable === 'function')
+			// Type guard: if (typeof variable === 'function'). instanceof is
+			// narrowed by AnalyzeCondition.
 			if binExpr.Operator == "===" || binExpr.Operator == "==" {
 				// Check for: typeof variable === 'function'
 				if unaryExpr, ok := binExpr.Left.(*ast.UnaryExpression); ok {
@@ -237,6 +238,9 @@ func (tc *TypeChecker) checkIfStatement(stmt *ast.IfStatement, filename string)
 }
 
 func (tc *TypeChecker) checkForStatement(stmt *ast.ForStatement, filename string) {
+	// After the loop, its assignments may have happened any number of times
+	defer tc.typeNarrowing.WidenAssigned(stmt)
+
 	// Find the for statement scope
 	forScope := tc.findScopeForNode(stmt)
 	if forScope != nil {
@@ -252,6 +256,7 @@ func (tc *TypeChecker) checkForStatement(stmt *ast.ForStatement, filename string
 				tc.checkExpression(init.Expression, filename)
 			}
 		}
+		tc.typeNarrowing.WidenAssigned(stmt.Test, stmt.Update, stmt.Body)
 
 		// Check test
 		if stmt.Test != nil {
@@ -279,6 +284,7 @@ func (tc *TypeChecker) checkForStatement(stmt *ast.ForStatement, filename string
 				tc.checkExpression(init.Expression, filename)
 			}
 		}
+		tc.typeNarrowing.WidenAssigned(stmt.Test, stmt.Update, stmt.Body)
 
 		if stmt.Test != nil {
 			tc.checkExpression(stmt.Test, filename)
@@ -294,7 +300,212 @@ func (tc *TypeChecker) checkForStatement(stmt *ast.ForStatement, filename string
 	}
 }
 
+func (tc *TypeChecker) checkForOfStatement(stmt *ast.ForOfStatement, filename string) {
+	// The iterable is evaluated in the enclosing scope
+	elementType := types.Any
+	if stmt.Right != nil {
+		tc.checkExpression(stmt.Right, filename)
+
+		iterableType := tc.inferencer.InferType(stmt.Right)
+		if elemType, ok := tc.iteratedElementType(iterableType, stmt.Await); ok {
+			elementType = elemType
+		} else if stmt.Await {
+			tc.addError(filename, stmt.Right.Pos().Line, stmt.Right.Pos().Column,
+				fmt.Sprintf("Type '%s' must have a '[Symbol.asyncIterator]()' method that returns an async iterator.", iterableType.String()),
+				"TS2504", "error")
+		} else {
+			tc.addError(filename, stmt.Right.Pos().Line, stmt.Right.Pos().Column,
+				fmt.Sprintf("Type '%s' must have a '[Symbol.iterator]()' method that returns an iterator.", iterableType.String()),
+				"TS2488", "error")
+		}
+	}
+
+	tc.typeNarrowing.WidenAssigned(stmt.Body)
+	tc.checkLoopBody(stmt, stmt.Left, elementType, stmt.Body, filename)
+	tc.typeNarrowing.WidenAssigned(stmt.Body)
+}
+
+func (tc *TypeChecker) checkForInStatement(stmt *ast.ForInStatement, filename string) {
+	if stmt.Right != nil {
+		tc.checkExpression(stmt.Right, filename)
+	}
+
+	// for...in always iterates over string keys
+	tc.typeNarrowing.WidenAssigned(stmt.Body)
+	tc.checkLoopBody(stmt, stmt.Left, types.String, stmt.Body, filename)
+	tc.typeNarrowing.WidenAssigned(stmt.Body)
+}
+
+// checkLoopBody types the loop variable of a for...of/for...in loop and checks
+// the body inside the loop scope
+func (tc *TypeChecker) checkLoopBody(stmt ast.Statement, left ast.Node, elementType *types.Type, body ast.Statement, filename string) {
+	loopScope := tc.findScopeForNode(stmt)
+	if loopScope != nil {
+		originalScope := tc.symbolTable.Current
+		tc.symbolTable.Current = loopScope
+		defer func() { tc.symbolTable.Current = originalScope }()
+	}
+
+	switch l := left.(type) {
+	case *ast.VariableDeclaration:
+		destructured := len(l.Decls) > 1
+		for i, declarator := range l.Decls {
+			if declarator.ID == nil {
+				continue
+			}
+			// Destructuring patterns produce identifiers spanning the whole pattern
+			if declarator.ID.End().Offset-declarator.ID.Pos().Offset != len(declarator.ID.Name) {
+				destructured = true
+			}
+
+			varType := elementType
+			if destructured {
+				varType = destructuredElementType(elementType, i, declarator.ID.Name)
+			}
+
+			tc.typeCache[declarator.ID] = varType
+			tc.varTypeCache[declarator.ID.Name] = varType
+			if loopScope != nil {
+				if symbol, exists := loopScope.Symbols[declarator.ID.Name]; exists {
+					symbol.ResolvedType = varType
+				}
+			}
+		}
+	case *ast.ExpressionStatement:
+		tc.checkExpression(l.Expression, filename)
+	}
+
+	if body != nil {
+		tc.checkStatement(body, filename)
+	}
+}
+
+// iteratedElementType returns the type produced by iterating over t with
+// for...of (for await...of when isAwait), or false if t is not iterable
+func (tc *TypeChecker) iteratedElementType(t *types.Type, isAwait bool) (*types.Type, bool) {
+	if t == nil {
+		return types.Any, true
+	}
+
+	unwrap := func(elem *types.Type) *types.Type {
+		if isAwait {
+			return tc.unwrapPromiseType(elem)
+		}
+		return elem
+	}
+
+	switch t.Kind {
+	case types.ArrayType:
+		if t.ElementType == nil {
+			return types.Any, true
+		}
+		return unwrap(t.ElementType), true
+	case types.TupleType:
+		if len(t.Types) == 0 {
+			return types.Never, true
+		}
+		if len(t.Types) == 1 {
+			return unwrap(t.Types[0]), true
+		}
+		return unwrap(types.NewUnionType(t.Types)), true
+	case types.StringType:
+		return types.String, true
+	case types.LiteralType:
+		if _, ok := t.Value.(string); ok {
+			return types.String, true
+		}
+		return nil, false
+	case types.NumberType, types.BooleanType, types.BigIntType, types.SymbolType,
+		types.NullType, types.UndefinedType, types.VoidType, types.NeverType:
+		return nil, false
+	case types.UnionType:
+		var elements []*types.Type
+		for _, member := range t.Types {
+			elem, ok := tc.iteratedElementType(member, isAwait)
+			if !ok {
+				return nil, false
+			}
+			elements = append(elements, elem)
+		}
+		if len(elements) == 1 {
+			return elements[0], true
+		}
+		return types.NewUnionType(elements), true
+	case types.ObjectType:
+		args := t.TypeParameters
+		switch t.Name {
+		case "Map", "ReadonlyMap":
+			if len(args) == 2 {
+				return types.NewTupleType([]*types.Type{args[0], args[1]}), true
+			}
+			return types.Any, true
+		case "Set", "ReadonlySet", "Iterable", "IterableIterator", "Iterator", "Generator",
+			"ArrayLike", "ReadonlyArray", "Array", "IteratorObject", "MapIterator", "SetIterator",
+			"ArrayIterator", "NodeListOf", "HTMLCollectionOf":
+			if len(args) >= 1 {
+				return unwrap(args[0]), true
+			}
+			return types.Any, true
+		case "NodeList", "HTMLCollection", "IArguments", "Int8Array", "Uint8Array",
+			"Uint8ClampedArray", "Int16Array", "Uint16Array", "Int32Array", "Uint32Array",
+			"Float32Array", "Float64Array", "BigInt64Array", "BigUint64Array":
+			return types.Any, true
+		case "AsyncIterable", "AsyncIterableIterator", "AsyncGenerator":
+			if !isAwait {
+				return nil, false
+			}
+			if len(args) >= 1 {
+				return unwrap(args[0]), true
+			}
+			return types.Any, true
+		case "String":
+			return types.String, true
+		}
+		// Classes and interfaces may declare [Symbol.iterator]
+		if _, ok := t.Properties["[Symbol.iterator]"]; ok {
+			return types.Any, true
+		}
+		if _, ok := t.Properties["[Symbol.asyncIterator]"]; ok && isAwait {
+			return types.Any, true
+		}
+		if t.Name != "" && len(t.Properties) == 0 && len(t.CallSignatures) == 0 {
+			// A named type that did not resolve
+			return types.Any, true
+		}
+		return nil, false
+	case types.FunctionType:
+		return nil, false
+	}
+
+	// Type parameters and types that did not resolve
+	return types.Any, true
+}
+
+// destructuredElementType picks the type of the index-th binding of a
+// destructuring pattern over elementType
+func destructuredElementType(elementType *types.Type, index int, name string) *types.Type {
+	switch elementType.Kind {
+	case types.TupleType:
+		if index < len(elementType.Types) {
+			return elementType.Types[index]
+		}
+	case types.ArrayType:
+		if elementType.ElementType != nil {
+			return elementType.ElementType
+		}
+	case types.ObjectType:
+		if propType, exists := elementType.Properties[name]; exists {
+			return propType
+		}
+	}
+	return types.Any
+}
+
 func (tc *TypeChecker) checkWhileStatement(stmt *ast.WhileStatement, filename string) {
+	// Each iteration may follow the assignments of the one before
+	tc.typeNarrowing.WidenAssigned(stmt)
+	defer tc.typeNarrowing.WidenAssigned(stmt)
+
 	// Check test
 	tc.checkExpression(stmt.Test, filename)
 
@@ -304,6 +515,18 @@ func (tc *TypeChecker) checkWhileStatement(stmt *ast.WhileStatement, filename st
 	}
 }
 
+func (tc *TypeChecker) checkDoWhileStatement(stmt *ast.DoWhileStatement, filename string) {
+	// The test runs after the body, and each iteration may follow the
+	// assignments of the one before
+	tc.typeNarrowing.WidenAssigned(stmt)
+	defer tc.typeNarrowing.WidenAssigned(stmt)
+
+	if stmt.Body != nil {
+		tc.checkStatement(stmt.Body, filename)
+	}
+	tc.checkExpression(stmt.Test, filename)
+}
+
 // checkSwitchStatement checks switch statements
 func (tc *TypeChecker) checkSwitchStatement(stmt *ast.SwitchStatement, filename string) {
 	// Check discriminant (the expression being switched on)
@@ -311,29 +534,72 @@ func (tc *TypeChecker) checkSwitchStatement(stmt *ast.SwitchStatement, filename
 		tc.checkExpression(stmt.Discriminant, filename)
 	}
 
-	// Check each case
+	caseNarrowings, noMatch, exhaustive := tc.typeNarrowing.AnalyzeSwitch(stmt)
+	if exhaustive {
+		tc.typeNarrowing.exhaustiveSwitches[stmt] = true
+	}
+
+	// Variables assigned in the cases get the union of their types at the end
+	// of the cases that leave the switch, and before it when no case matches
+	join := tc.typeNarrowing.beginBranches(stmt)
+	hasDefault := false
 	for _, switchCase := range stmt.Cases {
+		hasDefault = hasDefault || switchCase.Test == nil
+	}
+	if !hasDefault && !exhaustive {
+		join.endBranch(false)
+	}
+
+	// Check each case
+	for i, switchCase := range stmt.Cases {
 		// Check test expression (nil for default case)
 		if switchCase.Test != nil {
 			tc.checkExpression(switchCase.Test, filename)
 		}
 
-		// Check consequent statements
+		// Check consequent statements with the discriminated variable narrowed
+		// to the members the case handles
+		restore := tc.typeNarrowing.ApplyNarrowing(caseNarrowings[i])
+		exits := tc.listCompletesAbruptly(switchCase.Consequent, true)
 		for _, consequent := range switchCase.Consequent {
 			tc.checkStatement(consequent, filename)
+			exits = exits && !breaksOut(consequent)
+		}
+		restore()
+		join.endBranch(exits)
+	}
+	join.finish()
+
+	// When every case exits, the code after the switch only runs when no case
+	// matched
+	if len(noMatch) > 0 && tc.casesCompleteAbruptly(stmt, true) {
+		for varName, narrowedType := range noMatch {
+			tc.varTypeCache[varName] = narrowedType
 		}
 	}
 }
 
 // checkTryStatement checks try-catch-finally statements
 func (tc *TypeChecker) checkTryStatement(stmt *ast.TryStatement, filename string) {
+	// Variables assigned in the try block or the catch clause get the union of
+	// their types at the end of both
+	branches := []ast.Node{stmt.Block}
+	if stmt.Handler != nil {
+		branches = append(branches, stmt.Handler)
+	}
+	join := tc.typeNarrowing.beginBranches(branches...)
+
 	// Check the try block
 	if stmt.Block != nil {
 		tc.checkBlockStatement(stmt.Block, filename)
 	}
+	join.endBranch(stmt.Block != nil && tc.completesAbruptly(stmt.Block, true))
 
 	// Check the catch clause
 	if stmt.Handler != nil {
+		// The catch clause may run after any statement of the try block
+		tc.typeNarrowing.WidenAssigned(stmt.Block)
+
 		// Create a new scope for the catch clause
 		tc.symbolTable.EnterScope(stmt.Handler)
 
@@ -349,9 +615,11 @@ func (tc *TypeChecker) checkTryStatement(stmt *ast.TryStatement, filename string
 		if stmt.Handler.Body != nil {
 			tc.checkBlockStatement(stmt.Handler.Body, filename)
 		}
+		join.endBranch(stmt.Handler.Body != nil && tc.completesAbruptly(stmt.Handler.Body, true))
 
 		tc.symbolTable.ExitScope()
 	}
+	join.finish()
 
 	// Check the finally block
 	if stmt.Finalizer != nil {
@@ -193,6 +193,7 @@ func (tc *TypeChecker) registerFunctionType(decl *ast.FunctionDeclaration, filen
 					constraint = tc.convertTypeNode(typeParam.Constraint)
 				}
 				typeParams[i] = types.NewTypeParameter(typeParam.Name.Name, constraint, nil)
+				typeParams[i].IsConst = typeParam.Const
 			}
 		}
 		fnType = types.NewGenericFunctionType(typeParams, paramTypes, returnType)
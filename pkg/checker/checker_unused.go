@@ -0,0 +1,254 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/symbols"
+)
+
+// unusedDeclarations reports the locals and parameters of file that are never
+// read, under noUnusedLocals and noUnusedParameters. It must run right after
+// binding, before imports replace the symbols of the file scope.
+func (tc *TypeChecker) unusedDeclarations(file *ast.File, filename string, fileScope *symbols.Scope) []TypeError {
+	config := tc.GetConfig()
+	if (!config.NoUnusedLocals && !config.NoUnusedParameters) || strings.HasSuffix(filename, ".d.ts") {
+		return nil
+	}
+
+	var diagnostics []TypeError
+	for _, scope := range symbols.MarkReferences(file, fileScope) {
+		// Top-level declarations of scripts are globals, and the template of
+		// a .vue component reads the bindings of <script setup>
+		if scope.Level == 0 || (scope == fileScope && file.ScriptSetup) {
+			continue
+		}
+		if config.NoUnusedLocals {
+			diagnostics = append(diagnostics, unusedLocals(scope, filename)...)
+		}
+		if config.NoUnusedParameters {
+			diagnostics = append(diagnostics, unusedParameters(scope, filename)...)
+		}
+	}
+	if config.NoUnusedParameters {
+		diagnostics = append(diagnostics, unusedTypeParameters(file, filename)...)
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+	return diagnostics
+}
+
+// unusedLocals reports the variables, functions, imports, types and private
+// class members declared in scope that are never read
+func unusedLocals(scope *symbols.Scope, filename string) []TypeError {
+	var diagnostics []TypeError
+	for _, symbol := range scope.Symbols {
+		if symbol.References > 0 || symbol.FromDTS {
+			continue
+		}
+
+		var name *ast.Identifier
+		code := "TS6133"
+		switch node := symbol.Node.(type) {
+		case *ast.VariableDeclarator:
+			if isLoopScope(scope) && strings.HasPrefix(symbol.Name, "_") {
+				continue
+			}
+			name = node.ID
+		case *ast.FunctionDeclaration:
+			name = node.ID
+		case *ast.NamespaceDeclaration:
+			name = node.Name
+		case *ast.ImportDeclaration:
+			name = &ast.Identifier{Name: symbol.Name, Position: symbol.DeclSpan}
+		case *ast.TypeAliasDeclaration:
+			name, code = node.ID, "TS6196"
+		case *ast.InterfaceDeclaration:
+			name, code = node.ID, "TS6196"
+		case *ast.ClassDeclaration:
+			name, code = node.ID, "TS6196"
+		case *ast.EnumDeclaration:
+			name, code = node.Name, "TS6196"
+		case *ast.MethodDefinition:
+			if node.AccessModifier == "private" && node.Kind != "constructor" {
+				name = node.Key
+			}
+		case *ast.PropertyDefinition:
+			if node.AccessModifier == "private" {
+				name = node.Key
+			}
+		case *ast.Parameter:
+			// Private parameter properties are class members
+			if node.Private {
+				name, code = node.ID, "TS6138"
+			}
+		}
+		if name == nil {
+			continue
+		}
+
+		// Destructured declarators are named after the pattern
+		pos := name.Pos()
+		if name.Name != symbol.Name {
+			pos = symbol.DeclSpan
+		}
+		diagnostics = append(diagnostics, unusedDiagnostic(filename, symbol.Name, pos, code))
+	}
+	return diagnostics
+}
+
+// unusedParameters reports the parameters of the function that created scope
+// which are never read. Parameters before the last one read are needed for
+// its position, and names starting with _ are intentionally unused.
+func unusedParameters(scope *symbols.Scope, filename string) []TypeError {
+	var params []*ast.Parameter
+	switch fn := scope.Node.(type) {
+	case *ast.FunctionDeclaration:
+		if fn.Body == nil {
+			return nil
+		}
+		params = fn.Params
+	case *ast.FunctionExpression:
+		params = fn.Params
+	case *ast.ArrowFunctionExpression:
+		params = fn.Params
+	case *ast.MethodDefinition:
+		// A setter must declare its parameter
+		if fn.Kind == "set" || fn.Value == nil {
+			return nil
+		}
+		params = fn.Value.Params
+	default:
+		return nil
+	}
+
+	lastRead := -1
+	for i, param := range params {
+		if symbol := parameterSymbol(scope, param); symbol != nil && symbol.References > 0 {
+			lastRead = i
+		}
+	}
+
+	var diagnostics []TypeError
+	for _, param := range params[lastRead+1:] {
+		symbol := parameterSymbol(scope, param)
+		if symbol == nil || symbol.References > 0 {
+			continue
+		}
+		if strings.HasPrefix(param.ID.Name, "_") || param.ID.Name == "this" {
+			continue
+		}
+		// Parameter properties are class members
+		if symbols.IsParameterProperty(param) {
+			continue
+		}
+		diagnostics = append(diagnostics, unusedDiagnostic(filename, param.ID.Name, param.ID.Pos(), "TS6133"))
+	}
+	return diagnostics
+}
+
+// unusedTypeParameters reports the type parameters of the generic
+// declarations of file that no type refers to. When every type parameter of a
+// list of several is unused, the list is reported once instead.
+func unusedTypeParameters(file *ast.File, filename string) []TypeError {
+	var diagnostics []TypeError
+	ast.Inspect(file, func(node ast.Node) bool {
+		var params []ast.TypeNode
+		switch decl := node.(type) {
+		case *ast.FunctionDeclaration:
+			// Overload signatures share the type parameters of the implementation
+			if decl.Body != nil {
+				params = decl.TypeParameters
+			}
+		case *ast.ClassDeclaration:
+			params = decl.TypeParameters
+		case *ast.InterfaceDeclaration:
+			params = decl.TypeParameters
+		case *ast.TypeAliasDeclaration:
+			params = decl.TypeParameters
+		}
+		if len(params) == 0 {
+			return true
+		}
+
+		referenced := typeReferenceNames(node)
+		var unused []*ast.TypeParameter
+		for _, param := range params {
+			typeParam, ok := param.(*ast.TypeParameter)
+			if !ok || typeParam.Name == nil || referenced[typeParam.Name.Name] || strings.HasPrefix(typeParam.Name.Name, "_") {
+				continue
+			}
+			unused = append(unused, typeParam)
+		}
+		if len(params) > 1 && len(unused) == len(params) {
+			diagnostics = append(diagnostics, unusedDiagnostic(filename, "", unused[0].Name.Pos(), "TS6205"))
+			return true
+		}
+		for _, typeParam := range unused {
+			diagnostics = append(diagnostics, unusedDiagnostic(filename, typeParam.Name.Name, typeParam.Name.Pos(), "TS6133"))
+		}
+		return true
+	})
+	return diagnostics
+}
+
+// typeReferenceNames returns the names the type references in node start with
+func typeReferenceNames(node ast.Node) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ref, ok := n.(*ast.TypeReference); ok {
+			name, _, _ := strings.Cut(ref.Name, ".")
+			names[name] = true
+		}
+		return true
+	})
+	return names
+}
+
+// parameterSymbol returns the symbol param declares in scope
+func parameterSymbol(scope *symbols.Scope, param *ast.Parameter) *symbols.Symbol {
+	if param.ID == nil {
+		return nil
+	}
+	symbol, ok := scope.Symbols[param.ID.Name]
+	if !ok || symbol.Node != ast.Node(param) {
+		return nil
+	}
+	return symbol
+}
+
+// isLoopScope reports whether scope holds the variable of a for...of/for...in loop
+func isLoopScope(scope *symbols.Scope) bool {
+	switch scope.Node.(type) {
+	case *ast.ForOfStatement, *ast.ForInStatement:
+		return true
+	}
+	return false
+}
+
+func unusedDiagnostic(filename, name string, pos ast.Position, code string) TypeError {
+	message := fmt.Sprintf("'%s' is declared but its value is never read.", name)
+	switch code {
+	case "TS6196":
+		message = fmt.Sprintf("'%s' is declared but never used.", name)
+	case "TS6138":
+		message = fmt.Sprintf("Property '%s' is declared but its value is never read.", name)
+	case "TS6205":
+		message = "All type parameters are unused."
+	}
+	return TypeError{
+		File:     filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Message:  message,
+		Code:     code,
+		Severity: "error",
+	}
+}
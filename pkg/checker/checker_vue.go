@@ -0,0 +1,271 @@
+package checker
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/symbols"
+	"tstypechecker/pkg/types"
+)
+
+// vueMacros are the compiler macros available in <script setup> without an import
+var vueMacros = []string{
+	"defineProps", "defineEmits", "defineModel", "defineExpose",
+	"defineOptions", "defineSlots", "withDefaults",
+}
+
+// vuePropConstructors maps the constructors of runtime prop declarations
+// (defineProps({ title: String })) to the types they declare
+var vuePropConstructors = map[string]*types.Type{
+	"String":  types.String,
+	"Number":  types.Number,
+	"Boolean": types.Boolean,
+}
+
+// isVueFileName reports whether filename is a Vue single file component
+func isVueFileName(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".vue"
+}
+
+// isVueJavaScript reports whether the <script> blocks of a .vue file are
+// JavaScript, which is only checked like a .js file
+func isVueJavaScript(file *ast.File, filename string) bool {
+	if !isVueFileName(filename) {
+		return false
+	}
+	return file.ScriptLang != "ts" && file.ScriptLang != "tsx"
+}
+
+// loadVueMacros declares the compiler macros of a <script setup> block and
+// types their calls from their type arguments
+func (tc *TypeChecker) loadVueMacros(file *ast.File, filename string) {
+	tc.inferencer.SetCallTypeResolver(nil)
+	if !isVueFileName(filename) || !file.ScriptSetup {
+		return
+	}
+
+	for _, name := range vueMacros {
+		if _, exists := tc.symbolTable.ResolveSymbol(name); !exists {
+			symbol := tc.symbolTable.DefineSymbol(name, symbols.FunctionSymbol, nil, false)
+			symbol.IsFunction = true
+		}
+	}
+	tc.inferencer.SetCallTypeResolver(tc.vueMacroType)
+}
+
+// vueMacroType returns the type of a compiler macro call, or nil when call is
+// not one
+func (tc *TypeChecker) vueMacroType(call *ast.CallExpression) *types.Type {
+	id, ok := call.Callee.(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+	if symbol, exists := tc.symbolTable.ResolveSymbol(id.Name); exists && symbol.Node != nil {
+		// A declaration of the same name shadows the macro
+		return nil
+	}
+
+	switch id.Name {
+	case "defineProps":
+		return tc.vuePropsType(call)
+	case "withDefaults":
+		return tc.vueWithDefaultsType(call)
+	case "defineEmits":
+		return tc.vueEmitsType(call)
+	case "defineModel":
+		return tc.vueModelType(call)
+	case "defineSlots":
+		if len(call.TypeArguments) > 0 {
+			return tc.convertTypeNode(call.TypeArguments[0])
+		}
+		return types.Any
+	case "defineExpose", "defineOptions":
+		return types.Void
+	}
+	return nil
+}
+
+// vuePropsType types defineProps<Props>() and the runtime declarations
+// defineProps({ title: String, count: { type: Number, required: true } })
+// and defineProps(['title'])
+func (tc *TypeChecker) vuePropsType(call *ast.CallExpression) *types.Type {
+	if len(call.TypeArguments) > 0 {
+		return tc.convertTypeNode(call.TypeArguments[0])
+	}
+	if len(call.Arguments) == 0 {
+		return types.NewObjectType("", map[string]*types.Type{})
+	}
+
+	properties := make(map[string]*types.Type)
+	switch decl := call.Arguments[0].(type) {
+	case *ast.ArrayExpression:
+		for _, elem := range decl.Elements {
+			if lit, ok := elem.(*ast.Literal); ok {
+				if name, ok := lit.Value.(string); ok {
+					properties[name] = types.Any
+				}
+			}
+		}
+	case *ast.ObjectExpression:
+		for _, prop := range decl.Properties {
+			p, ok := prop.(*ast.Property)
+			if !ok {
+				return types.Any
+			}
+			name := propertyKeyName(p.Key)
+			if name == "" {
+				return types.Any
+			}
+			propType, required := tc.vueRuntimePropType(p.Value)
+			if !required {
+				propType = types.NewUnionType([]*types.Type{propType, types.Undefined})
+			}
+			properties[name] = propType
+		}
+	default:
+		return types.Any
+	}
+	return types.NewObjectType("", properties)
+}
+
+// vueRuntimePropType types one runtime prop declaration: String, [String,
+// Number] or { type: String, required: true }
+func (tc *TypeChecker) vueRuntimePropType(value ast.Expression) (*types.Type, bool) {
+	switch v := value.(type) {
+	case *ast.Identifier:
+		if t, ok := vuePropConstructors[v.Name]; ok {
+			return t, false
+		}
+		if v.Name == "Array" {
+			return types.NewArrayType(types.Any), false
+		}
+	case *ast.ArrayExpression:
+		var members []*types.Type
+		for _, elem := range v.Elements {
+			t, _ := tc.vueRuntimePropType(elem)
+			if t.Kind == types.AnyType {
+				return types.Any, false
+			}
+			members = append(members, t)
+		}
+		if len(members) == 1 {
+			return members[0], false
+		}
+		if len(members) > 1 {
+			return types.NewUnionType(members), false
+		}
+	case *ast.ObjectExpression:
+		propType, required := types.Any, false
+		for _, prop := range v.Properties {
+			p, ok := prop.(*ast.Property)
+			if !ok {
+				continue
+			}
+			switch propertyKeyName(p.Key) {
+			case "type":
+				propType, _ = tc.vueRuntimePropType(p.Value)
+			case "required":
+				if lit, ok := p.Value.(*ast.Literal); ok && lit.Value == true {
+					required = true
+				}
+			case "default":
+				// Props with a default are never undefined inside the component
+				required = true
+			}
+		}
+		return propType, required
+	}
+	return types.Any, false
+}
+
+// vueWithDefaultsType types withDefaults(defineProps<Props>(), defaults): the
+// props that have a default are no longer optional
+func (tc *TypeChecker) vueWithDefaultsType(call *ast.CallExpression) *types.Type {
+	if len(call.Arguments) == 0 {
+		return types.Any
+	}
+	props := tc.inferencer.InferType(call.Arguments[0])
+	if props.Kind != types.ObjectType || len(call.Arguments) < 2 {
+		return props
+	}
+	defaults, ok := call.Arguments[1].(*ast.ObjectExpression)
+	if !ok {
+		return props
+	}
+
+	properties := make(map[string]*types.Type, len(props.Properties))
+	for name, t := range props.Properties {
+		properties[name] = t
+	}
+	for _, prop := range defaults.Properties {
+		if p, ok := prop.(*ast.Property); ok {
+			name := propertyKeyName(p.Key)
+			if t, exists := properties[name]; exists && t.Kind == types.UnionType {
+				properties[name] = withoutUndefined(t)
+			}
+		}
+	}
+	return types.NewObjectType(props.Name, properties)
+}
+
+// vueEmitsType types defineEmits<{ (e: 'change', id: number): void }>() and the
+// shorthand defineEmits<{ change: [id: number] }>() as the emit function
+func (tc *TypeChecker) vueEmitsType(call *ast.CallExpression) *types.Type {
+	if len(call.TypeArguments) == 0 {
+		// Runtime declarations only list the event names
+		return types.Any
+	}
+	emits := tc.convertTypeNode(call.TypeArguments[0])
+	if emits.Kind != types.ObjectType || len(emits.CallSignatures) > 0 {
+		return emits
+	}
+
+	emit := types.NewObjectType("", map[string]*types.Type{})
+	for _, event := range sortedKeys(emits.Properties) {
+		payload := emits.Properties[event]
+		if payload.Kind != types.TupleType {
+			return types.Any
+		}
+		params := append([]*types.Type{types.NewLiteralType(event)}, payload.Types...)
+		emit.CallSignatures = append(emit.CallSignatures, types.NewFunctionType(params, types.Void))
+	}
+	return emit
+}
+
+// vueModelType types defineModel<T>() as a ref whose value is T, or T |
+// undefined unless the model is declared with { required: true }
+func (tc *TypeChecker) vueModelType(call *ast.CallExpression) *types.Type {
+	value := types.Any
+	if len(call.TypeArguments) > 0 {
+		value = tc.convertTypeNode(call.TypeArguments[0])
+
+		required := false
+		for _, arg := range call.Arguments {
+			if options, ok := arg.(*ast.ObjectExpression); ok {
+				_, required = tc.vueRuntimePropType(options)
+			}
+		}
+		if !required {
+			value = types.NewUnionType([]*types.Type{value, types.Undefined})
+		}
+	}
+
+	return types.NewObjectType(fmt.Sprintf("ModelRef<%s>", value.String()), map[string]*types.Type{
+		"value": value,
+	})
+}
+
+// propertyKeyName returns the name of an identifier or string property key
+func propertyKeyName(key ast.Expression) string {
+	switch k := key.(type) {
+	case *ast.Identifier:
+		return k.Name
+	case *ast.Literal:
+		if name, ok := k.Value.(string); ok {
+			return name
+		}
+	}
+	return ""
+}
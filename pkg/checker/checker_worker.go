@@ -35,6 +35,7 @@ func NewForWorker(resolver *modules.ModuleResolver, symbolTable *symbols.SymbolT
 		loadedLibFiles:     make(map[string]bool),
 		profiler:           NewPerformanceProfiler(),
 		conversionStack:    make(map[ast.TypeNode]bool),
+		jsxLibraries:       newJSXLibraryCache(),
 	}
 
 	// Note: Types are NOT loaded here to avoid redundant I/O in worker threads.
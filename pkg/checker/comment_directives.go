@@ -0,0 +1,100 @@
+package checker
+
+import (
+	"path/filepath"
+	"strings"
+
+	"tstypechecker/pkg/ast"
+)
+
+// shouldCheckFile decides whether a file gets semantic diagnostics at all.
+// TypeScript files are checked unless they contain // @ts-nocheck; JavaScript
+// files only when checkJs is enabled or they opt in with // @ts-check.
+func (tc *TypeChecker) shouldCheckFile(file *ast.File, filename string) bool {
+	if file == nil {
+		return true
+	}
+	if file.HasDirective(ast.DirectiveNoCheck) {
+		return false
+	}
+	if isJavaScriptFileName(filename) || isVueJavaScript(file, filename) {
+		return tc.GetConfig().CheckJs || file.HasDirective(ast.DirectiveCheck)
+	}
+	return true
+}
+
+// applyCommentDirectives drops the diagnostics suppressed by // @ts-ignore and
+// // @ts-expect-error comments and reports TS2578 for expect-error comments
+// that did not suppress anything
+func (tc *TypeChecker) applyCommentDirectives(file *ast.File, filename string, errors []TypeError) []TypeError {
+	if file == nil || len(file.Directives) == 0 {
+		return errors
+	}
+
+	// Suppressing directives keyed by the line they are written on
+	directivesByLine := make(map[int]*ast.CommentDirective)
+	for _, d := range file.Directives {
+		if d.Kind == ast.DirectiveIgnore || d.Kind == ast.DirectiveExpectError {
+			directivesByLine[d.Position.Line] = d
+		}
+	}
+	if len(directivesByLine) == 0 {
+		return errors
+	}
+
+	lines := strings.Split(file.Source, "\n")
+	used := make(map[*ast.CommentDirective]bool)
+
+	kept := errors[:0]
+	for _, e := range errors {
+		if e.File == "" || e.File == filename {
+			if d := precedingDirective(e.Line, directivesByLine, lines); d != nil {
+				used[d] = true
+				continue
+			}
+		}
+		kept = append(kept, e)
+	}
+
+	for _, d := range file.Directives {
+		if d.Kind == ast.DirectiveExpectError && !used[d] {
+			kept = append(kept, TypeError{
+				File:     filename,
+				Line:     d.Position.Line,
+				Column:   d.Position.Column,
+				Message:  "Unused '@ts-expect-error' directive.",
+				Code:     "TS2578",
+				Severity: "error",
+			})
+		}
+	}
+
+	return kept
+}
+
+// precedingDirective finds the directive that applies to line: the closest one
+// above it, skipping only blank lines and other // comments like tsc does
+func precedingDirective(line int, directivesByLine map[int]*ast.CommentDirective, lines []string) *ast.CommentDirective {
+	for l := line - 1; l >= 1; l-- {
+		if d, ok := directivesByLine[l]; ok {
+			return d
+		}
+		if l > len(lines) {
+			continue
+		}
+		text := strings.TrimSpace(lines[l-1])
+		if text != "" && !strings.HasPrefix(text, "//") {
+			return nil
+		}
+	}
+	return nil
+}
+
+// isJavaScriptFileName reports whether filename is a JavaScript source file
+func isJavaScriptFileName(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".js", ".jsx", ".mjs", ".cjs":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"testing"
+
+	"tstypechecker/pkg/parser"
+)
+
+func TestApplyCommentDirectives(t *testing.T) {
+	code := `// @ts-ignore
+const a: number = "x";
+
+// @ts-expect-error: explanation
+// another comment
+const b: string = 1;
+
+// @ts-expect-error
+const c = 1;
+const d: number = "y";
+`
+	file, err := parser.ParseCode(code, "test.ts")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	if len(file.Directives) != 3 {
+		t.Fatalf("expected 3 directives, got %d", len(file.Directives))
+	}
+
+	errors := []TypeError{
+		{File: "test.ts", Line: 2, Column: 19, Code: "TS2322"},
+		{File: "test.ts", Line: 6, Column: 19, Code: "TS2322"},
+		{File: "test.ts", Line: 10, Column: 19, Code: "TS2322"},
+	}
+
+	tc := &TypeChecker{}
+	result := tc.applyCommentDirectives(file, "test.ts", errors)
+
+	want := []struct {
+		line int
+		code string
+	}{
+		{10, "TS2322"}, // Not directly below a directive
+		{8, "TS2578"},  // Unused @ts-expect-error
+	}
+	if len(result) != len(want) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(want), len(result), result)
+	}
+	for i, w := range want {
+		if result[i].Line != w.line || result[i].Code != w.code {
+			t.Errorf("diagnostic %d = %s at line %d, want %s at line %d", i, result[i].Code, result[i].Line, w.code, w.line)
+		}
+	}
+}
+
+func TestShouldCheckFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		code     string
+		checkJs  bool
+		want     bool
+	}{
+		{"ts file", "a.ts", "const a = 1;", false, true},
+		{"ts file with nocheck", "a.ts", "// @ts-nocheck\nconst a = 1;", false, false},
+		{"js file without checkJs", "a.js", "const a = 1;", false, false},
+		{"js file with checkJs", "a.js", "const a = 1;", true, true},
+		{"js file with ts-check", "a.js", "// @ts-check\nconst a = 1;", false, true},
+		{"js file with checkJs and nocheck", "a.js", "// @ts-nocheck\nconst a = 1;", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseCode(tt.code, tt.filename)
+			if err != nil {
+				t.Fatalf("ParseCode() error = %v", err)
+			}
+			tc := &TypeChecker{config: &CompilerConfig{CheckJs: tt.checkJs}}
+			if got := tc.shouldCheckFile(file, tt.filename); got != tt.want {
+				t.Errorf("shouldCheckFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package checker
+
+import "testing"
+
+func TestConstAssertions(t *testing.T) {
+	runDiagnosticCases(t, nil, []diagnosticCase{
+		{
+			name: "object",
+			code: `const routes = { home: "/", about: "/about" } as const;
+type RouteKey = keyof typeof routes;
+type RoutePath = (typeof routes)[RouteKey];
+const key: RouteKey = "home";
+const badKey: RouteKey = "blog";
+const path: RoutePath = "/about";
+routes.home = "/index";
+`,
+			want: []diagnostic{
+				{line: 5, code: "TS2322"}, // "blog" is not a route
+				{line: 7, code: "TS2540"}, // routes is readonly
+			},
+		},
+		{
+			name: "array",
+			code: `const sizes = [1, 2, -3] as const;
+type Size = (typeof sizes)[number];
+const size: Size = -3;
+const badSize: Size = 4;
+const first: 1 = sizes[0];
+sizes[0] = 2;
+`,
+			want: []diagnostic{
+				{line: 4, code: "TS2322"}, // 4 is not a size
+				{line: 6, code: "TS2540"}, // sizes is a readonly tuple
+			},
+		},
+		{
+			name: "template literal",
+			code: `const base = "api";
+const url = ` + "`/${base}/users`" + ` as const;
+const okUrl: "/api/users" = url;
+`,
+		},
+		{
+			name: "let",
+			code: `let greeting = "hi";
+const literal: "hi" = greeting;
+let pinned = "hi" as const;
+const pinnedLiteral: "hi" = pinned;
+`,
+			want: []diagnostic{
+				{line: 2, code: "TS2322"}, // let widens to string
+			},
+		},
+		{
+			name: "const type parameter",
+			code: `function tuple<const T extends readonly string[]>(items: T): T {
+  return items;
+}
+const pair: readonly ["a", "b"] = tuple(["a", "b"]);
+`,
+		},
+		{
+			name: "returned object literals",
+			code: `type Actions = typeof actions;
+const actions = {
+  add: (n: number) => ({ type: "add", n } as const),
+  reset: () => ({ type: "reset" } as const),
+};
+const action: keyof Actions = "remove";
+`,
+			want: []diagnostic{
+				{line: 6, code: "TS2322"}, // "remove" is not an action
+			},
+		},
+	})
+}
@@ -82,6 +82,11 @@ func (cfa *ControlFlowAnalyzer) analyzeStatement(
 		cfa.analyzeStatement(s.Body, info)
 		return false
 
+	case *ast.DoWhileStatement:
+		// The body runs once, but a break or continue may skip its returns
+		cfa.analyzeStatement(s.Body, info)
+		return false
+
 	case *ast.ForStatement:
 		// For loops don't guarantee execution
 		if s.Body != nil {
@@ -89,6 +94,20 @@ func (cfa *ControlFlowAnalyzer) analyzeStatement(
 		}
 		return false
 
+	case *ast.ForOfStatement:
+		// The iterable may be empty
+		if s.Body != nil {
+			cfa.analyzeStatement(s.Body, info)
+		}
+		return false
+
+	case *ast.ForInStatement:
+		// The object may have no keys
+		if s.Body != nil {
+			cfa.analyzeStatement(s.Body, info)
+		}
+		return false
+
 	case *ast.ThrowStatement:
 		// Throw is like a return - control flow ends
 		return true
@@ -0,0 +1,334 @@
+package checker
+
+import (
+	"strings"
+
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/types"
+)
+
+// checkControlFlow reports unreachable code, fallthrough cases in switch
+// statements and functions that do not return a value on every path, under
+// allowUnreachableCode, noFallthroughCasesInSwitch, noImplicitReturns and
+// strictNullChecks
+func (tc *TypeChecker) checkControlFlow(file *ast.File, filename string) {
+	if strings.HasSuffix(filename, ".d.ts") {
+		return
+	}
+	config := tc.GetConfig()
+	checkReturns := config.NoImplicitReturns || config.StrictNullChecks
+
+	// Unreachable code is an error when allowUnreachableCode is false and a
+	// warning when it is not set
+	unreachableSeverity := "warning"
+	if config.AllowUnreachableCode != nil {
+		unreachableSeverity = ""
+		if !*config.AllowUnreachableCode {
+			unreachableSeverity = "error"
+		}
+	}
+
+	// Statements after the first unreachable one are reported once, with it
+	unreachable := make(map[ast.Node]bool)
+	checkStatements := func(stmts []ast.Statement) {
+		if unreachableSeverity == "" {
+			return
+		}
+		for _, stmt := range tc.unreachableStatements(stmts) {
+			unreachable[stmt] = true
+		}
+		for _, stmt := range stmts {
+			if unreachable[stmt] {
+				pos := stmt.Pos()
+				tc.addError(filename, pos.Line, pos.Column, "Unreachable code detected.", "TS7027", unreachableSeverity)
+				break
+			}
+		}
+	}
+
+	checkStatements(file.Body)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil || unreachable[node] {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.BlockStatement:
+			checkStatements(n.Body)
+		case *ast.NamespaceDeclaration:
+			checkStatements(n.Body)
+		case *ast.SwitchCase:
+			checkStatements(n.Consequent)
+		case *ast.SwitchStatement:
+			if config.NoFallthroughCasesInSwitch {
+				tc.checkFallthrough(n, filename)
+			}
+		case *ast.FunctionDeclaration:
+			if checkReturns && n.Body != nil && !n.Generator {
+				tc.checkImplicitReturns(n, n.ID, n.ReturnType, n.Body, n.Async, filename)
+			}
+		case *ast.FunctionExpression:
+			if checkReturns && n.Body != nil && !n.Generator {
+				tc.checkImplicitReturns(n, n.ID, n.ReturnType, n.Body, n.Async, filename)
+			}
+		case *ast.ArrowFunctionExpression:
+			if body, ok := n.Body.(*ast.BlockStatement); ok && checkReturns {
+				tc.checkImplicitReturns(n, nil, n.ReturnType, body, n.Async, filename)
+			}
+		}
+		return true
+	})
+}
+
+// unreachableStatements returns the statements of a list that follow one that
+// always exits. Declarations that emit no code are never unreachable.
+func (tc *TypeChecker) unreachableStatements(stmts []ast.Statement) []ast.Statement {
+	var unreachable []ast.Statement
+	exited := false
+	for _, stmt := range stmts {
+		if exited {
+			if !isHoistedDeclaration(stmt) {
+				unreachable = append(unreachable, stmt)
+			}
+			continue
+		}
+		exited = tc.completesAbruptly(stmt, false)
+	}
+	return unreachable
+}
+
+// isHoistedDeclaration reports whether stmt declares something without
+// running code where it appears
+func isHoistedDeclaration(stmt ast.Statement) bool {
+	switch s := stmt.(type) {
+	case *ast.FunctionDeclaration, *ast.TypeAliasDeclaration, *ast.InterfaceDeclaration,
+		*ast.ModuleDeclaration, *ast.EmptyStatement:
+		return true
+	case *ast.VariableDeclaration:
+		if s.Kind != "var" {
+			return false
+		}
+		for _, decl := range s.Decls {
+			if decl.Init != nil {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// completesAbruptly reports whether the statement after stmt can never run,
+// because stmt always returns, throws, breaks, continues or loops forever.
+// With exhaustive, a switch without default that handles every member of the
+// union it discriminates counts as having one; unreachable code is reported
+// without it, as TypeScript does.
+func (tc *TypeChecker) completesAbruptly(stmt ast.Statement, exhaustive bool) bool {
+	switch s := stmt.(type) {
+	case *ast.BreakStatement, *ast.ContinueStatement:
+		return true
+	case *ast.BlockStatement:
+		return tc.listCompletesAbruptly(s.Body, exhaustive)
+	case *ast.IfStatement:
+		return s.Alternate != nil && tc.completesAbruptly(s.Consequent, exhaustive) && tc.completesAbruptly(s.Alternate, exhaustive)
+	case *ast.TryStatement:
+		if s.Finalizer != nil && tc.completesAbruptly(s.Finalizer, exhaustive) {
+			return true
+		}
+		if !tc.completesAbruptly(s.Block, exhaustive) {
+			return false
+		}
+		return s.Handler == nil || tc.completesAbruptly(s.Handler.Body, exhaustive)
+	case *ast.WhileStatement:
+		return isTrueLiteral(s.Test) && !breaksOut(s.Body)
+	case *ast.DoWhileStatement:
+		// The loop ends when its test fails, so only do...while (true) without
+		// a break never completes
+		return isTrueLiteral(s.Test) && !breaksOut(s.Body)
+	case *ast.ForStatement:
+		return (s.Test == nil || isTrueLiteral(s.Test)) && !breaksOut(s.Body)
+	case *ast.SwitchStatement:
+		return tc.switchCompletesAbruptly(s, exhaustive)
+	}
+	return tc.controlFlowNarrowing.statementAlwaysExits(stmt)
+}
+
+// switchCompletesAbruptly reports whether the end of a switch statement is
+// unreachable. Cases fall through, so only the last one must exit, and the
+// default case must exist with no break leaving the switch.
+func (tc *TypeChecker) switchCompletesAbruptly(stmt *ast.SwitchStatement, exhaustive bool) bool {
+	hasDefault := exhaustive && tc.typeNarrowing.exhaustiveSwitches[stmt]
+	for _, clause := range stmt.Cases {
+		if clause.Test == nil {
+			hasDefault = true
+		}
+	}
+	return hasDefault && tc.casesCompleteAbruptly(stmt, exhaustive)
+}
+
+// casesCompleteAbruptly reports whether no case of a switch statement reaches
+// its end: none breaks out and the last one exits
+func (tc *TypeChecker) casesCompleteAbruptly(stmt *ast.SwitchStatement, exhaustive bool) bool {
+	if len(stmt.Cases) == 0 {
+		return false
+	}
+	for _, clause := range stmt.Cases {
+		for _, caseStmt := range clause.Consequent {
+			if breaksOut(caseStmt) {
+				return false
+			}
+		}
+	}
+	return tc.listCompletesAbruptly(stmt.Cases[len(stmt.Cases)-1].Consequent, exhaustive)
+}
+
+// listCompletesAbruptly reports whether any statement of a list completes abruptly
+func (tc *TypeChecker) listCompletesAbruptly(stmts []ast.Statement, exhaustive bool) bool {
+	for _, stmt := range stmts {
+		if tc.completesAbruptly(stmt, exhaustive) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTrueLiteral reports whether expr is the literal true
+func isTrueLiteral(expr ast.Expression) bool {
+	lit, ok := expr.(*ast.Literal)
+	return ok && lit.Value == true
+}
+
+// breaksOut reports whether body contains a break that leaves the loop it
+// belongs to. Labeled breaks may leave any enclosing loop.
+func breaksOut(body ast.Statement) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		if found || node == nil {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.BreakStatement:
+			found = true
+		case *ast.WhileStatement, *ast.DoWhileStatement, *ast.ForStatement, *ast.ForOfStatement, *ast.ForInStatement, *ast.SwitchStatement:
+			// An unlabeled break inside belongs to the nested statement
+			return containsLabeledBreak(n)
+		case *ast.FunctionDeclaration, *ast.FunctionExpression, *ast.ArrowFunctionExpression, *ast.ClassDeclaration:
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// containsLabeledBreak reports whether node contains a break with a label
+func containsLabeledBreak(node ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if br, ok := n.(*ast.BreakStatement); ok && br.Label != nil {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkFallthrough reports the non-empty cases of a switch statement whose
+// end is reachable, so that they fall through to the next case
+func (tc *TypeChecker) checkFallthrough(stmt *ast.SwitchStatement, filename string) {
+	for i, clause := range stmt.Cases {
+		if i == len(stmt.Cases)-1 || len(clause.Consequent) == 0 {
+			continue
+		}
+		if !tc.listCompletesAbruptly(clause.Consequent, true) {
+			pos := clause.Pos()
+			tc.addError(filename, pos.Line, pos.Column, "Fallthrough case in switch.", "TS7029", "error")
+		}
+	}
+}
+
+// checkImplicitReturns reports functions that return a value on some paths
+// and reach the end of their body or return nothing on others. Under
+// strictNullChecks, reaching the end is an error of its own when the declared
+// return type does not include undefined.
+func (tc *TypeChecker) checkImplicitReturns(fn ast.Node, name *ast.Identifier, returnType ast.TypeNode, body *ast.BlockStatement, async bool, filename string) {
+	var returns []*ast.ReturnStatement
+	returnsValue := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FunctionDeclaration, *ast.FunctionExpression, *ast.ArrowFunctionExpression, *ast.ClassDeclaration:
+			return false
+		case *ast.ReturnStatement:
+			returns = append(returns, n)
+			if n.Argument != nil && !isUndefinedExpression(n.Argument) {
+				returnsValue = true
+			}
+		}
+		return node != nil
+	})
+	if !returnsValue {
+		return
+	}
+	config := tc.GetConfig()
+	lacksUndefined := false
+	if returnType != nil {
+		declared := tc.convertTypeNode(returnType)
+		if async && declared != nil && declared.Kind == types.ObjectType && declared.Name == "Promise" && len(declared.TypeParameters) > 0 {
+			declared = declared.TypeParameters[0]
+		}
+		if declared == nil || isVoidOrAny(declared) {
+			return
+		}
+		lacksUndefined = config.StrictNullChecks && !includesUndefined(declared)
+	}
+
+	const message = "Not all code paths return a value."
+	if config.NoImplicitReturns {
+		for _, ret := range returns {
+			if ret.Argument == nil {
+				tc.addError(filename, ret.Pos().Line, ret.Pos().Column, message, "TS7030", "error")
+			}
+		}
+	}
+	if tc.listCompletesAbruptly(body.Body, true) {
+		return
+	}
+
+	pos := fn.Pos()
+	if returnType != nil {
+		pos = returnType.Pos()
+	} else if name != nil {
+		pos = name.Pos()
+	}
+	if lacksUndefined {
+		tc.addError(filename, pos.Line, pos.Column,
+			"Function lacks ending return statement and return type does not include 'undefined'.", "TS2366", "error")
+	} else if config.NoImplicitReturns {
+		tc.addError(filename, pos.Line, pos.Column, message, "TS7030", "error")
+	}
+}
+
+// isUndefinedExpression reports whether expr is undefined or a void expression
+func isUndefinedExpression(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		return e.Name == "undefined"
+	case *ast.UnaryExpression:
+		return e.Operator == "void"
+	}
+	return false
+}
+
+// isVoidOrAny reports whether a function returning t may omit its return
+// value: t is void, any or undefined, or a union with void
+func isVoidOrAny(t *types.Type) bool {
+	switch t.Kind {
+	case types.VoidType, types.AnyType, types.UndefinedType:
+		return true
+	case types.UnionType:
+		for _, member := range t.Types {
+			if member.Kind == types.VoidType || member.Kind == types.AnyType {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package checker
+
+import "testing"
+
+func TestCheckControlFlow(t *testing.T) {
+	strict := getDefaultConfig()
+	strict.NoImplicitReturns = true
+	strict.NoFallthroughCasesInSwitch = true
+	disallowUnreachable, allowUnreachable := getDefaultConfig(), getDefaultConfig()
+	no, yes := false, true
+	disallowUnreachable.AllowUnreachableCode = &no
+	allowUnreachable.AllowUnreachableCode = &yes
+
+	const unreachable = `export function early() {
+  return 1;
+  x();
+  function x() { return true; }
+}
+`
+	runDiagnosticCases(t, strict, []diagnosticCase{
+		{
+			name: "implicit returns",
+			code: `export function positive(x: number) {
+  if (x > 0) {
+    return 1;
+  }
+}
+export function label(x: number): string | undefined {
+  if (x) return "a";
+  return;
+}
+export function log(x: number): void {
+  if (x) return;
+}
+export function poll(): number {
+  while (true) {
+    if (x()) return 1;
+  }
+}
+export const arrow = (x: number) => { if (x) { return x; } };
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS7030"},
+				{line: 8, code: "TS7030"},
+				{line: 18, code: "TS7030"},
+			},
+		},
+		{
+			name: "fallthrough cases",
+			code: `export function kind(x: number): number {
+  switch (x) {
+    case 1:
+      x++;
+    case 2:
+    case 3:
+      return 2;
+    default:
+      return 3;
+  }
+}
+export function tally(x: number) {
+  switch (x) {
+    case 1:
+      x++;
+      break;
+    default:
+      x--;
+  }
+  return x;
+}
+`,
+			want: []diagnostic{
+				{line: 3, code: "TS7029"},
+			},
+		},
+		{
+			name: "unreachable code is a warning by default",
+			code: unreachable,
+			want: []diagnostic{
+				{line: 3, code: "TS7027", severity: "warning"},
+			},
+		},
+		{
+			name:   "allowUnreachableCode false",
+			code:   unreachable,
+			config: disallowUnreachable,
+			want: []diagnostic{
+				{line: 3, code: "TS7027", severity: "error"},
+			},
+		},
+		{
+			name:   "allowUnreachableCode true",
+			code:   unreachable,
+			config: allowUnreachable,
+		},
+		{
+			name: "do...while loops complete unless the test is true",
+			code: `export function skip(items: number[]): number {
+  let i = 0;
+  do {
+    if (items[i] < 0) continue;
+    if (items[i] > 10) break;
+    i++;
+  } while (i < items.length);
+  do {
+    if (i > 3) break;
+    i++;
+  } while (true);
+  return i;
+}
+export function forever(): number {
+  do {
+    work();
+  } while (true);
+  return 1;
+}
+function work() {}
+`,
+			want: []diagnostic{
+				{line: 18, code: "TS7027", severity: "warning"},
+			},
+		},
+	}, "TS7027", "TS7029", "TS7030")
+}
@@ -120,6 +120,14 @@ func (cfn *ControlFlowNarrowing) AnalyzeIfStatementWithControlFlow(
 	// Analyze the condition for type narrowing
 	thenNarrowing, elseNarrowing := cfn.tc.typeNarrowing.AnalyzeCondition(condition)
 
+	// Variables assigned in a branch get the union of their types at the end
+	// of the branches that complete
+	branches := []ast.Node{stmt.Consequent}
+	if stmt.Alternate != nil {
+		branches = append(branches, stmt.Alternate)
+	}
+	join := cfn.tc.typeNarrowing.beginBranches(branches...)
+
 	// Check if the then branch always exits (returns/throws)
 	thenAlwaysExits := false
 	if blockStmt, ok := stmt.Consequent.(*ast.BlockStatement); ok {
@@ -131,6 +139,7 @@ func (cfn *ControlFlowNarrowing) AnalyzeIfStatementWithControlFlow(
 		restore()
 		thenAlwaysExits = cfn.statementAlwaysExits(stmt.Consequent)
 	}
+	join.endBranch(thenAlwaysExits)
 
 	// Check the else branch if present
 	elseAlwaysExits := false
@@ -145,6 +154,8 @@ func (cfn *ControlFlowNarrowing) AnalyzeIfStatementWithControlFlow(
 			elseAlwaysExits = cfn.statementAlwaysExits(stmt.Alternate)
 		}
 	}
+	join.endBranch(elseAlwaysExits)
+	join.finish()
 
 	// CRITICAL: If the then branch always exits (returns/throws),
 	// then code AFTER the if statement has the ELSE narrowing applied!
@@ -156,4 +167,14 @@ func (cfn *ControlFlowNarrowing) AnalyzeIfStatementWithControlFlow(
 			cfn.tc.varTypeCache[varName] = narrowedType
 		}
 	}
+
+	// Likewise, when only the else branch exits, the then narrowing holds
+	// after the if statement for the variables the then branch leaves alone
+	if elseAlwaysExits && !thenAlwaysExits {
+		for varName, narrowedType := range thenNarrowing {
+			if !join.assigns(varName) {
+				cfn.tc.varTypeCache[varName] = narrowedType
+			}
+		}
+	}
 }
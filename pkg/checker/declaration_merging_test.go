@@ -0,0 +1,109 @@
+package checker
+
+import "testing"
+
+func TestDeclarationMerging(t *testing.T) {
+	runDiagnosticCases(t, nil, []diagnosticCase{
+		{
+			name: "interfaces",
+			code: `interface Box { width: number }
+interface Box { height: number }
+const box: Box = { width: 1, height: 2 };
+const badBox: Box = { width: 1 };
+const area: string = box.width * box.height;
+`,
+			want: []diagnostic{
+				{line: 4, code: "TS2322"}, // height is missing
+				{line: 5, code: "TS2322"}, // both properties are numbers
+			},
+		},
+		{
+			name: "function and namespace",
+			code: `function greet(name: string): string { return "hi " + name; }
+namespace greet {
+  export const prefix = "hi";
+}
+const prefix: number = greet.prefix;
+const greeting: number = greet("x");
+`,
+			want: []diagnostic{
+				{line: 5, code: "TS2322"}, // the namespace member is a string
+				{line: 6, code: "TS2322"}, // greet is still callable
+			},
+		},
+		{
+			name: "enums",
+			code: `enum Color { Red, Green }
+enum Color { Blue = 2 }
+const blue: Color = Color.Blue;
+const red: Color = Color.Red;
+Color.Purple;
+`,
+			want: []diagnostic{
+				{line: 5, code: "TS2339"}, // neither enum block declares Purple
+			},
+		},
+		{
+			name: "class and namespace",
+			code: `class Album { label = "x"; }
+namespace Album {
+  export const count = 1;
+}
+const count: string = Album.count;
+const label: string = new Album().label;
+export {};
+`,
+			want: []diagnostic{
+				{line: 5, code: "TS2322"}, // the namespace member is a number
+			},
+		},
+		{
+			name: "namespace interfaces",
+			code: `namespace NS { export interface P { A: string } }
+namespace NS { export interface P { B: number } }
+declare const e: NS.P;
+const x: number = e.A;
+const y: number = e.B;
+`,
+			want: []diagnostic{
+				{line: 4, code: "TS2322"}, // A comes from the first block
+			},
+		},
+		{
+			name: "global namespace interfaces",
+			code: `declare global { namespace Express { interface Request { user: string } } }
+declare const req: Express.Request;
+const user: number = req.user;
+export {};
+`,
+			want: []diagnostic{
+				{line: 3, code: "TS2322"}, // user is a string
+			},
+		},
+	})
+}
+
+func TestGlobalDeclarationsAcrossFiles(t *testing.T) {
+	files := map[string]string{
+		"globals.ts": `declare global {
+  interface Settings { theme: string }
+}
+export {};
+`,
+		"layout.ts": `declare
+global {
+  interface Settings { columns: number }
+}
+export {};
+`,
+	}
+	code := `interface Settings { size: number }
+const settings: Settings = { theme: "dark", columns: 2, size: 1 };
+const theme: number = settings.theme;
+const columns: string = settings.columns;
+`
+	expectDiagnostics(t, checkProjectDiagnostics(t, files, code), []diagnostic{
+		{line: 3, code: "TS2322"}, // the global declaration merges into Settings
+		{line: 4, code: "TS2322"}, // so does the one split across lines
+	})
+}
@@ -0,0 +1,834 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/types"
+)
+
+// checkDefiniteAssignment reports variables read before they are assigned on
+// some path (TS2454, under strictNullChecks) and block-scoped variables,
+// classes and enums used before their declaration (TS2448, TS2449, TS2450).
+// Each function body, the file and every namespace get their own flow graph.
+func (tc *TypeChecker) checkDefiniteAssignment(file *ast.File, filename string) {
+	if strings.HasSuffix(filename, ".d.ts") {
+		return
+	}
+	b := &flowBuilder{
+		tc:       tc,
+		filename: filename,
+		strict:   tc.GetConfig().StrictNullChecks,
+	}
+	b.container(file, func() {
+		b.hoistVars(file.Body)
+		b.statements(file.Body)
+	})
+}
+
+// flowGraph is the control flow graph of one function body, namespace or file.
+// Nodes only record what definite assignment needs: the start of the body,
+// joins of several paths and assignments to the variables being tracked.
+type flowGraph struct {
+	container ast.Node
+	nodes     []*flowNode
+	current   *flowNode
+	variables int
+	reads     []flowRead
+	breaks    []*flowNode // targets of break, innermost last
+	continues []*flowNode // targets of continue, innermost last
+}
+
+type flowAction int
+
+const (
+	flowJoin flowAction = iota
+	flowAssign
+	flowDeclare // a let without initializer, fresh on every iteration
+)
+
+type flowNode struct {
+	preds    []*flowNode
+	action   flowAction
+	variable int
+	assigned flowState // variables definitely assigned after the node
+}
+
+// flowRead is a read of a tracked variable at a point of the graph
+type flowRead struct {
+	node     *flowNode
+	variable int
+	id       *ast.Identifier
+}
+
+// flowState is a set of variables as a bit set
+type flowState []uint64
+
+func (s flowState) has(i int) bool { return s[i/64]&(1<<(uint(i)%64)) != 0 }
+
+func newFlowGraph(container ast.Node) *flowGraph {
+	g := &flowGraph{container: container}
+	g.current = g.node(flowJoin, -1)
+	return g
+}
+
+// node adds a node reached from preds
+func (g *flowGraph) node(action flowAction, variable int, preds ...*flowNode) *flowNode {
+	n := &flowNode{preds: preds, action: action, variable: variable}
+	g.nodes = append(g.nodes, n)
+	return n
+}
+
+// label adds a join point whose predecessors are added later
+func (g *flowGraph) label() *flowNode {
+	return g.node(flowJoin, -1)
+}
+
+// edge adds the current point as a predecessor of label
+func (g *flowGraph) edge(label *flowNode) {
+	label.preds = append(label.preds, g.current)
+}
+
+// unreachable continues the graph after return, throw, break or continue
+func (g *flowGraph) unreachable() {
+	g.current = g.label()
+}
+
+// join continues the graph at label, reached from the current point and from
+// the given points
+func (g *flowGraph) join(others ...*flowNode) {
+	label := g.label()
+	label.preds = append(append(label.preds, g.current), others...)
+	g.current = label
+}
+
+func (g *flowGraph) assign(variable int) {
+	g.current = g.node(flowAssign, variable, g.current)
+}
+
+// solve computes the variables definitely assigned after every node. Nodes
+// start with every variable assigned, so that unreachable code reports
+// nothing, and shrink until no node changes.
+func (g *flowGraph) solve() {
+	words := (g.variables + 63) / 64
+	all := make(flowState, words)
+	for i := range all {
+		all[i] = ^uint64(0)
+	}
+	for i, n := range g.nodes {
+		n.assigned = make(flowState, words)
+		if i > 0 {
+			copy(n.assigned, all)
+		}
+	}
+
+	state := make(flowState, words)
+	for changed := true; changed; {
+		changed = false
+		for _, n := range g.nodes[1:] {
+			copy(state, all)
+			for _, pred := range n.preds {
+				for i := range state {
+					state[i] &= pred.assigned[i]
+				}
+			}
+			switch n.action {
+			case flowAssign:
+				state[n.variable/64] |= 1 << (uint(n.variable) % 64)
+			case flowDeclare:
+				state[n.variable/64] &^= 1 << (uint(n.variable) % 64)
+			}
+			for i := range state {
+				if state[i] != n.assigned[i] {
+					copy(n.assigned, state)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// flowBinding is a name declared in a lexical scope
+type flowBinding struct {
+	kind      string // let, const, var, class, enum, function or param
+	end       int    // offset before which a use comes before the declaration
+	container ast.Node
+	variable  int // index of a variable tracked for definite assignment, or -1
+}
+
+type flowBuilder struct {
+	tc       *TypeChecker
+	filename string
+	strict   bool
+	graph    *flowGraph
+	scopes   []map[string]*flowBinding
+}
+
+// container walks a function body, namespace or file in a graph of its own
+// and reports its variables read before being assigned
+func (b *flowBuilder) container(node ast.Node, walk func()) {
+	outer := b.graph
+	b.graph = newFlowGraph(node)
+	b.pushScope()
+	walk()
+	b.popScope()
+
+	b.graph.solve()
+	for _, read := range b.graph.reads {
+		if !read.node.assigned.has(read.variable) {
+			pos := read.id.Pos()
+			b.tc.addError(b.filename, pos.Line, pos.Column,
+				fmt.Sprintf("Variable '%s' is used before being assigned.", read.id.Name), "TS2454", "error")
+		}
+	}
+	b.graph = outer
+}
+
+func (b *flowBuilder) pushScope() {
+	b.scopes = append(b.scopes, make(map[string]*flowBinding))
+}
+
+func (b *flowBuilder) popScope() {
+	b.scopes = b.scopes[:len(b.scopes)-1]
+}
+
+// declare binds name in the innermost scope
+func (b *flowBuilder) declare(name, kind string, end int) *flowBinding {
+	binding := &flowBinding{kind: kind, end: end, container: b.graph.container, variable: -1}
+	b.scopes[len(b.scopes)-1][name] = binding
+	return binding
+}
+
+func (b *flowBuilder) lookup(name string) *flowBinding {
+	for i := len(b.scopes) - 1; i >= 0; i-- {
+		if binding, ok := b.scopes[i][name]; ok {
+			return binding
+		}
+	}
+	return nil
+}
+
+// track starts tracking the definite assignment of binding
+func (b *flowBuilder) track(binding *flowBinding) {
+	binding.variable = b.graph.variables
+	b.graph.variables++
+}
+
+// isTracked reports whether a declarator without initializer must be assigned
+// before it is read: its declared type does not include undefined
+func (b *flowBuilder) isTracked(decl *ast.VariableDeclaration, declarator *ast.VariableDeclarator) bool {
+	if !b.strict || decl.Declare || decl.Kind == "const" || declarator.Init != nil || declarator.Definite || declarator.TypeAnnotation == nil {
+		return false
+	}
+	t := b.tc.convertTypeNode(declarator.TypeAnnotation)
+	return t != nil && !includesUndefined(t)
+}
+
+// includesUndefined reports whether undefined is assignable to t
+func includesUndefined(t *types.Type) bool {
+	switch t.Kind {
+	case types.AnyType, types.UnknownType, types.VoidType, types.UndefinedType:
+		return true
+	case types.UnionType:
+		for _, member := range t.Types {
+			if includesUndefined(member) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hoistVars declares the var declarations of a body, which belong to the
+// whole function
+func (b *flowBuilder) hoistVars(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.FunctionDeclaration, *ast.FunctionExpression, *ast.ArrowFunctionExpression,
+				*ast.ClassDeclaration, *ast.ClassExpression, *ast.NamespaceDeclaration:
+				return false
+			case *ast.VariableDeclaration:
+				if n.Kind == "var" {
+					for _, declarator := range n.Decls {
+						if b.lookupLocal(declarator.ID.Name) != nil {
+							continue
+						}
+						binding := b.declare(declarator.ID.Name, "var", 0)
+						if b.isTracked(n, declarator) {
+							b.track(binding)
+						}
+					}
+				}
+			}
+			return node != nil
+		})
+	}
+}
+
+func (b *flowBuilder) lookupLocal(name string) *flowBinding {
+	return b.scopes[len(b.scopes)-1][name]
+}
+
+// hoistBlock declares the block-scoped declarations of a statement list,
+// which are in scope, but not initialized, from the start of the block
+func (b *flowBuilder) hoistBlock(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		if export, ok := stmt.(*ast.ExportDeclaration); ok && export.Declaration != nil {
+			stmt = export.Declaration
+		}
+		switch s := stmt.(type) {
+		case *ast.VariableDeclaration:
+			if s.Kind == "var" {
+				continue
+			}
+			for _, declarator := range s.Decls {
+				binding := b.declare(declarator.ID.Name, s.Kind, declarator.End().Offset)
+				if b.isTracked(s, declarator) {
+					b.track(binding)
+				}
+			}
+		case *ast.ClassDeclaration:
+			if s.ID != nil {
+				b.declare(s.ID.Name, "class", s.Pos().Offset)
+			}
+		case *ast.EnumDeclaration:
+			if s.Name != nil {
+				b.declare(s.Name.Name, "enum", s.Pos().Offset)
+			}
+		case *ast.FunctionDeclaration:
+			if s.ID != nil {
+				b.declare(s.ID.Name, "function", 0)
+			}
+		case *ast.NamespaceDeclaration:
+			if s.Name != nil {
+				b.declare(s.Name.Name, "namespace", 0)
+			}
+		}
+	}
+}
+
+// use checks a reference to a binding of the current container against its
+// declaration. Uses inside nested functions run later and are not checked.
+func (b *flowBuilder) use(id *ast.Identifier, binding *flowBinding) {
+	if binding.container != b.graph.container || id.Pos().Offset >= binding.end {
+		return
+	}
+	pos := id.Pos()
+	switch binding.kind {
+	case "let", "const":
+		b.tc.addError(b.filename, pos.Line, pos.Column,
+			fmt.Sprintf("Block-scoped variable '%s' used before its declaration.", id.Name), "TS2448", "error")
+	case "class":
+		b.tc.addError(b.filename, pos.Line, pos.Column,
+			fmt.Sprintf("Class '%s' used before its declaration.", id.Name), "TS2449", "error")
+	case "enum":
+		b.tc.addError(b.filename, pos.Line, pos.Column,
+			fmt.Sprintf("Enum '%s' used before its declaration.", id.Name), "TS2450", "error")
+	}
+}
+
+func (b *flowBuilder) read(id *ast.Identifier) {
+	binding := b.lookup(id.Name)
+	if binding == nil {
+		return
+	}
+	b.use(id, binding)
+	if binding.variable >= 0 && binding.container == b.graph.container {
+		b.graph.reads = append(b.graph.reads, flowRead{node: b.graph.current, variable: binding.variable, id: id})
+	}
+}
+
+func (b *flowBuilder) write(id *ast.Identifier) {
+	binding := b.lookup(id.Name)
+	if binding == nil {
+		return
+	}
+	b.use(id, binding)
+	if binding.variable >= 0 && binding.container == b.graph.container {
+		b.graph.assign(binding.variable)
+	}
+}
+
+func (b *flowBuilder) statements(stmts []ast.Statement) {
+	b.hoistBlock(stmts)
+	for _, stmt := range stmts {
+		b.statement(stmt)
+	}
+}
+
+func (b *flowBuilder) statement(stmt ast.Statement) {
+	g := b.graph
+	switch s := stmt.(type) {
+	case *ast.ExpressionStatement:
+		b.expression(s.Expression)
+	case *ast.VariableDeclaration:
+		b.variableDeclaration(s)
+	case *ast.BlockStatement:
+		b.pushScope()
+		b.statements(s.Body)
+		b.popScope()
+	case *ast.IfStatement:
+		b.expression(s.Test)
+		afterTest := g.current
+		b.statement(s.Consequent)
+		endThen := g.current
+		g.current = afterTest
+		if s.Alternate != nil {
+			b.statement(s.Alternate)
+		}
+		g.join(endThen)
+	case *ast.WhileStatement:
+		loop, exit := g.label(), g.label()
+		g.edge(loop)
+		g.current = loop
+		b.expression(s.Test)
+		if !isTrueLiteral(s.Test) {
+			g.edge(exit)
+		}
+		b.loopBody(s.Body, exit, loop)
+		g.edge(loop)
+		g.current = exit
+	case *ast.DoWhileStatement:
+		// The body runs before the first test, and continue jumps to the test
+		loop, next, exit := g.label(), g.label(), g.label()
+		g.edge(loop)
+		g.current = loop
+		b.loopBody(s.Body, exit, next)
+		g.edge(next)
+		g.current = next
+		b.expression(s.Test)
+		if !isTrueLiteral(s.Test) {
+			g.edge(exit)
+		}
+		g.edge(loop)
+		g.current = exit
+	case *ast.ForStatement:
+		b.pushScope()
+		switch init := s.Init.(type) {
+		case *ast.VariableDeclaration:
+			b.hoistBlock([]ast.Statement{init})
+			b.statement(init)
+		case ast.Statement:
+			b.statement(init)
+		case ast.Expression:
+			b.expression(init)
+		}
+		loop, next, exit := g.label(), g.label(), g.label()
+		g.edge(loop)
+		g.current = loop
+		if s.Test != nil {
+			b.expression(s.Test)
+			if !isTrueLiteral(s.Test) {
+				g.edge(exit)
+			}
+		}
+		b.loopBody(s.Body, exit, next)
+		g.edge(next)
+		g.current = next
+		b.expression(s.Update)
+		g.edge(loop)
+		g.current = exit
+		b.popScope()
+	case *ast.ForOfStatement:
+		b.forEach(s.Left, s.Right, s.Body)
+	case *ast.ForInStatement:
+		b.forEach(s.Left, s.Right, s.Body)
+	case *ast.SwitchStatement:
+		b.switchStatement(s)
+	case *ast.TryStatement:
+		beforeTry := g.current
+		b.statement(s.Block)
+		endTry := g.current
+		if s.Handler != nil {
+			// Any statement of the try block may throw before its assignments
+			g.current = g.node(flowJoin, -1, beforeTry)
+			b.pushScope()
+			if s.Handler.Param != nil {
+				b.declare(s.Handler.Param.Name, "param", 0)
+			}
+			b.statement(s.Handler.Body)
+			b.popScope()
+			g.join(endTry)
+		}
+		if s.Finalizer != nil {
+			b.statement(s.Finalizer)
+		}
+	case *ast.ReturnStatement:
+		b.expression(s.Argument)
+		g.unreachable()
+	case *ast.ThrowStatement:
+		b.expression(s.Argument)
+		g.unreachable()
+	case *ast.BreakStatement:
+		if s.Label == nil && len(g.breaks) > 0 {
+			g.edge(g.breaks[len(g.breaks)-1])
+		}
+		g.unreachable()
+	case *ast.ContinueStatement:
+		if s.Label == nil && len(g.continues) > 0 {
+			g.edge(g.continues[len(g.continues)-1])
+		}
+		g.unreachable()
+	case *ast.FunctionDeclaration:
+		b.function(s, nil, s.Params, s.Body)
+	case *ast.ClassDeclaration:
+		b.class(s)
+	case *ast.EnumDeclaration:
+		// Members refer to the members before them by name
+		b.pushScope()
+		for _, member := range s.Members {
+			b.expression(member.Value)
+			if member.Name != nil {
+				b.declare(member.Name.Name, "member", 0)
+			}
+		}
+		b.popScope()
+	case *ast.NamespaceDeclaration:
+		b.container(s, func() {
+			b.hoistVars(s.Body)
+			b.statements(s.Body)
+		})
+	case *ast.ExportDeclaration:
+		if s.Declaration != nil {
+			b.statement(s.Declaration)
+		}
+	}
+}
+
+func (b *flowBuilder) variableDeclaration(decl *ast.VariableDeclaration) {
+	// Destructured declarators share the initializer of their pattern
+	var previous ast.Expression
+	for _, declarator := range decl.Decls {
+		if declarator.Init != nil && declarator.Init != previous {
+			b.expression(declarator.Init)
+			previous = declarator.Init
+		}
+		if declarator.Init != nil {
+			b.initialize(declarator.ID)
+			continue
+		}
+		binding := b.lookup(declarator.ID.Name)
+		if decl.Kind == "let" && binding != nil && binding.variable >= 0 && binding.container == b.graph.container {
+			b.graph.current = b.graph.node(flowDeclare, binding.variable, b.graph.current)
+		}
+	}
+}
+
+// initialize assigns the variable a declaration binds
+func (b *flowBuilder) initialize(id *ast.Identifier) {
+	binding := b.lookup(id.Name)
+	if binding != nil && binding.variable >= 0 && binding.container == b.graph.container {
+		b.graph.assign(binding.variable)
+	}
+}
+
+// loopBody walks the body of a loop, where break jumps to exit and continue
+// to next
+func (b *flowBuilder) loopBody(body ast.Statement, exit, next *flowNode) {
+	g := b.graph
+	g.breaks = append(g.breaks, exit)
+	g.continues = append(g.continues, next)
+	b.statement(body)
+	g.breaks = g.breaks[:len(g.breaks)-1]
+	g.continues = g.continues[:len(g.continues)-1]
+}
+
+// forEach walks a for...of or for...in loop, which may run its body no times
+func (b *flowBuilder) forEach(left ast.Node, right ast.Expression, body ast.Statement) {
+	g := b.graph
+	b.pushScope()
+	b.expression(right)
+	loop, exit := g.label(), g.label()
+	g.edge(loop)
+	g.current = loop
+	g.edge(exit)
+	switch l := left.(type) {
+	case *ast.VariableDeclaration:
+		b.hoistBlock([]ast.Statement{l})
+		for _, declarator := range l.Decls {
+			b.initialize(declarator.ID)
+		}
+	case *ast.ExpressionStatement:
+		b.assignTarget(l.Expression)
+	}
+	b.loopBody(body, exit, loop)
+	g.edge(loop)
+	g.current = exit
+	b.popScope()
+}
+
+// switchStatement walks the cases of a switch, which share one block scope.
+// A case is entered when its test matches or from the case before it, and
+// the default case when no test matches.
+func (b *flowBuilder) switchStatement(s *ast.SwitchStatement) {
+	g := b.graph
+	b.expression(s.Discriminant)
+	b.pushScope()
+	for _, clause := range s.Cases {
+		b.hoistBlock(clause.Consequent)
+	}
+
+	exit := g.label()
+	g.breaks = append(g.breaks, exit)
+	tests := g.current
+	var fallthroughEnd, defaultCase *flowNode
+	for _, clause := range s.Cases {
+		entry := g.label()
+		if clause.Test != nil {
+			g.current = tests
+			b.expression(clause.Test)
+			tests = g.current
+			g.edge(entry)
+		} else {
+			defaultCase = entry
+		}
+		if fallthroughEnd != nil {
+			entry.preds = append(entry.preds, fallthroughEnd)
+		}
+		g.current = entry
+		for _, stmt := range clause.Consequent {
+			b.statement(stmt)
+		}
+		fallthroughEnd = g.current
+	}
+	g.breaks = g.breaks[:len(g.breaks)-1]
+
+	// A switch that handles every member of the union it discriminates
+	// cannot leave without entering a case
+	if defaultCase != nil {
+		defaultCase.preds = append(defaultCase.preds, tests)
+	} else if !b.tc.typeNarrowing.exhaustiveSwitches[s] {
+		exit.preds = append(exit.preds, tests)
+	}
+	if fallthroughEnd != nil {
+		exit.preds = append(exit.preds, fallthroughEnd)
+	}
+	g.current = exit
+	b.popScope()
+}
+
+// function walks a function in a graph of its own. Its parameters are always
+// assigned.
+func (b *flowBuilder) function(node ast.Node, name *ast.Identifier, params []*ast.Parameter, body ast.Node) {
+	if isNilBody(body) {
+		return
+	}
+	b.container(node, func() {
+		if name != nil {
+			b.declare(name.Name, "function", 0)
+		}
+		for _, param := range params {
+			if param.ID != nil {
+				b.declare(param.ID.Name, "param", 0)
+			}
+		}
+		for _, param := range params {
+			b.expression(param.Default)
+		}
+		switch body := body.(type) {
+		case *ast.BlockStatement:
+			b.hoistVars(body.Body)
+			b.statements(body.Body)
+		case ast.Expression:
+			b.expression(body)
+		}
+	})
+}
+
+func isNilBody(body ast.Node) bool {
+	block, ok := body.(*ast.BlockStatement)
+	return body == nil || (ok && block == nil)
+}
+
+// class walks the heritage of a class and its members. Methods and property
+// initializers run later, in graphs of their own.
+func (b *flowBuilder) class(decl *ast.ClassDeclaration) {
+	if decl == nil {
+		return
+	}
+	if decl.SuperClass != nil {
+		b.read(decl.SuperClass)
+	}
+	for _, member := range decl.Body {
+		switch m := member.(type) {
+		case *ast.MethodDefinition:
+			if m.Value != nil {
+				b.function(m.Value, nil, m.Value.Params, m.Value.Body)
+			}
+		case *ast.PropertyDefinition:
+			if m.Value != nil {
+				b.container(m, func() { b.expression(m.Value) })
+			}
+		}
+	}
+}
+
+func (b *flowBuilder) expression(expr ast.Expression) {
+	g := b.graph
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		if e != nil {
+			b.read(e)
+		}
+	case *ast.MemberExpression:
+		b.expression(e.Object)
+		if e.Computed {
+			b.expression(e.Property)
+		}
+	case *ast.CallExpression:
+		b.expression(e.Callee)
+		b.expressions(e.Arguments)
+	case *ast.NewExpression:
+		b.expression(e.Callee)
+		b.expressions(e.Arguments)
+	case *ast.AsExpression:
+		b.expression(e.Expression)
+	case *ast.SatisfiesExpression:
+		b.expression(e.Expression)
+	case *ast.ConditionalExpression:
+		b.expression(e.Test)
+		afterTest := g.current
+		b.expression(e.Consequent)
+		endThen := g.current
+		g.current = afterTest
+		b.expression(e.Alternate)
+		g.join(endThen)
+	case *ast.BinaryExpression:
+		b.expression(e.Left)
+		switch e.Operator {
+		case "&&", "||", "??":
+			// The right operand may not run
+			afterLeft := g.current
+			b.expression(e.Right)
+			g.join(afterLeft)
+		default:
+			b.expression(e.Right)
+		}
+	case *ast.AssignmentExpression:
+		b.assignment(e)
+	case *ast.UnaryExpression:
+		if id, ok := e.Argument.(*ast.Identifier); ok && (e.Operator == "++" || e.Operator == "--") {
+			b.read(id)
+			b.write(id)
+			return
+		}
+		b.expression(e.Argument)
+	case *ast.ArrayExpression:
+		b.expressions(e.Elements)
+	case *ast.ObjectExpression:
+		for _, prop := range e.Properties {
+			switch p := prop.(type) {
+			case *ast.Property:
+				b.expression(p.Value)
+			case *ast.SpreadElement:
+				b.expression(p.Argument)
+			}
+		}
+	case *ast.SpreadElement:
+		b.expression(e.Argument)
+	case *ast.ArrowFunctionExpression:
+		b.function(e, nil, e.Params, e.Body)
+	case *ast.FunctionExpression:
+		b.function(e, e.ID, e.Params, e.Body)
+	case *ast.ClassExpression:
+		b.class(e.Class)
+	case *ast.YieldExpression:
+		b.expression(e.Argument)
+	case *ast.TaggedTemplateExpression:
+		b.expression(e.Tag)
+		b.expression(e.Quasi)
+	case *ast.TemplateLiteral:
+		b.expressions(e.Expressions)
+	case *ast.JSXElement:
+		switch name := e.Name.(type) {
+		case *ast.Identifier:
+			// Lowercase tags are intrinsic elements
+			if name.Name != "" && strings.ToUpper(name.Name[:1]) == name.Name[:1] {
+				b.read(name)
+			}
+		case *ast.MemberExpression:
+			b.expression(name)
+		}
+		for _, attr := range e.Attributes {
+			switch a := attr.(type) {
+			case *ast.JSXAttribute:
+				b.expression(a.Value)
+			case *ast.JSXSpreadAttribute:
+				b.expression(a.Argument)
+			}
+		}
+		b.expressions(e.Children)
+	case *ast.JSXFragment:
+		b.expressions(e.Children)
+	case *ast.JSXExpressionContainer:
+		b.expression(e.Expression)
+	}
+}
+
+func (b *flowBuilder) expressions(exprs []ast.Expression) {
+	for _, expr := range exprs {
+		b.expression(expr)
+	}
+}
+
+func (b *flowBuilder) assignment(e *ast.AssignmentExpression) {
+	g := b.graph
+	switch e.Operator {
+	case "=":
+		if member, ok := e.Left.(*ast.MemberExpression); ok {
+			b.expression(member)
+			b.expression(e.Right)
+			return
+		}
+		b.expression(e.Right)
+		b.assignTarget(e.Left)
+	case "&&=", "||=", "??=":
+		b.expression(e.Left)
+		afterLeft := g.current
+		b.expression(e.Right)
+		b.assignTarget(e.Left)
+		g.join(afterLeft)
+	default:
+		b.expression(e.Left)
+		b.expression(e.Right)
+		b.assignTarget(e.Left)
+	}
+}
+
+// assignTarget assigns the identifiers of an assignment target, which may be
+// an array or object destructuring pattern
+func (b *flowBuilder) assignTarget(target ast.Expression) {
+	switch t := target.(type) {
+	case *ast.Identifier:
+		if t != nil {
+			b.write(t)
+		}
+	case *ast.ArrayExpression:
+		for _, element := range t.Elements {
+			b.assignTarget(element)
+		}
+	case *ast.ObjectExpression:
+		for _, prop := range t.Properties {
+			switch p := prop.(type) {
+			case *ast.Property:
+				b.assignTarget(p.Value)
+			case *ast.SpreadElement:
+				b.assignTarget(p.Argument)
+			}
+		}
+	case *ast.SpreadElement:
+		b.assignTarget(t.Argument)
+	case *ast.AssignmentExpression:
+		// A default value
+		b.expression(t.Right)
+		b.assignTarget(t.Left)
+	case *ast.MemberExpression:
+		b.expression(t.Object)
+		if t.Computed {
+			b.expression(t.Property)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package checker
+
+import "testing"
+
+func TestCheckDefiniteAssignment(t *testing.T) {
+	strict := getDefaultConfig()
+	strict.StrictNullChecks = true
+
+	const useBeforeDeclaration = `const total = count + 1;
+const count = 2;
+export const box = new Box();
+class Box {}
+export const red = Color.Red;
+enum Color { Red }
+export function deferred() { return late; }
+const late = total;
+`
+	runDiagnosticCases(t, strict, []diagnosticCase{
+		{
+			name: "branches",
+			code: `export function partial(flag: boolean): number {
+  let x: number;
+  if (flag) {
+    x = 1;
+  }
+  return x;
+}
+export function both(flag: boolean, n: number): string {
+  let s: string;
+  if (flag) {
+    s = "a";
+  } else {
+    switch (n) {
+      case 1:
+        s = "b";
+        break;
+      default:
+        throw new Error("x");
+    }
+  }
+  return s;
+}
+export function attempt(): number {
+  let r: number;
+  try { r = JSON.parse("1"); } catch { r = 0; }
+  return r;
+}
+`,
+			want: []diagnostic{
+				{line: 6, code: "TS2454", message: "Variable 'x' is used before being assigned."},
+			},
+		},
+		{
+			name: "loops",
+			code: `export function loops(items: number[]): number {
+  let last: number;
+  for (const item of items) {
+    last = item;
+  }
+  let first: number;
+  while (true) {
+    first = 1;
+    break;
+  }
+  let maybe: number | undefined;
+  let definite!: number;
+  const later = () => last;
+  return last + first + (maybe ?? 0) + definite + later();
+}
+`,
+			want: []diagnostic{
+				{line: 14, code: "TS2454", message: "Variable 'last' is used before being assigned."},
+			},
+		},
+		{
+			name: "use before declaration",
+			code: useBeforeDeclaration,
+			want: []diagnostic{
+				{line: 1, code: "TS2448", message: "Block-scoped variable 'count' used before its declaration."},
+				{line: 3, code: "TS2449", message: "Class 'Box' used before its declaration."},
+				{line: 5, code: "TS2450", message: "Enum 'Color' used before its declaration."},
+			},
+		},
+		{
+			// Definite assignment needs strictNullChecks, declaration order does not
+			name: "without strictNullChecks",
+			code: useBeforeDeclaration + `export function partial(flag: boolean): number {
+  let x: number;
+  if (flag) {
+    x = 1;
+  }
+  return x;
+}
+`,
+			config: getDefaultConfig(),
+			want: []diagnostic{
+				{line: 1, code: "TS2448"},
+				{line: 3, code: "TS2449"},
+				{line: 5, code: "TS2450"},
+			},
+		},
+	}, "TS2454", "TS2448", "TS2449", "TS2450")
+}
@@ -0,0 +1,158 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"tstypechecker/pkg/parser"
+	"tstypechecker/pkg/symbols"
+)
+
+// diagnostic is an error a test expects. An empty message or severity
+// matches any.
+type diagnostic struct {
+	line     int
+	code     string
+	message  string
+	severity string
+}
+
+// diagnosticCase is a snippet and the errors checking it should report
+type diagnosticCase struct {
+	name   string
+	code   string
+	config *CompilerConfig // Overrides the config of the run
+	want   []diagnostic
+}
+
+// runDiagnosticCases checks each case as main.ts in a subtest, with the
+// config of the case or config. When codes are given, only errors with one
+// of them are compared.
+func runDiagnosticCases(t *testing.T, config *CompilerConfig, cases []diagnosticCase, codes ...string) {
+	t.Helper()
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			caseConfig := config
+			if c.config != nil {
+				caseConfig = c.config
+			}
+			expectDiagnostics(t, checkDiagnostics(t, c.code, caseConfig, codes...), c.want)
+		})
+	}
+}
+
+// checkDiagnostics checks code as main.ts with config, or the checker's
+// defaults when config is nil. When codes are given, only errors with one of
+// them are returned.
+func checkDiagnostics(t *testing.T, code string, config *CompilerConfig, codes ...string) []TypeError {
+	t.Helper()
+	file, err := parser.ParseCode(code, "main.ts")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	tc := New()
+	if config != nil {
+		tc.SetConfig(config)
+	}
+	return filterDiagnostics(tc.CheckFile("main.ts", file), codes)
+}
+
+// filterDiagnostics returns the errors with one of codes, or all of them
+// when there are no codes
+func filterDiagnostics(errors []TypeError, codes []string) []TypeError {
+	if len(codes) == 0 {
+		return errors
+	}
+	var filtered []TypeError
+	for _, e := range errors {
+		for _, code := range codes {
+			if e.Code == code {
+				filtered = append(filtered, e)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// expectDiagnostics fails t unless got are the errors in want, in order
+func expectDiagnostics(t *testing.T, got []TypeError, want []diagnostic) {
+	t.Helper()
+	matches := len(got) == len(want)
+	for i := 0; matches && i < len(want); i++ {
+		e, w := got[i], want[i]
+		matches = e.Line == w.line && e.Code == w.code &&
+			(w.message == "" || e.Message == w.message) &&
+			(w.severity == "" || e.Severity == w.severity)
+	}
+	if matches {
+		return
+	}
+	for _, e := range got {
+		t.Errorf("got %d: %s %s (%s)", e.Line, e.Code, e.Message, e.Severity)
+	}
+	for _, w := range want {
+		t.Errorf("want %d: %s %s", w.line, w.code, w.message)
+	}
+}
+
+// runProjectCases checks each case as main.ts of a project with files in a
+// subtest
+func runProjectCases(t *testing.T, files map[string]string, cases []diagnosticCase) {
+	t.Helper()
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			expectDiagnostics(t, checkProjectDiagnostics(t, files, c.code), c.want)
+		})
+	}
+}
+
+// checkProjectDiagnostics checks code as main.ts in a project with files,
+// which are keyed by their path in the project, as the check command does
+// for a directory
+func checkProjectDiagnostics(t *testing.T, files map[string]string, code string) []TypeError {
+	t.Helper()
+	return checkProjectFile(t, files, "main.ts", code, nil)
+}
+
+// checkProjectFile is checkProjectDiagnostics for a file named name, checked
+// with config, or the checker's defaults when config is nil
+func checkProjectFile(t *testing.T, files map[string]string, name string, code string, config *CompilerConfig) []TypeError {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	var paths []string
+	for name, content := range files {
+		paths = append(paths, write(name, content))
+	}
+	filename := write(name, code)
+	paths = append(paths, filename)
+	sort.Strings(paths)
+
+	file, err := parser.ParseCode(code, filename)
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	template := NewWithModuleResolver(dir)
+	template.LoadGlobalDeclarations(paths)
+	tc := NewForWorker(template.GetModuleResolver(), symbols.NewSymbolTable())
+	tc.CopyGlobalTypesFrom(template)
+	if config == nil {
+		config = getDefaultConfig()
+	}
+	tc.SetConfig(config)
+	return tc.CheckFile(filename, file)
+}
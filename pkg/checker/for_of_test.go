@@ -0,0 +1,50 @@
+package checker
+
+import "testing"
+
+func TestForOfIterables(t *testing.T) {
+	runDiagnosticCases(t, nil, []diagnosticCase{
+		{
+			name: "arrays and strings",
+			code: `for (const n of [1, 2]) { const s: string = n; }
+for (const c of "ab") { const d: string = c; }
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS2322"}, // the elements are numbers
+			},
+		},
+		{
+			name: "object literal",
+			code: `for (const o of { a: 1 }) {}
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS2488"},
+			},
+		},
+		{
+			name: "primitives and functions",
+			code: `for (const n of 5) {}
+const fn = () => 1;
+for (const r of fn) {}
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS2488"},
+				{line: 3, code: "TS2488"},
+			},
+		},
+		{
+			name: "Symbol.iterator member",
+			code: `interface Bag { [Symbol.iterator](): Iterator<number> }
+declare const bag: Bag;
+for (const item of bag) {}
+`,
+		},
+		{
+			name: "type parameter",
+			code: `function each<T>(items: T) {
+  for (const item of items) {}
+}
+`,
+		},
+	})
+}
@@ -36,6 +36,10 @@ func (gi *GenericInferencer) InferTypeArguments(
 		}
 
 		argType := gi.tc.inferencer.InferType(arg)
+		if typeRef, ok := param.ParamType.(*ast.TypeReference); ok && isConstTypeParameter(typeParams, typeRef.Name) {
+			// const T infers the argument as with 'as const'
+			argType = gi.tc.inferencer.InferConstType(arg)
+		}
 		gi.inferFromTypes(param.ParamType, argType, typeMap)
 	}
 
@@ -139,6 +143,16 @@ func (gi *GenericInferencer) SubstituteTypeParameters(
 	}
 }
 
+// isConstTypeParameter checks if name is a type parameter declared const
+func isConstTypeParameter(typeParams []*ast.TypeParameter, name string) bool {
+	for _, typeParam := range typeParams {
+		if typeParam.Name.Name == name {
+			return typeParam.Const
+		}
+	}
+	return false
+}
+
 // isTypeParameter checks if a name is a common type parameter
 func isTypeParameter(name string) bool {
 	// Common single-letter type parameters
@@ -0,0 +1,161 @@
+package checker
+
+import "testing"
+
+const fakeReactTypes = `declare namespace React {
+  interface ReactElement<P = any> { type: any; props: P; }
+  namespace JSX {
+    interface Element extends React.ReactElement<any> {}
+    interface IntrinsicElements {
+      div: { id?: string; className?: string };
+      span: { className?: string };
+    }
+  }
+}
+declare global {
+  namespace JSX {
+    interface Element extends React.JSX.Element {}
+    interface IntrinsicElements extends React.JSX.IntrinsicElements {}
+  }
+}
+`
+
+// libraryReactTypes declares intrinsic elements the way @types/react does,
+// through generic library types
+const libraryReactTypes = `declare namespace React {
+  type Booleanish = boolean | "true" | "false";
+  type ReactNode = ReactElement | string | number | null | undefined;
+  interface ReactElement<P = any> { type: any; props: P; }
+  interface ClassAttributes<T> { key?: string | number; ref?: any; }
+  interface DOMAttributes<T> { children?: ReactNode; onClick?: (event: any) => void; }
+  interface HTMLAttributes<T> extends DOMAttributes<T> {
+    id?: string;
+    hidden?: boolean;
+    draggable?: Booleanish;
+  }
+  interface InputHTMLAttributes<T> extends HTMLAttributes<T> {
+    disabled?: boolean;
+    value?: string | number;
+  }
+  type DetailedHTMLProps<E extends HTMLAttributes<T>, T> = ClassAttributes<T> & E;
+}
+declare global {
+  namespace JSX {
+    interface Element extends React.ReactElement<any> {}
+    interface IntrinsicElements {
+      div: React.DetailedHTMLProps<React.HTMLAttributes<HTMLDivElement>, HTMLDivElement>;
+      input: React.DetailedHTMLProps<React.InputHTMLAttributes<HTMLInputElement>, HTMLInputElement>;
+    }
+  }
+}
+`
+
+func TestCheckJSX(t *testing.T) {
+	strict := getDefaultConfig()
+	strict.StrictNullChecks = true
+
+	tests := []struct {
+		name   string
+		files  map[string]string
+		code   string
+		config *CompilerConfig
+		want   []diagnostic
+	}{
+		{
+			name:  "components and inline props",
+			files: map[string]string{"node_modules/@types/react/index.d.ts": fakeReactTypes},
+			code: `interface ButtonProps {
+  title: string;
+  count?: number;
+}
+function Button({ title, count }: ButtonProps) {
+  return <div className="btn">{title} {count}</div>;
+}
+function Broken() {
+  return 42;
+}
+const ok = <Button title="ok" count={1} />;
+const badValue = <Button title="ok" count="3" />;
+const missing = <Button count={1} />;
+const excess = <Button title="ok" colour="red" />;
+const unknownTag = <blink>old</blink>;
+const unknownName = <Missing />;
+const notComponent = <Broken />;
+const n: number = <span />;
+const el: JSX.Element = <div id="app" />;
+`,
+			want: []diagnostic{
+				{line: 12, code: "TS2322"}, // count="3"
+				{line: 13, code: "TS2741"}, // title is missing
+				{line: 14, code: "TS2322"}, // colour is not a prop
+				{line: 15, code: "TS2339"}, // blink is not an intrinsic element
+				{line: 16, code: "TS2304"}, // Missing is not declared
+				{line: 17, code: "TS2786"}, // Broken returns a number
+				{line: 18, code: "TS2322"}, // JSX.Element is not a number
+			},
+		},
+		{
+			name:  "library props",
+			files: map[string]string{"node_modules/@types/react/index.d.ts": libraryReactTypes},
+			code: `const ok = <div id="app" hidden={true} draggable="true" onClick={() => {}} data-x="1" key="k">hi</div>;
+const badId = <div id={1} />;
+const badFlag = <input disabled="yes" />;
+const unknown = <div colour="red" />;
+const value = <input value={3} />;
+const badDrag = <div draggable="yes" />;
+`,
+			want: []diagnostic{
+				{line: 2, code: "TS2322"}, // id is a string
+				{line: 3, code: "TS2322"}, // disabled is a boolean
+				{line: 4, code: "TS2322"}, // colour is not an attribute of div
+				{line: 6, code: "TS2322"}, // draggable is Booleanish
+			},
+		},
+		{
+			name: "types entry of the package",
+			files: map[string]string{
+				"node_modules/@types/react/package.json":     `{"name": "@types/react", "types": "ts5.0/index.d.ts"}`,
+				"node_modules/@types/react/ts5.0/index.d.ts": libraryReactTypes,
+			},
+			code: `const badId = <div id={1} />;
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS2322"}, // id is a string
+			},
+		},
+		{
+			name:  "type arguments of generic components",
+			files: map[string]string{"node_modules/@types/react/index.d.ts": fakeReactTypes},
+			code: `function List<T>(props: { items: T[] }) {
+  return <div />;
+}
+const inferred = <List items={["a"]} />;
+const explicit = <List<number> items={[1, 2]} />;
+const wrong = <List<number> items={["a"]} />;
+`,
+			want: []diagnostic{
+				{line: 6, code: "TS2322"}, // items is number[]
+			},
+		},
+		{
+			name:   "optional props keep their declared type",
+			files:  map[string]string{"node_modules/@types/react/index.d.ts": fakeReactTypes},
+			config: strict,
+			code: `function Badge(props: { label: string; count?: number }) {
+  return <span />;
+}
+const bad = <Badge label="x" count="3" />;
+const missing = <Badge count={1} />;
+`,
+			want: []diagnostic{
+				{line: 4, code: "TS2322", message: "Type 'string' is not assignable to type 'number'."},
+				{line: 5, code: "TS2741", message: "Property 'label' is missing in type '{ count: number }' but required in type '{ count?: number; label: string }'."},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectDiagnostics(t, checkProjectFile(t, tt.files, "app.tsx", tt.code, tt.config), tt.want)
+		})
+	}
+}
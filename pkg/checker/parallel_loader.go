@@ -279,24 +279,29 @@ func (pll *ParallelLibLoader) loadTypesPackagesParallel(nodeModulesDir string) {
 
 	var wg sync.WaitGroup
 	numWorkers := runtime.NumCPU()
-	jobs := make(chan string, len(entries))
+	jobs := make(chan struct {
+		dir  string
+		name string
+	}, len(entries))
 
 	// Start workers
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for pkgName := range jobs {
-				pkgDir := filepath.Join(typesDir, pkgName)
-				pll.tc.loadPackageWithCache(pkgDir, "@types/"+pkgName)
+			for job := range jobs {
+				pll.tc.loadPackageWithCache(job.dir, "@types/"+job.name)
 			}
 		}()
 	}
 
 	// Send jobs
 	for _, entry := range entries {
-		if entry.IsDir() {
-			jobs <- entry.Name()
+		if pkgDir, ok := packageDir(typesDir, entry); ok {
+			jobs <- struct {
+				dir  string
+				name string
+			}{pkgDir, entry.Name()}
 		}
 	}
 	close(jobs)
@@ -318,12 +323,11 @@ func (pll *ParallelLibLoader) loadBundledTypesParallel(nodeModulesDir string) {
 
 	// Collect packages to load
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		pkgDir, ok := packageDir(nodeModulesDir, entry)
+		if !ok {
 			continue
 		}
 
-		pkgDir := filepath.Join(nodeModulesDir, entry.Name())
-
 		// Handle scoped packages
 		if strings.HasPrefix(entry.Name(), "@") {
 			if entry.Name() == "@types" {
@@ -336,11 +340,11 @@ func (pll *ParallelLibLoader) loadBundledTypesParallel(nodeModulesDir string) {
 			}
 
 			for _, scopeEntry := range scopeEntries {
-				if !scopeEntry.IsDir() {
+				scopedPkgDir, ok := packageDir(pkgDir, scopeEntry)
+				if !ok {
 					continue
 				}
 
-				scopedPkgDir := filepath.Join(pkgDir, scopeEntry.Name())
 				packageJSONPath := filepath.Join(scopedPkgDir, "package.json")
 
 				if typesFile := pll.tc.getPackageTypesFile(packageJSONPath); typesFile != "" {
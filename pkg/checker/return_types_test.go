@@ -0,0 +1,24 @@
+package checker
+
+import "testing"
+
+func TestInferredReturnTypes(t *testing.T) {
+	runDiagnosticCases(t, nil, []diagnosticCase{
+		{
+			name: "union of returns",
+			code: `export function pick(c: boolean) {
+  if (c) return 1;
+  return "s";
+}
+`,
+		},
+		{
+			name: "early null",
+			code: `export function render(c: boolean) {
+  if (!c) return null;
+  return { ok: true };
+}
+`,
+		},
+	}, "TS2322")
+}
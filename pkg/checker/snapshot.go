@@ -74,6 +74,18 @@ func (sm *SnapshotManager) computeLibsHash(libs []string, typescriptLibPath stri
 	h.Write([]byte(typescriptLibPath))
 
 	// Check modification time of ALL requested lib files
+	for _, fullPath := range sm.libFiles(libs, typescriptLibPath) {
+		if info, err := os.Stat(fullPath); err == nil {
+			h.Write([]byte(info.ModTime().String()))
+			h.Write([]byte(fmt.Sprintf("%d", info.Size())))
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+}
+
+// libFiles returns the paths of the requested lib files
+func (sm *SnapshotManager) libFiles(libs []string, typescriptLibPath string) []string {
 	// We map the lib names to actual files using the same logic as checker_libs.go
 	libFileMap := map[string]string{
 		"es5":          "lib.es5.d.ts",
@@ -95,18 +107,14 @@ func (sm *SnapshotManager) computeLibsHash(libs []string, typescriptLibPath stri
 		"scripthost":   "lib.scripthost.d.ts",
 	}
 
+	var files []string
 	for _, lib := range libs {
 		libLower := strings.ToLower(lib)
 		if fileName, ok := libFileMap[libLower]; ok {
-			fullPath := filepath.Join(typescriptLibPath, fileName)
-			if info, err := os.Stat(fullPath); err == nil {
-				h.Write([]byte(info.ModTime().String()))
-				h.Write([]byte(fmt.Sprintf("%d", info.Size())))
-			}
+			files = append(files, filepath.Join(typescriptLibPath, fileName))
 		}
 	}
-
-	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+	return files
 }
 
 // SaveSnapshot saves the current state to a binary snapshot
@@ -131,6 +139,10 @@ func (sm *SnapshotManager) SaveSnapshot(tc *TypeChecker, libs []string, snapshot
 	// Flatten symbol table (only global scope)
 	if tc.symbolTable != nil && tc.symbolTable.Global != nil {
 		for name, sym := range tc.symbolTable.Global.Symbols {
+			// Declarations of the checked file are not part of the libs
+			if sym.Node != nil && !sym.FromDTS {
+				continue
+			}
 			snapshot.Symbols[name] = &SymbolData{
 				Name:       sym.Name,
 				Type:       sym.Type,
@@ -197,6 +209,10 @@ func (sm *SnapshotManager) LoadSnapshot(tc *TypeChecker, snapshotPath string) er
 	// Restore symbols to global scope
 	if tc.symbolTable != nil && tc.symbolTable.Global != nil {
 		for name, symData := range snapshot.Symbols {
+			// Keep the declarations already bound, which the libs merge into
+			if existing, exists := tc.symbolTable.Global.Symbols[name]; exists && existing.Node != nil {
+				continue
+			}
 			sym := &symbols.Symbol{
 				Name:       symData.Name,
 				Type:       symData.Type,
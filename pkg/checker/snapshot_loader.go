@@ -53,6 +53,9 @@ func (tc *TypeChecker) LoadTypeScriptLibsWithSnapshot(libs []string) {
 		}
 
 		if loadErr := snapshotMgr.LoadSnapshot(tc, snapshotPath); loadErr == nil {
+			for _, path := range snapshotMgr.libFiles(libs, typescriptLibPath) {
+				tc.addDeclarationFile(path)
+			}
 			if tc.profiler.IsEnabled() {
 				tc.profiler.EndSubPhase("TypeScript Libs Loading", "Load from Snapshot")
 				tc.profiler.RecordCacheHit("TypeScript Libs Loading")
@@ -11,13 +11,20 @@ type TypeNarrowing struct {
 	tc *TypeChecker
 	// Maps variable names to their narrowed types within a scope
 	narrowedTypes map[string]*types.Type
+	// Switch statements that handle every member of the union they discriminate
+	exhaustiveSwitches map[*ast.SwitchStatement]bool
+	// Assignments seen per variable, so that restoring a narrowing keeps the
+	// type of a later assignment
+	assignments map[string]int
 }
 
 // NewTypeNarrowing creates a new type narrowing analyzer
 func NewTypeNarrowing(tc *TypeChecker) *TypeNarrowing {
 	return &TypeNarrowing{
-		tc:            tc,
-		narrowedTypes: make(map[string]*types.Type),
+		tc:                 tc,
+		narrowedTypes:      make(map[string]*types.Type),
+		exhaustiveSwitches: make(map[*ast.SwitchStatement]bool),
+		assignments:        make(map[string]int),
 	}
 }
 
@@ -26,15 +33,31 @@ func (tn *TypeNarrowing) AnalyzeCondition(condition ast.Expression) (thenNarrowi
 	thenNarrowing = make(map[string]*types.Type)
 	elseNarrowing = make(map[string]*types.Type)
 
-	// Handle binary expressions (===, ==, !==, !=)
+	// Handle binary expressions (===, ==, !==, !=, instanceof, in, &&, ||)
 	if binExpr, ok := condition.(*ast.BinaryExpression); ok {
-		if binExpr.Operator == "===" || binExpr.Operator == "==" {
+		switch binExpr.Operator {
+		case "===", "==":
 			tn.analyzeEquality(binExpr, thenNarrowing, elseNarrowing, false)
 			tn.analyzeTypeof(binExpr, thenNarrowing, elseNarrowing)
-		} else if binExpr.Operator == "!==" || binExpr.Operator == "!=" {
+			tn.analyzeNullEquality(binExpr, thenNarrowing, elseNarrowing)
+		case "!==", "!=":
 			tn.analyzeEquality(binExpr, elseNarrowing, thenNarrowing, true)
 			tn.analyzeTypeof(binExpr, elseNarrowing, thenNarrowing)
+			tn.analyzeNullEquality(binExpr, elseNarrowing, thenNarrowing)
+		case "instanceof":
+			tn.analyzeInstanceof(binExpr, thenNarrowing, elseNarrowing)
+		case "in":
+			tn.analyzeIn(binExpr, thenNarrowing, elseNarrowing)
+		case "&&", "||":
+			return tn.analyzeLogical(binExpr)
 		}
+	} else if unaryExpr, ok := condition.(*ast.UnaryExpression); ok && unaryExpr.Operator == "!" {
+		// Handle negation: if (!x) swaps the branches of if (x)
+		negatedThen, negatedElse := tn.AnalyzeCondition(unaryExpr.Argument)
+		return negatedElse, negatedThen
+	} else if id, ok := condition.(*ast.Identifier); ok {
+		// Handle truthiness check: if (x)
+		tn.analyzeIdentifierTruthiness(id, thenNarrowing)
 	} else if memberExpr, ok := condition.(*ast.MemberExpression); ok {
 		// Handle truthiness check: if (obj.prop)
 		tn.analyzeTruthiness(memberExpr, thenNarrowing, elseNarrowing)
@@ -323,9 +346,17 @@ func (tn *TypeNarrowing) ApplyNarrowing(narrowing map[string]*types.Type) (resto
 		tn.tc.varTypeCache[varName] = narrowedType
 	}
 
-	// Return a function to restore original types
+	// Return a function to restore original types. A variable assigned since
+	// keeps the type of the assignment.
+	assignments := make(map[string]int)
+	for varName := range narrowing {
+		assignments[varName] = tn.assignments[varName]
+	}
 	return func() {
 		for varName := range narrowing {
+			if tn.assignments[varName] != assignments[varName] {
+				continue
+			}
 			if originalType, exists := originalTypes[varName]; exists {
 				tn.tc.varTypeCache[varName] = originalType
 			} else {
@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/types"
+)
+
+// declaredType returns the type a variable or parameter was declared with,
+// before any narrowing, or nil when it is not known
+func (tn *TypeNarrowing) declaredType(name string) *types.Type {
+	symbol, exists := tn.tc.symbolTable.ResolveSymbol(name)
+	if !exists || symbol.Node == nil {
+		return nil
+	}
+	switch n := symbol.Node.(type) {
+	case *ast.VariableDeclarator:
+		if n.ID != nil {
+			return tn.tc.typeCache[n.ID]
+		}
+	case *ast.Parameter:
+		if n.ID != nil {
+			return tn.tc.typeCache[n.ID]
+		}
+	}
+	return nil
+}
+
+// NarrowByAssignment narrows a variable a value of type assigned is assigned
+// to, to the members of its declared union the value belongs to. The type stays
+// narrowed until the next assignment.
+func (tn *TypeNarrowing) NarrowByAssignment(name string, assigned *types.Type) {
+	if _, exists := tn.tc.varTypeCache[name]; !exists {
+		return
+	}
+	if declared := tn.declaredType(name); declared != nil {
+		tn.assign(name, tn.assignmentReduction(declared, assigned))
+	}
+}
+
+// assignmentReduction narrows declared to the members assigned may belong to,
+// or returns declared when it is not a union
+func (tn *TypeNarrowing) assignmentReduction(declared, assigned *types.Type) *types.Type {
+	if assigned == nil || assigned.Kind == types.AnyType || assigned.Kind == types.UnknownType {
+		return declared
+	}
+	if declared.Kind != types.UnionType {
+		return declared
+	}
+
+	parts := []*types.Type{assigned}
+	if assigned.Kind == types.UnionType {
+		parts = assigned.Types
+	}
+	var kept []*types.Type
+	for _, member := range declared.Types {
+		for _, part := range parts {
+			if tn.tc.isAssignableTo(part, member) {
+				kept = append(kept, member)
+				break
+			}
+		}
+	}
+	if len(kept) == 0 {
+		return declared
+	}
+	return unionOfTypes(kept)
+}
+
+// assignedVariables returns the variables assigned in nodes, leaving out
+// nested functions, which run at some other time
+func assignedVariables(nodes ...ast.Node) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(expr ast.Expression) {
+		if id, ok := expr.(*ast.Identifier); ok && !seen[id.Name] {
+			seen[id.Name] = true
+			names = append(names, id.Name)
+		}
+	}
+	for _, node := range nodes {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch e := n.(type) {
+			case *ast.FunctionDeclaration, *ast.FunctionExpression, *ast.ArrowFunctionExpression, *ast.ClassDeclaration:
+				return false
+			case *ast.AssignmentExpression:
+				add(e.Left)
+			case *ast.UnaryExpression:
+				if e.Operator == "++" || e.Operator == "--" {
+					add(e.Argument)
+				}
+			}
+			return n != nil
+		})
+	}
+	return names
+}
+
+// WidenAssigned resets the variables assigned in nodes to their declared
+// type. A loop body may run after any of its iterations, so the narrowing of
+// an assignment in it does not hold at its start or after it.
+func (tn *TypeNarrowing) WidenAssigned(nodes ...ast.Node) {
+	for _, name := range assignedVariables(nodes...) {
+		if _, exists := tn.tc.varTypeCache[name]; !exists {
+			continue
+		}
+		if declared := tn.declaredType(name); declared != nil {
+			tn.assign(name, declared)
+		}
+	}
+}
+
+// assign gives a variable the type of a value assigned to it
+func (tn *TypeNarrowing) assign(name string, t *types.Type) {
+	tn.tc.varTypeCache[name] = t
+	tn.assignments[name]++
+}
+
+// branchJoin gives the variables assigned in the branches of a statement the
+// union of their types at the end of the branches that complete normally
+type branchJoin struct {
+	tn     *TypeNarrowing
+	before map[string]*types.Type
+	ends   map[string][]*types.Type
+	joined bool // some branch completes normally
+}
+
+// beginBranches starts joining the variables assigned in the branches nodes
+func (tn *TypeNarrowing) beginBranches(nodes ...ast.Node) *branchJoin {
+	j := &branchJoin{
+		tn:     tn,
+		before: make(map[string]*types.Type),
+		ends:   make(map[string][]*types.Type),
+	}
+	for _, name := range assignedVariables(nodes...) {
+		if t, exists := tn.tc.varTypeCache[name]; exists {
+			j.before[name] = t
+		}
+	}
+	return j
+}
+
+// assigns reports whether a branch assigns the variable name
+func (j *branchJoin) assigns(name string) bool {
+	_, assigned := j.before[name]
+	return assigned
+}
+
+// endBranch records the end of a branch, unless it always exits, and resets
+// the variables for the next branch
+func (j *branchJoin) endBranch(exits bool) {
+	for name, before := range j.before {
+		if end := j.tn.tc.varTypeCache[name]; !exits && end != nil {
+			j.ends[name] = append(j.ends[name], end)
+		}
+		j.tn.tc.varTypeCache[name] = before
+	}
+	j.joined = j.joined || !exits
+}
+
+// finish gives each variable the union of its types at the end of the
+// branches that complete normally
+func (j *branchJoin) finish() {
+	if !j.joined {
+		return
+	}
+	for name, ends := range j.ends {
+		j.tn.tc.varTypeCache[name] = unionOfTypes(ends)
+	}
+}
@@ -0,0 +1,79 @@
+package checker
+
+import "testing"
+
+func TestAssignmentAndTruthinessNarrowing(t *testing.T) {
+	config := getDefaultConfig()
+	config.StrictNullChecks = true
+
+	const user = "interface User { name: string }\n"
+	runDiagnosticCases(t, config, []diagnosticCase{
+		{
+			name: "negated guard",
+			code: user + `export function guard(a: User | undefined, b: User | undefined): number {
+  if (!a || !b) return 0;
+  return a.name.length + b.name.length;
+}
+`,
+		},
+		{
+			name: "null check",
+			code: user + `export function nullCheck(a: User | null): number {
+  if (a == null) {
+    return 0;
+  }
+  return a.name.length;
+}
+`,
+		},
+		{
+			name: "assignment",
+			code: `export function assigned(): number {
+  let name: string | undefined;
+  name = "ada";
+  return name.length;
+}
+`,
+		},
+		{
+			name: "else exits",
+			code: user + `export function elseExits(u: User | undefined): number {
+  if (u) {
+    u.name;
+  } else {
+    return 0;
+  }
+  return u.name.length;
+}
+`,
+		},
+		{
+			name: "branches join",
+			code: `export function joined(reset: boolean): void {
+  let name: string | undefined = "ada";
+  if (reset) {
+    name = undefined;
+  }
+  name.length;
+}
+`,
+			want: []diagnostic{
+				{line: 6, code: "TS2532"}, // name may have been reset
+			},
+		},
+		{
+			name: "loop widens",
+			code: `export function looped(items: string[]): void {
+  let last: string | undefined = "ada";
+  for (const item of items) {
+    last.length;
+    last = undefined;
+  }
+}
+`,
+			want: []diagnostic{
+				{line: 4, code: "TS2532"}, // last is undefined after the first iteration
+			},
+		},
+	})
+}
@@ -0,0 +1,175 @@
+package checker
+
+import (
+	"strings"
+
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/types"
+)
+
+// analyzeInstanceof narrows x in x instanceof C to the instances of C. A union
+// keeps the members that are instances of C in the then branch and the others
+// in the else branch.
+func (tn *TypeNarrowing) analyzeInstanceof(binExpr *ast.BinaryExpression, trueNarrowing, falseNarrowing map[string]*types.Type) {
+	id, ok := binExpr.Left.(*ast.Identifier)
+	if !ok {
+		return
+	}
+	instance := tn.instanceType(binExpr.Right)
+	if instance == nil {
+		return
+	}
+
+	varType, exists := tn.tc.varTypeCache[id.Name]
+	if !exists || varType == nil || varType.Kind != types.UnionType {
+		trueNarrowing[id.Name] = instance
+		return
+	}
+
+	var instances, others []*types.Type
+	for _, member := range varType.Types {
+		if member.Kind == types.ObjectType && tn.tc.isAssignableTo(member, instance) {
+			instances = append(instances, member)
+		} else {
+			others = append(others, member)
+		}
+	}
+	if len(instances) == 0 {
+		trueNarrowing[id.Name] = instance
+		return
+	}
+	trueNarrowing[id.Name] = unionOfTypes(instances)
+	falseNarrowing[id.Name] = unionOfTypes(others)
+}
+
+// instanceType returns the type new creates with a constructor expression, or
+// nil when it is not known
+func (tn *TypeNarrowing) instanceType(ctor ast.Expression) *types.Type {
+	if id, ok := ctor.(*ast.Identifier); ok && id.Name == "Function" {
+		return types.NewFunctionType(nil, types.Any)
+	}
+	instance := tn.tc.inferencer.InferType(&ast.NewExpression{Callee: ctor, Position: ctor.Pos()})
+	if instance == nil || instance.Kind == types.UnknownType || instance.Kind == types.AnyType {
+		return nil
+	}
+	return instance
+}
+
+// analyzeIn narrows x in "prop" in x to the members of its union that declare
+// prop, and to the others, or those where prop is optional, in the else branch
+func (tn *TypeNarrowing) analyzeIn(binExpr *ast.BinaryExpression, trueNarrowing, falseNarrowing map[string]*types.Type) {
+	lit, ok := binExpr.Left.(*ast.Literal)
+	id, isId := binExpr.Right.(*ast.Identifier)
+	if !ok || !isId {
+		return
+	}
+	propName, ok := lit.Value.(string)
+	if !ok {
+		return
+	}
+	propName = strings.Trim(propName, `"'`)
+
+	varType, exists := tn.tc.varTypeCache[id.Name]
+	if !exists || varType == nil {
+		return
+	}
+	if varType.Kind != types.UnionType && varType.Name != "" {
+		if resolved, exists := tn.tc.typeAliasCache[varType.Name]; exists {
+			varType = resolved
+		}
+	}
+	if varType.Kind != types.UnionType {
+		return
+	}
+
+	var with, without []*types.Type
+	for _, member := range varType.Types {
+		if member.Kind != types.ObjectType {
+			return
+		}
+		propType, declared := member.Properties[propName]
+		if declared {
+			with = append(with, member)
+		}
+		if !declared || includesUndefined(propType) {
+			without = append(without, member)
+		}
+	}
+	if len(with) > 0 {
+		trueNarrowing[id.Name] = unionOfTypes(with)
+	}
+	if len(without) > 0 {
+		falseNarrowing[id.Name] = unionOfTypes(without)
+	}
+}
+
+// analyzeLogical narrows a && b and a || b. The right operand only runs when
+// the left one is truthy (&&) or falsy (||), so its narrowing builds on the
+// left one.
+func (tn *TypeNarrowing) analyzeLogical(binExpr *ast.BinaryExpression) (thenNarrowing, elseNarrowing map[string]*types.Type) {
+	leftThen, leftElse := tn.AnalyzeCondition(binExpr.Left)
+	rightEntry := leftThen
+	if binExpr.Operator == "||" {
+		rightEntry = leftElse
+	}
+	restore := tn.ApplyNarrowing(rightEntry)
+	rightThen, rightElse := tn.AnalyzeCondition(binExpr.Right)
+	restore()
+
+	if binExpr.Operator == "&&" {
+		// Both operands are truthy in the then branch, either may be falsy in
+		// the else branch
+		return mergeNarrowings(leftThen, rightThen), joinNarrowings(leftElse, mergeNarrowings(leftThen, rightElse))
+	}
+	return joinNarrowings(leftThen, mergeNarrowings(leftElse, rightThen)), mergeNarrowings(leftElse, rightElse)
+}
+
+// mergeNarrowings applies the narrowing b on top of a
+func mergeNarrowings(a, b map[string]*types.Type) map[string]*types.Type {
+	merged := make(map[string]*types.Type, len(a)+len(b))
+	for name, t := range a {
+		merged[name] = t
+	}
+	for name, t := range b {
+		merged[name] = t
+	}
+	return merged
+}
+
+// joinNarrowings narrows the variables narrowed on both of two paths to the
+// union of their types
+func joinNarrowings(a, b map[string]*types.Type) map[string]*types.Type {
+	joined := make(map[string]*types.Type)
+	for name, t := range a {
+		if other, exists := b[name]; exists {
+			joined[name] = unionOfTypes([]*types.Type{t, other})
+		}
+	}
+	return joined
+}
+
+// unionOfTypes returns the union of ts without duplicate members, or the only
+// member when there is one
+func unionOfTypes(ts []*types.Type) *types.Type {
+	var members []*types.Type
+	seen := make(map[string]bool)
+	for _, t := range ts {
+		flat := []*types.Type{t}
+		if t.Kind == types.UnionType {
+			flat = t.Types
+		}
+		for _, member := range flat {
+			if key := member.String(); !seen[key] {
+				seen[key] = true
+				members = append(members, member)
+			}
+		}
+	}
+	switch len(members) {
+	case 0:
+		return types.Never
+	case 1:
+		return members[0]
+	}
+	return types.NewUnionType(members)
+}
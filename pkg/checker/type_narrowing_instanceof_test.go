@@ -0,0 +1,87 @@
+package checker
+
+import "testing"
+
+func TestInstanceofAndInNarrowing(t *testing.T) {
+	const pets = `class Dog { bark(): string { return "woof"; } }
+class Cat { meow(): string { return "meow"; } }
+`
+	const httpError = `class HttpError extends Error {
+  status: number = 500;
+}
+`
+	runDiagnosticCases(t, nil, []diagnosticCase{
+		{
+			name: "unknown",
+			code: httpError + `export function status(e: unknown): number {
+  if (e instanceof HttpError) {
+    return e.status;
+  }
+  return 0;
+}
+`,
+		},
+		{
+			name: "union with else",
+			code: pets + `export function speak(pet: Dog | Cat): string {
+  if (pet instanceof Dog) {
+    return pet.bark();
+  } else {
+    return pet.meow();
+  }
+}
+`,
+		},
+		{
+			name: "conditional expression",
+			code: pets + `export function speakInline(pet: Dog | Cat): string {
+  return pet instanceof Dog ? pet.bark() : pet.meow();
+}
+`,
+		},
+		{
+			name: "wrong member",
+			code: pets + `export function wrong(pet: Dog | Cat): string {
+  if (pet instanceof Cat) {
+    return pet.bark();
+  }
+  return "";
+}
+`,
+			want: []diagnostic{
+				{line: 5, code: "TS2339"}, // pet is a Cat
+			},
+		},
+		{
+			name: "in",
+			code: `interface Fish { swim(): void; name: string }
+interface Bird { fly(): void; name: string }
+export function move(animal: Fish | Bird): void {
+  if ("swim" in animal) {
+    animal.swim();
+  } else {
+    animal.fly();
+  }
+}
+`,
+		},
+		{
+			name: "and chain",
+			code: httpError + `export function failed(ready: boolean, e: unknown): boolean {
+  return ready && e instanceof HttpError && e.status > 400;
+}
+`,
+		},
+		{
+			name: "constructor type",
+			code: pets + `type DogConstructor = new () => Dog;
+export function viaConstructor(pet: Dog | Cat, Kind: DogConstructor): string {
+  if (pet instanceof Kind) {
+    return pet.bark();
+  }
+  return "";
+}
+`,
+		},
+	})
+}
@@ -0,0 +1,233 @@
+package checker
+
+import (
+	"fmt"
+
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/types"
+)
+
+// AnalyzeSwitch narrows the variable a switch statement discriminates in each
+// of its cases: x in switch (x.kind) over a discriminated union, switch (x)
+// over a union of literals and switch (typeof x). A case is also entered from
+// the case before it when that one falls through. noMatch narrows the code
+// reached when no case matches, and exhaustive reports that every member of
+// the union is handled without a default case.
+func (tn *TypeNarrowing) AnalyzeSwitch(stmt *ast.SwitchStatement) (caseNarrowings []map[string]*types.Type, noMatch map[string]*types.Type, exhaustive bool) {
+	caseNarrowings = make([]map[string]*types.Type, len(stmt.Cases))
+	for i := range caseNarrowings {
+		caseNarrowings[i] = make(map[string]*types.Type)
+	}
+	noMatch = make(map[string]*types.Type)
+
+	varName, members, tag := tn.switchDiscriminant(stmt.Discriminant)
+	if varName == "" {
+		return caseNarrowings, noMatch, false
+	}
+
+	// Members matched by the test of each case; a test that is not a literal
+	// may match any of them
+	matched := make([][]bool, len(stmt.Cases))
+	handled := make([]bool, len(members))
+	known, hasDefault := true, false
+	for i, clause := range stmt.Cases {
+		matched[i] = make([]bool, len(members))
+		if clause.Test == nil {
+			hasDefault = true
+			continue
+		}
+		value, ok := switchCaseValue(clause.Test)
+		for j, member := range members {
+			if !ok || tag(member) == value {
+				matched[i][j] = true
+				handled[j] = handled[j] || ok
+			}
+		}
+		known = known && ok
+	}
+
+	remaining := make([]bool, len(members))
+	for j := range members {
+		remaining[j] = !known || !handled[j]
+	}
+
+	entering := make([]bool, len(members))
+	for i, clause := range stmt.Cases {
+		direct := matched[i]
+		if clause.Test == nil {
+			direct = remaining
+		}
+		for j := range members {
+			entering[j] = entering[j] || direct[j]
+		}
+		caseNarrowings[i][varName] = unionOfMembers(members, entering)
+
+		if len(clause.Consequent) > 0 && tn.tc.listCompletesAbruptly(clause.Consequent, true) {
+			entering = make([]bool, len(members))
+		}
+	}
+
+	if !hasDefault && known {
+		noMatch[varName] = unionOfMembers(members, remaining)
+		exhaustive = noMatch[varName].Kind == types.NeverType
+	}
+	return caseNarrowings, noMatch, exhaustive
+}
+
+// switchDiscriminant returns the variable a switch discriminant narrows, the
+// members of its union type and how a case value identifies a member
+func (tn *TypeNarrowing) switchDiscriminant(discriminant ast.Expression) (string, []*types.Type, func(*types.Type) string) {
+	switch d := discriminant.(type) {
+	case *ast.MemberExpression:
+		// switch (shape.kind)
+		objId, ok := d.Object.(*ast.Identifier)
+		propId, isId := d.Property.(*ast.Identifier)
+		if !ok || !isId || d.Computed {
+			return "", nil, nil
+		}
+		members := tn.unionMembers(objId)
+		for _, member := range members {
+			if _, ok := discriminantValue(member, propId.Name); !ok {
+				return "", nil, nil
+			}
+		}
+		return objId.Name, members, func(member *types.Type) string {
+			value, _ := discriminantValue(member, propId.Name)
+			return value
+		}
+	case *ast.Identifier:
+		// switch (status) over a union of literals
+		members := tn.unionMembers(d)
+		for _, member := range members {
+			if member.Kind != types.LiteralType {
+				return "", nil, nil
+			}
+		}
+		return d.Name, members, literalTypeValue
+	case *ast.UnaryExpression:
+		// switch (typeof value)
+		argId, ok := d.Argument.(*ast.Identifier)
+		if d.Operator != "typeof" || !ok {
+			return "", nil, nil
+		}
+		members := tn.unionMembers(argId)
+		for _, member := range members {
+			if typeofTag(member) == "" {
+				return "", nil, nil
+			}
+		}
+		return argId.Name, members, typeofTag
+	}
+	return "", nil, nil
+}
+
+// unionMembers returns the members of the union type of a variable, with
+// boolean as true | false
+func (tn *TypeNarrowing) unionMembers(id *ast.Identifier) []*types.Type {
+	varType, exists := tn.tc.varTypeCache[id.Name]
+	if !exists || varType == nil {
+		return nil
+	}
+	if varType.Kind != types.UnionType && varType.Name != "" {
+		if resolved, exists := tn.tc.typeAliasCache[varType.Name]; exists {
+			varType = resolved
+		}
+	}
+	switch varType.Kind {
+	case types.UnionType:
+		var members []*types.Type
+		for _, member := range varType.Types {
+			if member.Kind == types.BooleanType {
+				members = append(members, types.NewLiteralType(true), types.NewLiteralType(false))
+			} else {
+				members = append(members, member)
+			}
+		}
+		return members
+	case types.BooleanType:
+		return []*types.Type{types.NewLiteralType(true), types.NewLiteralType(false)}
+	}
+	return nil
+}
+
+// discriminantValue returns the literal value of the property prop of an
+// object member of a discriminated union
+func discriminantValue(member *types.Type, prop string) (string, bool) {
+	if member.Kind != types.ObjectType || member.Properties == nil {
+		return "", false
+	}
+	propType, exists := member.Properties[prop]
+	if !exists || propType.Kind != types.LiteralType {
+		return "", false
+	}
+	return literalTypeValue(propType), true
+}
+
+func literalTypeValue(t *types.Type) string {
+	return fmt.Sprint(normalizeLiteralValue(t.Value))
+}
+
+// switchCaseValue returns the value of a literal case test
+func switchCaseValue(test ast.Expression) (string, bool) {
+	lit, ok := test.(*ast.Literal)
+	if !ok || lit.Value == nil {
+		return "", false
+	}
+	return fmt.Sprint(normalizeLiteralValue(lit.Value)), true
+}
+
+// typeofTag returns what typeof evaluates to for values of t, or "" when it
+// depends on the value
+func typeofTag(t *types.Type) string {
+	switch t.Kind {
+	case types.StringType, types.TemplateLiteralType:
+		return "string"
+	case types.NumberType:
+		return "number"
+	case types.BooleanType:
+		return "boolean"
+	case types.BigIntType:
+		return "bigint"
+	case types.SymbolType:
+		return "symbol"
+	case types.UndefinedType, types.VoidType:
+		return "undefined"
+	case types.FunctionType:
+		return "function"
+	case types.NullType, types.ArrayType, types.TupleType:
+		return "object"
+	case types.ObjectType:
+		if t.IsFunction || len(t.CallSignatures) > 0 {
+			return "function"
+		}
+		return "object"
+	case types.LiteralType:
+		switch t.Value.(type) {
+		case string:
+			return "string"
+		case bool:
+			return "boolean"
+		case float64, int, int64:
+			return "number"
+		}
+	}
+	return ""
+}
+
+// unionOfMembers returns the union of the selected members, or never when
+// none is selected
+func unionOfMembers(members []*types.Type, selected []bool) *types.Type {
+	var picked []*types.Type
+	for j, member := range members {
+		if selected[j] {
+			picked = append(picked, member)
+		}
+	}
+	switch len(picked) {
+	case 0:
+		return types.Never
+	case 1:
+		return picked[0]
+	}
+	return types.NewUnionType(picked)
+}
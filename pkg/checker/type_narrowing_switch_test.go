@@ -0,0 +1,88 @@
+package checker
+
+import "testing"
+
+func TestSwitchNarrowing(t *testing.T) {
+	config := getDefaultConfig()
+	config.StrictNullChecks = true
+
+	const shapes = `interface Circle { kind: "circle"; radius: number }
+interface Square { kind: "square"; size: number }
+type Shape = Circle | Square;
+`
+	runDiagnosticCases(t, config, []diagnosticCase{
+		{
+			name: "exhaustive discriminant",
+			code: shapes + `export function area(s: Shape): number {
+  switch (s.kind) {
+    case "circle":
+      return s.radius * s.radius;
+    case "square":
+      return s.size * s.size;
+  }
+}
+`,
+		},
+		{
+			name: "narrowed per case",
+			code: shapes + `export function side(s: Shape): number {
+  switch (s.kind) {
+    case "circle":
+      return s.size;
+    default:
+      const rest: never = s;
+      return rest;
+  }
+}
+`,
+			want: []diagnostic{
+				{line: 7, code: "TS2339"}, // s is a Circle in its case
+				{line: 9, code: "TS2322"}, // Square is left for the default case
+			},
+		},
+		{
+			name: "missing literal",
+			code: `type Status = "on" | "off" | "idle";
+export function level(status: Status): number {
+  switch (status) {
+    case "on":
+      return 1;
+    case "off":
+      return 0;
+  }
+}
+`,
+			want: []diagnostic{
+				{line: 2, code: "TS2366"}, // "idle" is not handled
+			},
+		},
+		{
+			name: "typeof",
+			code: `export function describe(value: string | number): number {
+  switch (typeof value) {
+    case "string":
+      return value.length;
+    case "number":
+      return value;
+  }
+}
+`,
+		},
+		{
+			name: "boolean",
+			code: `export function flag(on: boolean): number {
+  let result: number;
+  switch (on) {
+    case true:
+      result = 1;
+      break;
+    case false:
+      result = 0;
+      break;
+  }
+  return result;
+}
+`,
+		},
+	})
+}
@@ -80,3 +80,80 @@ func (tn *TypeNarrowing) analyzeTruthiness(memberExpr *ast.MemberExpression, tru
 		}
 	}
 }
+
+// analyzeIdentifierTruthiness narrows x in if (x) to the members of its union
+// other than null and undefined
+func (tn *TypeNarrowing) analyzeIdentifierTruthiness(id *ast.Identifier, trueNarrowing map[string]*types.Type) {
+	varType, exists := tn.tc.varTypeCache[id.Name]
+	if !exists || varType == nil || varType.Kind != types.UnionType {
+		return
+	}
+	var defined []*types.Type
+	for _, t := range varType.Types {
+		if !isNullish(t) {
+			defined = append(defined, t)
+		}
+	}
+	if len(defined) > 0 && len(defined) < len(varType.Types) {
+		trueNarrowing[id.Name] = unionOfTypes(defined)
+	}
+}
+
+// analyzeNullEquality narrows x in x === null, x === undefined and x == null,
+// where == matches both null and undefined
+func (tn *TypeNarrowing) analyzeNullEquality(binExpr *ast.BinaryExpression, equalNarrowing, otherNarrowing map[string]*types.Type) {
+	id, ok := binExpr.Left.(*ast.Identifier)
+	other := binExpr.Right
+	if !ok || isNullishExpression(id) {
+		id, ok = binExpr.Right.(*ast.Identifier)
+		other = binExpr.Left
+	}
+	if !ok || !isNullishExpression(other) {
+		return
+	}
+	varType, exists := tn.tc.varTypeCache[id.Name]
+	if !exists || varType == nil || varType.Kind != types.UnionType {
+		return
+	}
+
+	loose := binExpr.Operator == "==" || binExpr.Operator == "!="
+	matches := func(t *types.Type) bool {
+		if loose {
+			return isNullish(t)
+		}
+		if _, isNull := other.(*ast.Literal); isNull {
+			return t.Kind == types.NullType
+		}
+		return t.Kind == types.UndefinedType || t.Kind == types.VoidType
+	}
+	var equal, rest []*types.Type
+	for _, t := range varType.Types {
+		if matches(t) {
+			equal = append(equal, t)
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	if len(equal) > 0 {
+		equalNarrowing[id.Name] = unionOfTypes(equal)
+	}
+	if len(rest) > 0 && len(rest) < len(varType.Types) {
+		otherNarrowing[id.Name] = unionOfTypes(rest)
+	}
+}
+
+// isNullish reports whether t is null, undefined or void
+func isNullish(t *types.Type) bool {
+	return t.Kind == types.NullType || t.Kind == types.UndefinedType || t.Kind == types.VoidType
+}
+
+// isNullishExpression reports whether expr is the null literal or undefined
+func isNullishExpression(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.Literal:
+		return e.Value == nil
+	case *ast.Identifier:
+		return e.Name == "undefined"
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"tstypechecker/pkg/types"
+)
+
+// keyofType resolves keyof T to the union of the property names of an object
+// type T, or leaves it unresolved for other types
+func keyofType(target *types.Type) *types.Type {
+	if target.Kind != types.ObjectType || len(target.Properties) == 0 {
+		return types.NewKeyOfType(target)
+	}
+	names := make([]string, 0, len(target.Properties))
+	for name := range target.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	keys := make([]*types.Type, len(names))
+	for i, name := range names {
+		keys[i] = types.NewLiteralType(name)
+	}
+	if len(keys) == 1 {
+		return keys[0]
+	}
+	return types.NewUnionType(keys)
+}
+
+// indexedAccessType resolves T[K] for an object, tuple or array type T, or
+// returns nil when it cannot be resolved
+func indexedAccessType(objectType, indexType *types.Type) *types.Type {
+	switch indexType.Kind {
+	case types.LiteralType:
+		key := fmt.Sprint(normalizeLiteralValue(indexType.Value))
+		switch objectType.Kind {
+		case types.ObjectType:
+			if propType, exists := objectType.Properties[key]; exists {
+				return propType
+			}
+		case types.TupleType:
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(objectType.Types) {
+				return objectType.Types[i]
+			}
+		}
+	case types.UnionType:
+		// T["a" | "b"] is T["a"] | T["b"]
+		var members []*types.Type
+		for _, member := range indexType.Types {
+			memberType := indexedAccessType(objectType, member)
+			if memberType == nil {
+				return nil
+			}
+			members = append(members, memberType)
+		}
+		return unionOfTypes(members)
+	case types.NumberType:
+		switch objectType.Kind {
+		case types.TupleType:
+			if len(objectType.Types) > 0 {
+				return unionOfTypes(objectType.Types)
+			}
+		case types.ArrayType:
+			return objectType.ElementType
+		case types.ObjectType:
+			return objectType.NumberIndexType
+		}
+	case types.StringType:
+		if objectType.Kind == types.ObjectType {
+			return objectType.StringIndexType
+		}
+	}
+	return nil
+}
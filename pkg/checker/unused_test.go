@@ -0,0 +1,152 @@
+package checker
+
+import "testing"
+
+func TestCheckUnusedDeclarations(t *testing.T) {
+	unused := getDefaultConfig()
+	unused.NoUnusedLocals = true
+	unused.NoUnusedParameters = true
+
+	runDiagnosticCases(t, unused, []diagnosticCase{
+		{
+			name: "imports",
+			code: `import { used, unusedImport } from "./lib";
+import type { Shape } from "./lib";
+export function run(): Shape {
+  return used();
+}
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS6133", message: "'unusedImport' is declared but its value is never read."},
+			},
+		},
+		{
+			name: "types",
+			code: `type Unused = string;
+interface Options { verbose: boolean }
+export const options: Options = { verbose: true };
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS6196", message: "'Unused' is declared but never used."},
+			},
+		},
+		{
+			name: "parameters",
+			code: `interface Options { verbose: boolean }
+export function run(first: number, second: Options, third: number, _fourth: number): number {
+  const lengths = [1, 2].map((value, index) => index);
+  return second.verbose ? lengths.length : 0;
+}
+`,
+			want: []diagnostic{
+				{line: 2, code: "TS6133", message: "'third' is declared but its value is never read."},
+			},
+		},
+		{
+			name: "locals",
+			code: `export function run(): string {
+  let writeOnly = 0;
+  writeOnly++;
+  const label = "run";
+  for (const _index of [1, 2]) {}
+  for (const item of [1, 2]) {}
+  try { run(); } catch (err) {}
+  return ` + "`${label}`" + `;
+}
+`,
+			want: []diagnostic{
+				{line: 2, code: "TS6133", message: "'writeOnly' is declared but its value is never read."},
+				{line: 6, code: "TS6133", message: "'item' is declared but its value is never read."},
+			},
+		},
+		{
+			name: "private members",
+			code: `class Service {
+  private cache = new Map<string, number>();
+  private hits = 0;
+  private helper() { return 1; }
+  private stale() {}
+  constructor(private readonly name: string) {}
+  get size() { return this.cache.size + this.helper(); }
+}
+export const service = new Service("x");
+`,
+			want: []diagnostic{
+				{line: 3, code: "TS6133", message: "'hits' is declared but its value is never read."},
+				{line: 5, code: "TS6133", message: "'stale' is declared but its value is never read."},
+				{line: 6, code: "TS6138", message: "Property 'name' is declared but its value is never read."},
+			},
+		},
+		{
+			name: "parameter properties",
+			code: `class Point {
+  constructor(private x: number, private y: number, private z: number, public w: number, protected v: number) {
+    console.log(z);
+  }
+  get length() { return this.x; }
+}
+export const point = new Point(1, 2, 3, 4, 5);
+`,
+			want: []diagnostic{
+				{line: 2, code: "TS6138", message: "Property 'y' is declared but its value is never read."},
+			},
+		},
+		{
+			name: "functions that only refer to themselves",
+			code: `function countdown(n: number): number {
+  return n > 0 ? countdown(n - 1) : 0;
+}
+function even(n: number): boolean {
+  return n === 0 || odd(n - 1);
+}
+function odd(n: number): boolean {
+  return n !== 0 && even(n - 1);
+}
+class Node {
+  next?: Node;
+  static create() { return new Node(); }
+}
+export const parity = even(3);
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS6133", message: "'countdown' is declared but its value is never read."},
+				{line: 10, code: "TS6196", message: "'Node' is declared but never used."},
+			},
+		},
+		{
+			name: "type parameters",
+			code: `export function first<T, U>(items: T[]): T {
+  return items[0];
+}
+export function ignore<T>(value: number): number {
+  return value;
+}
+export function both<K, V>(key: string): string {
+  return key;
+}
+export interface Box<T, _Tag> { value: T }
+export type Pair<A, B extends A> = [B, B];
+export class Cache<T> {
+  get(key: string) { return key; }
+}
+`,
+			want: []diagnostic{
+				{line: 1, code: "TS6133", message: "'U' is declared but its value is never read."},
+				{line: 4, code: "TS6133", message: "'T' is declared but its value is never read."},
+				{line: 7, code: "TS6205", message: "All type parameters are unused."},
+				{line: 12, code: "TS6133", message: "'T' is declared but its value is never read."},
+			},
+		},
+		{
+			name:   "off by default",
+			config: getDefaultConfig(),
+			code: `import { unusedImport } from "./lib";
+type Unused = string;
+export function run(first: number) {
+  let writeOnly = 0;
+  writeOnly++;
+}
+`,
+		},
+	}, "TS6133", "TS6138", "TS6196", "TS6205")
+}
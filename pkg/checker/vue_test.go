@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"tstypechecker/pkg/parser"
+)
+
+func TestCheckVueScriptSetupMacros(t *testing.T) {
+	code := `<template>
+  <button @click="emit('change', 1)">{{ title }}</button>
+</template>
+
+<script setup lang="ts">
+const props = defineProps<{ title: string; count?: number }>();
+const emit = defineEmits<{ (e: 'change', id: number): void }>();
+const emitSave = defineEmits<{ save: [name: string] }>();
+const model = defineModel<string>({ required: true });
+const withDefault = withDefaults(defineProps<{ size?: number }>(), { size: 1 });
+
+const badTitle: number = props.title;
+emit('change', 1);
+emitSave('save', 2);
+const badModel: number = model.value;
+const size: number = withDefault.size;
+</script>
+`
+	file, err := parser.ParseCode(code, "Button.vue")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+
+	tc := New()
+	errors := tc.CheckFile("Button.vue", file)
+
+	var got []string
+	for _, e := range errors {
+		got = append(got, fmt.Sprintf("%s@%d", e.Code, e.Line))
+	}
+	sort.Strings(got)
+
+	want := []string{"TS2322@12", "TS2322@15", "TS2345@14"}
+	if len(got) != len(want) {
+		t.Fatalf("got diagnostics %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("diagnostic %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCheckVueJavaScriptIsSkipped(t *testing.T) {
+	file, err := parser.ParseCode("<script>\nconst x = 1;\nx();\n</script>\n", "Plain.vue")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	if errors := New().CheckFile("Plain.vue", file); len(errors) != 0 {
+		t.Errorf("expected no diagnostics for a JavaScript component, got %v", errors)
+	}
+}
@@ -0,0 +1,400 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// configDirVariable is replaced by the directory of the config being loaded,
+// so a shared base can name paths inside the project that extends it
+const configDirVariable = "${configDir}"
+
+// pathOptions are the compiler options that hold paths. Relative values are
+// resolved against the directory of the config that sets them.
+var pathOptions = map[string]bool{
+	"baseUrl":        true,
+	"rootDir":        true,
+	"rootDirs":       true,
+	"outDir":         true,
+	"outFile":        true,
+	"declarationDir": true,
+	"typeRoots":      true,
+}
+
+// ExtendsList holds the configs a tsconfig extends, written as a single
+// string or, since TypeScript 5.0, as an array merged in order
+type ExtendsList []string
+
+// UnmarshalJSON accepts both forms of extends
+func (e *ExtendsList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*e = ExtendsList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("extends must be a string or an array of strings")
+	}
+	*e = list
+	return nil
+}
+
+// configLayer is a config file merged with the configs it extends. Compiler
+// options stay raw until the whole chain is merged, so each config only
+// overrides the options it sets.
+type configLayer struct {
+	options map[string]json.RawMessage
+	config  TSConfig
+}
+
+// loadConfigLayer reads the config at configPath and merges it over the
+// configs it extends. chain holds the configs being loaded, to detect
+// circular extends.
+func loadConfigLayer(configPath string, chain []string) (*configLayer, error) {
+	chain = append(chain, configPath)
+	for _, loading := range chain[:len(chain)-1] {
+		if loading == configPath {
+			return nil, fmt.Errorf("error TS18000: Circularity detected while resolving configuration: %s",
+				strings.Join(chain, " -> "))
+		}
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tsconfig.json: %w", err)
+	}
+	data = stripJSONComments(data)
+
+	var own TSConfig
+	var raw struct {
+		CompilerOptions map[string]json.RawMessage `json:"compilerOptions"`
+	}
+	if err := json.Unmarshal(data, &own); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+
+	dir := filepath.Dir(configPath)
+	layer := &configLayer{options: make(map[string]json.RawMessage)}
+	for _, spec := range own.Extends {
+		basePath, err := resolveExtends(spec, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load extended config: %w", err)
+		}
+		base, err := loadConfigLayer(basePath, chain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load extended config: %w", err)
+		}
+		layer.merge(base)
+	}
+
+	// Paths are relative to the config that sets them
+	for name, value := range raw.CompilerOptions {
+		if pathOptions[name] {
+			value = resolvePathOption(value, dir)
+		}
+		layer.options[name] = value
+	}
+	if own.Include != nil {
+		own.includeDir = dir
+	}
+	if own.Exclude != nil {
+		own.excludeDir = dir
+	}
+	if own.Files != nil {
+		own.filesDir = dir
+	}
+	layer.merge(&configLayer{config: own})
+
+	// References are never inherited
+	layer.config.References = own.References
+	layer.config.Extends = own.Extends
+	return layer, nil
+}
+
+// merge applies the options and lists set by other over the layer
+func (l *configLayer) merge(other *configLayer) {
+	for name, value := range other.options {
+		l.options[name] = value
+	}
+	// An empty list still replaces the inherited one
+	if other.config.Include != nil {
+		l.config.Include, l.config.includeDir = other.config.Include, other.config.includeDir
+	}
+	if other.config.Exclude != nil {
+		l.config.Exclude, l.config.excludeDir = other.config.Exclude, other.config.excludeDir
+	}
+	if other.config.Files != nil {
+		l.config.Files, l.config.filesDir = other.config.Files, other.config.filesDir
+	}
+}
+
+// substituteConfigDir replaces ${configDir} in path-valued options, in the
+// targets of paths and in the include, exclude and files lists with dir
+func (l *configLayer) substituteConfigDir(dir string) {
+	for name, value := range l.options {
+		// The targets of paths are relative to baseUrl, so resolvePathOption
+		// leaves them as they are
+		if (pathOptions[name] || name == "paths") && bytes.Contains(value, []byte(configDirVariable)) {
+			encoded, _ := json.Marshal(filepath.ToSlash(dir))
+			value = bytes.ReplaceAll(value, []byte(configDirVariable), encoded[1:len(encoded)-1])
+			l.options[name] = resolvePathOption(value, dir)
+		}
+	}
+	for _, list := range [][]string{l.config.Include, l.config.Exclude, l.config.Files} {
+		for i, path := range list {
+			list[i] = strings.ReplaceAll(path, configDirVariable, filepath.ToSlash(dir))
+		}
+	}
+}
+
+// encodedOptions returns the merged compiler options as a JSON object
+func (l *configLayer) encodedOptions() []byte {
+	data, _ := json.Marshal(l.options)
+	return data
+}
+
+// resolvePathOption resolves the relative paths of a path-valued option
+// against dir. Paths that start with ${configDir} are left to
+// substituteConfigDir.
+func resolvePathOption(value json.RawMessage, dir string) json.RawMessage {
+	resolve := func(path string) string {
+		if path == "" || strings.HasPrefix(path, configDirVariable) {
+			return path
+		}
+		return absPath(path, dir)
+	}
+
+	var single string
+	if err := json.Unmarshal(value, &single); err == nil {
+		data, _ := json.Marshal(resolve(single))
+		return data
+	}
+	var list []string
+	if err := json.Unmarshal(value, &list); err == nil {
+		for i, path := range list {
+			list[i] = resolve(path)
+		}
+		data, _ := json.Marshal(list)
+		return data
+	}
+	return value
+}
+
+// resolveExtends returns the config file an extends entry names: a path
+// relative to dir, or a package (or file inside one) found in node_modules
+func resolveExtends(spec, dir string) (string, error) {
+	if spec == "." || spec == ".." || strings.HasPrefix(spec, "./") || strings.HasPrefix(spec, "../") || filepath.IsAbs(spec) {
+		path := absPath(spec, dir)
+		if isFile(path) {
+			return path, nil
+		}
+		if !strings.HasSuffix(path, ".json") && isFile(path+".json") {
+			return path + ".json", nil
+		}
+		return "", fmt.Errorf("error TS6053: File '%s' not found.", path)
+	}
+
+	for current := dir; ; {
+		candidate := filepath.Join(current, "node_modules", filepath.FromSlash(spec))
+		if path, ok := packageConfigPath(candidate); ok {
+			return path, nil
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	return "", fmt.Errorf("error TS6053: File '%s' not found.", spec)
+}
+
+// packageConfigPath finds the config a module path inside node_modules names:
+// the file itself, the file with .json added, or for a package directory the
+// file in its package.json "tsconfig" field or its tsconfig.json
+func packageConfigPath(path string) (string, bool) {
+	if isFile(path) {
+		return path, true
+	}
+	if !strings.HasSuffix(path, ".json") && isFile(path+".json") {
+		return path + ".json", true
+	}
+
+	if data, err := os.ReadFile(filepath.Join(path, "package.json")); err == nil {
+		var pkg struct {
+			TSConfig string `json:"tsconfig"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && pkg.TSConfig != "" {
+			if configPath := filepath.Join(path, filepath.FromSlash(pkg.TSConfig)); isFile(configPath) {
+				return configPath, true
+			}
+		}
+	}
+	if configPath := filepath.Join(path, "tsconfig.json"); isFile(configPath) {
+		return configPath, true
+	}
+	return "", false
+}
+
+// isFile reports whether path exists and is not a directory
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// stripJSONComments blanks the comments of a JSONC document and drops
+// trailing commas, which tsconfig files allow
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		ch := data[i]
+		switch {
+		case ch == '"':
+			// Copy the string, escapes included
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(data) {
+				i = len(data) - 1
+			}
+			out = append(out, data[start:i+1]...)
+		case ch == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+		case ch == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+		case ch == '}' || ch == ']':
+			// Drop a comma left before the closing bracket
+			trimmed := bytes.TrimRight(out, " \t\r\n")
+			if len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				out = append(trimmed[:len(trimmed)-1], out[len(trimmed):]...)
+			}
+			out = append(out, ch)
+		default:
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
+// ShowConfig returns the effective configuration as JSON: the compiler
+// options set by the config and the configs it extends, its references, and
+// the files, include and exclude lists, with paths relative to the config
+func (c *TSConfig) ShowConfig() ([]byte, error) {
+	files, err := c.FileMatcher().Files()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, c.noInputsError()
+	}
+
+	options := make(map[string]interface{}, len(c.options))
+	for name, raw := range c.options {
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		if pathOptions[name] {
+			switch v := value.(type) {
+			case string:
+				value = c.relativePath(v)
+			case []interface{}:
+				for i, path := range v {
+					if s, ok := path.(string); ok {
+						v[i] = c.relativePath(s)
+					}
+				}
+			}
+		}
+		if targets, ok := value.(map[string]interface{}); ok && name == "paths" {
+			// Targets are relative to baseUrl, except those ${configDir} made absolute
+			for _, list := range targets {
+				list, _ := list.([]interface{})
+				for i, target := range list {
+					if s, ok := target.(string); ok && filepath.IsAbs(s) {
+						list[i] = c.relativePath(s)
+					}
+				}
+			}
+		}
+		options[name] = value
+	}
+
+	relativeList := func(list []string, dir string) []string {
+		if list == nil {
+			return nil
+		}
+		result := make([]string, len(list))
+		for i, path := range list {
+			result[i] = c.relativePath(absPath(path, c.listDir(dir)))
+		}
+		return result
+	}
+
+	shown := struct {
+		CompilerOptions map[string]interface{} `json:"compilerOptions"`
+		References      []ProjectReference     `json:"references,omitempty"`
+		Files           []string               `json:"files"`
+		Include         []string               `json:"include,omitempty"`
+		Exclude         []string               `json:"exclude,omitempty"`
+	}{
+		CompilerOptions: options,
+		References:      c.References,
+		Files:           relativeList(files, ""),
+		Include:         relativeList(c.Include, c.includeDir),
+		Exclude:         relativeList(c.Exclude, c.excludeDir),
+	}
+	return json.MarshalIndent(shown, "", "    ")
+}
+
+// noInputsError is the error tsc reports when include, exclude and files
+// select no file
+func (c *TSConfig) noInputsError() error {
+	include := c.Include
+	if include == nil {
+		include = []string{"**/*"}
+	}
+	exclude := c.Exclude
+	if exclude == nil {
+		exclude = []string{}
+	}
+	configPath := c.configPath
+	if configPath == "" {
+		configPath = filepath.Join(c.listDir(""), "tsconfig.json")
+	}
+	includeJSON, _ := json.Marshal(include)
+	excludeJSON, _ := json.Marshal(exclude)
+	return fmt.Errorf("error TS18003: No inputs were found in config file '%s'. Specified 'include' paths were '%s' and 'exclude' paths were '%s'.",
+		configPath, includeJSON, excludeJSON)
+}
+
+// relativePath writes path relative to the config's directory, the way
+// tsconfig files spell them
+func (c *TSConfig) relativePath(path string) string {
+	rel, err := filepath.Rel(c.listDir(""), path)
+	if err != nil || filepath.IsAbs(rel) {
+		return filepath.ToSlash(path)
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return rel
+	}
+	return "./" + rel
+}
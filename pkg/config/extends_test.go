@@ -0,0 +1,201 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadTSConfigExtendsPackagesAndArrays(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"node_modules/@tsconfig/node20/tsconfig.json": `{
+			// Shared configs are JSONC
+			"compilerOptions": { "target": "es2022", "strict": true, "noImplicitReturns": true, },
+		}`,
+		"node_modules/@acme/tsconfig/package.json": `{"name": "@acme/tsconfig", "tsconfig": "base.json"}`,
+		"node_modules/@acme/tsconfig/base.json": `{
+			"compilerOptions": { "strict": false, "outDir": "${configDir}/dist", "rootDir": "src" },
+			"include": ["${configDir}/src"]
+		}`,
+		"app/tsconfig.json": `{
+			"extends": ["@tsconfig/node20/tsconfig.json", "@acme/tsconfig"],
+			"compilerOptions": { "noImplicitReturns": false }
+		}`,
+		"app/src/main.ts":       "",
+		"app/test/main.test.ts": "",
+	})
+
+	c, err := LoadTSConfig(filepath.Join(dir, "app"))
+	if err != nil {
+		t.Fatalf("LoadTSConfig failed: %v", err)
+	}
+	options := c.CompilerOptions
+	if options.Target != "es2022" || options.Strict || options.NoImplicitReturns {
+		t.Errorf("options were not merged in order: target %q, strict %v, noImplicitReturns %v",
+			options.Target, options.Strict, options.NoImplicitReturns)
+	}
+	if want := filepath.Join(dir, "app", "dist"); options.OutDir != want {
+		t.Errorf("outDir = %s, want ${configDir} replaced with %s", options.OutDir, want)
+	}
+	if want := filepath.Join(dir, "node_modules", "@acme", "tsconfig", "src"); options.RootDir != want {
+		t.Errorf("rootDir = %s, want it relative to the base config: %s", options.RootDir, want)
+	}
+	if got := relativeFiles(t, c.FileMatcher(), dir); !reflect.DeepEqual(got, []string{"app/src/main.ts"}) {
+		t.Errorf("files = %v, want [app/src/main.ts]", got)
+	}
+
+	data, err := c.ShowConfig()
+	if err != nil {
+		t.Fatalf("ShowConfig failed: %v", err)
+	}
+	var shown struct {
+		CompilerOptions map[string]interface{} `json:"compilerOptions"`
+		Files           []string               `json:"files"`
+		Include         []string               `json:"include"`
+	}
+	if err := json.Unmarshal(data, &shown); err != nil {
+		t.Fatalf("ShowConfig output is not JSON: %v\n%s", err, data)
+	}
+	if shown.CompilerOptions["outDir"] != "./dist" || shown.CompilerOptions["target"] != "es2022" {
+		t.Errorf("shown options = %v", shown.CompilerOptions)
+	}
+	if !reflect.DeepEqual(shown.Files, []string{"./src/main.ts"}) || !reflect.DeepEqual(shown.Include, []string{"./src"}) {
+		t.Errorf("shown files = %v, include = %v", shown.Files, shown.Include)
+	}
+}
+
+func TestLoadTSConfigExtendsConfigDirInPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"node_modules/@acme/tsconfig/tsconfig.json": `{
+			"compilerOptions": { "baseUrl": ".", "paths": { "@/*": ["${configDir}/src/*"], "lib": ["vendor/lib"] } }
+		}`,
+		"app/tsconfig.json": `{"extends": "@acme/tsconfig"}`,
+	})
+
+	c, err := LoadTSConfig(filepath.Join(dir, "app"))
+	if err != nil {
+		t.Fatalf("LoadTSConfig failed: %v", err)
+	}
+	appDir := filepath.Join(dir, "app")
+	if got, want := c.CompilerOptions.Paths["@/*"], []string{filepath.ToSlash(appDir) + "/src/*"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("paths @/* = %v, want ${configDir} replaced: %v", got, want)
+	}
+	if got, want := c.ResolvePathAlias("@/util"), []string{filepath.Join(appDir, "src", "util")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolvePathAlias(@/util) = %v, want %v", got, want)
+	}
+	// Targets without ${configDir} stay relative to baseUrl
+	baseDir := filepath.Join(dir, "node_modules", "@acme", "tsconfig")
+	if got, want := c.ResolvePathAlias("lib"), []string{filepath.Join(baseDir, "vendor", "lib")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolvePathAlias(lib) = %v, want %v", got, want)
+	}
+}
+
+func TestShowConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"node_modules/@acme/tsconfig/tsconfig.json": `{
+			"compilerOptions": { "baseUrl": ".", "outDir": "${configDir}/dist", "paths": { "@/*": ["${configDir}/src/*"], "lib": ["vendor/lib"] } },
+			"include": ["${configDir}/src"]
+		}`,
+		"app/tsconfig.json":   `{"extends": "@acme/tsconfig"}`,
+		"app/src/main.ts":     "",
+		"empty/tsconfig.json": `{"include": ["src"]}`,
+	})
+
+	c, err := LoadTSConfig(filepath.Join(dir, "app"))
+	if err != nil {
+		t.Fatalf("LoadTSConfig failed: %v", err)
+	}
+	data, err := c.ShowConfig()
+	if err != nil {
+		t.Fatalf("ShowConfig failed: %v", err)
+	}
+	var shown struct {
+		CompilerOptions struct {
+			BaseUrl string              `json:"baseUrl"`
+			OutDir  string              `json:"outDir"`
+			Paths   map[string][]string `json:"paths"`
+		} `json:"compilerOptions"`
+		Files   []string `json:"files"`
+		Include []string `json:"include"`
+	}
+	if err := json.Unmarshal(data, &shown); err != nil {
+		t.Fatalf("ShowConfig output is not JSON: %v\n%s", err, data)
+	}
+	// Every path is relative to the config's directory, as tsc writes them
+	options := shown.CompilerOptions
+	if options.BaseUrl != "../node_modules/@acme/tsconfig" || options.OutDir != "./dist" {
+		t.Errorf("baseUrl = %s, outDir = %s", options.BaseUrl, options.OutDir)
+	}
+	want := map[string][]string{"@/*": {"./src/*"}, "lib": {"vendor/lib"}}
+	if !reflect.DeepEqual(options.Paths, want) {
+		t.Errorf("paths = %v, want %v", options.Paths, want)
+	}
+	if !reflect.DeepEqual(shown.Files, []string{"./src/main.ts"}) || !reflect.DeepEqual(shown.Include, []string{"./src"}) {
+		t.Errorf("files = %v, include = %v", shown.Files, shown.Include)
+	}
+
+	// No file matches include
+	c, err = LoadTSConfig(filepath.Join(dir, "empty"))
+	if err != nil {
+		t.Fatalf("LoadTSConfig failed: %v", err)
+	}
+	configPath := filepath.Join(dir, "empty", "tsconfig.json")
+	wantErr := "error TS18003: No inputs were found in config file '" + configPath + `'. Specified 'include' paths were '["src"]' and 'exclude' paths were '[]'.`
+	if data, err := c.ShowConfig(); err == nil || err.Error() != wantErr {
+		t.Errorf("ShowConfig() = %s, %v, want error %s", data, err, wantErr)
+	}
+}
+
+func TestLoadTSConfigExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "missing package",
+			files: map[string]string{"tsconfig.json": `{"extends": "@tsconfig/missing"}`},
+			want:  "TS6053",
+		},
+		{
+			name: "circular",
+			files: map[string]string{
+				"tsconfig.json": `{"extends": "./a"}`,
+				"a.json":        `{"extends": "./tsconfig.json"}`,
+			},
+			want: "TS18000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+			_, err := LoadTSConfig(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected %s error, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestStripJSONComments(t *testing.T) {
+	input := `{
+		// line comment
+		"a": "// not a comment", /* block */
+		"b": ["x", "y",],
+		"c": "quote \" and /* kept */",
+	}`
+	var got map[string]interface{}
+	if err := json.Unmarshal(stripJSONComments([]byte(input)), &got); err != nil {
+		t.Fatalf("stripped JSON does not parse: %v", err)
+	}
+	if got["a"] != "// not a comment" || got["c"] != `quote " and /* kept */` || len(got["b"].([]interface{})) != 2 {
+		t.Errorf("unexpected result: %v", got)
+	}
+}
@@ -0,0 +1,417 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Project file discovery follows tsc's include/exclude/files semantics:
+//
+//   - "*" matches any characters in one path segment and "?" exactly one
+//   - "**/" matches any number of directories
+//   - an include whose last segment has no wildcard or extension is a
+//     directory and matches everything below it (src -> src/**/*)
+//   - wildcards in include patterns skip dot files and dot directories, and
+//     "**" does not descend into package folders unless the pattern names them
+//   - an exclude matches a file or any of its parent directories
+//   - wildcards only match files with a supported extension; files listed in
+//     "files" are always part of the project, even when excluded
+
+// defaultExcludes are excluded when the config has no exclude list
+var defaultExcludes = []string{"node_modules", "bower_components", "jspm_packages"}
+
+// packageFolders are never entered by "**" in an include pattern
+var packageFolders = map[string]bool{
+	"node_modules":     true,
+	"bower_components": true,
+	"jspm_packages":    true,
+}
+
+// extensionFamilies lists the extensions of files that shadow each other, by
+// priority: when a directory has both a.ts and a.d.ts (or a.js), only a.ts is
+// part of the project
+var extensionFamilies = [][]string{
+	{".ts", ".tsx", ".d.ts", ".js", ".jsx"},
+	{".mts", ".d.mts", ".mjs"},
+	{".cts", ".d.cts", ".cjs"},
+	{".vue"},
+}
+
+// javaScriptExtensions are only matched with allowJs
+var javaScriptExtensions = map[string]bool{".js": true, ".jsx": true, ".mjs": true, ".cjs": true}
+
+// SupportedExtensions returns the extensions include wildcards match
+func (c *CompilerOptions) SupportedExtensions() []string {
+	var extensions []string
+	for _, family := range extensionFamilies {
+		for _, ext := range family {
+			if !javaScriptExtensions[ext] || c.AllowJs {
+				extensions = append(extensions, ext)
+			}
+		}
+	}
+	return extensions
+}
+
+// FileMatcher selects the source files of a project
+type FileMatcher struct {
+	files      []string
+	includes   []globPattern
+	excludes   []globPattern
+	extensions []string
+}
+
+// globPattern is an absolute pattern split into slash separated segments
+type globPattern []string
+
+// FileMatcher returns the matcher for the files the config's include,
+// exclude and files lists select
+func (c *TSConfig) FileMatcher() *FileMatcher {
+	includes := c.Include
+	if includes == nil && c.Files == nil {
+		includes = []string{"**/*"}
+	}
+
+	m := c.newFileMatcher()
+	for _, spec := range includes {
+		m.includes = append(m.includes, newGlobPattern(spec, c.listDir(c.includeDir), true))
+	}
+	for _, file := range c.Files {
+		m.files = append(m.files, absPath(file, c.listDir(c.filesDir)))
+	}
+	return m
+}
+
+// DirectoryFileMatcher returns the matcher for every source file under dir
+// that the config does not exclude. It is used when a directory other than
+// the project root is checked, which replaces the include and files lists.
+func (c *TSConfig) DirectoryFileMatcher(dir string) *FileMatcher {
+	m := c.newFileMatcher()
+	everything := append(pathSegments(absPath(dir, c.listDir(""))), "**", "*")
+	m.includes = []globPattern{globPattern(everything)}
+	return m
+}
+
+func (c *TSConfig) newFileMatcher() *FileMatcher {
+	return &FileMatcher{
+		excludes:   c.excludePatterns(),
+		extensions: c.CompilerOptions.SupportedExtensions(),
+	}
+}
+
+// excludePatterns returns the exclude list, or the package folders and output
+// directories when the config does not have one
+func (c *TSConfig) excludePatterns() []globPattern {
+	specs, dir := c.Exclude, c.listDir(c.excludeDir)
+	if specs == nil {
+		specs = append([]string{}, defaultExcludes...)
+		for _, out := range []string{c.CompilerOptions.OutDir, c.CompilerOptions.DeclarationDir} {
+			if out != "" {
+				specs = append(specs, out)
+			}
+		}
+		dir = c.listDir("")
+	}
+
+	patterns := make([]globPattern, 0, len(specs))
+	for _, spec := range specs {
+		patterns = append(patterns, newGlobPattern(spec, dir, false))
+	}
+	return patterns
+}
+
+// listDir returns the directory a path list is relative to: the config that
+// declared it, or this config's directory for its own and default lists
+func (c *TSConfig) listDir(dir string) string {
+	if dir != "" {
+		return dir
+	}
+	if c.ConfigDir != "" {
+		return c.ConfigDir
+	}
+	cwd, _ := os.Getwd()
+	return cwd
+}
+
+// absPath resolves path against dir
+func absPath(path, dir string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// newGlobPattern resolves spec against dir. Include patterns that name a
+// directory are expanded to everything below it.
+func newGlobPattern(spec, dir string, include bool) globPattern {
+	segments := strings.Split(filepath.ToSlash(absPath(spec, dir)), "/")
+	if include {
+		last := segments[len(segments)-1]
+		if last == "**" {
+			segments = append(segments, "*")
+		} else if !strings.ContainsAny(last, ".*?") {
+			segments = append(segments, "**", "*")
+		}
+	}
+	return globPattern(segments)
+}
+
+// Files returns the files of the project: the files list first, then the
+// files matched by the include patterns in directory order
+func (m *FileMatcher) Files() ([]string, error) {
+	var result []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			result = append(result, path)
+		}
+	}
+
+	for _, file := range m.files {
+		if info, err := os.Stat(file); err != nil || info.IsDir() {
+			return nil, fmt.Errorf("file '%s' not found", file)
+		}
+		add(file)
+	}
+
+	visited := make(map[string]bool)
+	for _, include := range m.includes {
+		base := include.base()
+		if info, err := os.Stat(base); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := m.walk(base, visited, add); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// walk adds the matching files of dir, before those of its subdirectories
+func (m *FileMatcher) walk(dir string, visited map[string]bool, add func(string)) error {
+	// Symlinked directories are followed, once
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil
+	}
+	if visited[real] {
+		return nil
+	}
+	visited[real] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var matched, subdirs []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			isDir = info.IsDir()
+		}
+
+		segments := pathSegments(path)
+		if isDir {
+			if !m.excluded(segments) && m.mayContainMatches(segments) {
+				subdirs = append(subdirs, path)
+			}
+		} else if m.matchSegments(segments) {
+			matched = append(matched, path)
+		}
+	}
+
+	for _, path := range matched {
+		if !shadowed(path, matched) {
+			add(path)
+		}
+	}
+	for _, subdir := range subdirs {
+		if err := m.walk(subdir, visited, add); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match reports whether path is one of the project's files
+func (m *FileMatcher) Match(path string) bool {
+	path, _ = filepath.Abs(path)
+	for _, file := range m.files {
+		if file == path {
+			return true
+		}
+	}
+	return m.matchSegments(pathSegments(path))
+}
+
+func (m *FileMatcher) matchSegments(segments []string) bool {
+	if m.extension(segments[len(segments)-1]) == "" || m.excluded(segments) {
+		return false
+	}
+	for _, include := range m.includes {
+		if matchGlob(include, segments, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether an exclude pattern matches the path or one of
+// its parent directories
+func (m *FileMatcher) excluded(segments []string) bool {
+	for _, exclude := range m.excludes {
+		for n := len(segments); n > 0; n-- {
+			if matchGlob(exclude, segments[:n], false) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mayContainMatches reports whether an include pattern can match files below
+// the directory, so the walk can skip the ones that cannot
+func (m *FileMatcher) mayContainMatches(dir []string) bool {
+	for _, include := range m.includes {
+		if matchGlobPrefix(include, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// extension returns the supported extension of name, or "" when it has none
+func (m *FileMatcher) extension(name string) string {
+	ext := ""
+	for _, candidate := range m.extensions {
+		// The longest suffix wins: a.d.ts is a .d.ts file, not a .ts file
+		if strings.HasSuffix(name, candidate) && len(candidate) > len(ext) {
+			ext = candidate
+		}
+	}
+	return ext
+}
+
+// shadowed reports whether another file in the same directory has the same
+// name and a higher priority extension
+func shadowed(path string, siblings []string) bool {
+	for _, family := range extensionFamilies {
+		for i, ext := range family {
+			if !strings.HasSuffix(path, ext) || (ext == ".ts" && strings.HasSuffix(path, ".d.ts")) {
+				continue
+			}
+			stem := strings.TrimSuffix(path, ext)
+			for _, better := range family[:i] {
+				for _, sibling := range siblings {
+					if sibling == stem+better {
+						return true
+					}
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// base returns the directory before the first wildcard segment, where the
+// walk for the pattern starts
+func (g globPattern) base() string {
+	n := 0
+	for n < len(g)-1 && !strings.ContainsAny(g[n], "*?") {
+		n++
+	}
+	base := strings.Join(g[:n], "/")
+	if base == "" {
+		base = "/"
+	}
+	return filepath.FromSlash(base)
+}
+
+// pathSegments splits an absolute path into slash separated segments
+func pathSegments(path string) []string {
+	return strings.Split(filepath.ToSlash(path), "/")
+}
+
+// matchGlob matches a whole path against the pattern. "**" only stands for
+// directories, never for the file name itself.
+func matchGlob(pattern globPattern, path []string, include bool) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		if matchGlob(pattern[1:], path, include) {
+			return true
+		}
+		return len(path) > 1 && recursiveDirectory(path[0], include) && matchGlob(pattern, path[1:], include)
+	}
+	return len(path) > 0 && matchSegment(pattern[0], path[0], include) && matchGlob(pattern[1:], path[1:], include)
+}
+
+// matchGlobPrefix reports whether the directory path matches the beginning of
+// an include pattern, leaving segments to match its contents
+func matchGlobPrefix(pattern globPattern, dir []string) bool {
+	if len(dir) == 0 {
+		return len(pattern) > 0
+	}
+	if len(pattern) == 0 {
+		return false
+	}
+	if pattern[0] == "**" {
+		return matchGlobPrefix(pattern[1:], dir) || (recursiveDirectory(dir[0], true) && matchGlobPrefix(pattern, dir[1:]))
+	}
+	return matchSegment(pattern[0], dir[0], true) && matchGlobPrefix(pattern[1:], dir[1:])
+}
+
+// recursiveDirectory reports whether "**" may match the directory name
+func recursiveDirectory(name string, include bool) bool {
+	if !include {
+		return true
+	}
+	return !strings.HasPrefix(name, ".") && !packageFolders[name]
+}
+
+// matchSegment matches one path segment against a pattern segment. In
+// include patterns a leading wildcard does not match a leading dot.
+func matchSegment(pattern, name string, include bool) bool {
+	if include && strings.HasPrefix(name, ".") && !strings.HasPrefix(pattern, ".") {
+		return pattern == name
+	}
+	return matchWildcards(pattern, name)
+}
+
+// matchWildcards matches name against a pattern of literal characters, "*"
+// and "?". Brackets and braces have no special meaning.
+func matchWildcards(pattern, name string) bool {
+	p, n := []rune(pattern), []rune(name)
+	star, resume := -1, 0
+	i, j := 0, 0
+	for j < len(n) {
+		switch {
+		case i < len(p) && (p[i] == '?' || p[i] == n[j]):
+			i++
+			j++
+		case i < len(p) && p[i] == '*':
+			star, resume = i, j
+			i++
+		case star >= 0:
+			resume++
+			i, j = star+1, resume
+		default:
+			return false
+		}
+	}
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+	return i == len(p)
+}
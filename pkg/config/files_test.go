@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTree creates files (slash separated, relative to dir) with contents
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// relativeFiles lists the project files relative to dir
+func relativeFiles(t *testing.T, m *FileMatcher, dir string) []string {
+	t.Helper()
+	files, err := m.Files()
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	rel := make([]string, 0, len(files))
+	for _, file := range files {
+		r, _ := filepath.Rel(dir, file)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	return rel
+}
+
+func TestMatchWildcards(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*", "a.ts", true},
+		{"*.ts", "a.ts", true},
+		{"*.ts", "a.tsx", false},
+		{"?.ts", "a.ts", true},
+		{"?.ts", "ab.ts", false},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"{a,b}.ts", "a.ts", false},
+		{"[ab].ts", "[ab].ts", true},
+	}
+	for _, tt := range tests {
+		if got := matchWildcards(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchWildcards(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFileMatcherIncludeExclude(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"src/index.ts":             "",
+		"src/util/a.ts":            "",
+		"src/util/a.d.ts":          "",
+		"src/util/b.js":            "",
+		"src/util/a.test.ts":       "",
+		"src/.hidden/c.ts":         "",
+		"src/node_modules/x/d.ts":  "",
+		"src/generated/e.ts":       "",
+		"src/view.tsx":             "",
+		"src/readme.md":            "",
+		"scripts/build.ts":         "",
+		"types/global.d.ts":        "",
+		"other/explicit.ts":        "",
+		"src/generated/keep.ts":    "",
+		"src/util/deep/er/f.ts":    "",
+		"src/util/deep/er/f.d.mts": "",
+	})
+
+	c := &TSConfig{
+		ConfigDir: dir,
+		Include:   []string{"src", "types/**/*.d.ts"},
+		Exclude:   []string{"**/*.test.ts", "src/generated"},
+		Files:     []string{"src/generated/keep.ts"},
+	}
+	got := relativeFiles(t, c.FileMatcher(), dir)
+	want := []string{
+		"src/generated/keep.ts",
+		"src/index.ts",
+		"src/view.tsx",
+		"src/util/a.ts",
+		"src/util/deep/er/f.d.mts",
+		"src/util/deep/er/f.ts",
+		"types/global.d.ts",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+
+	m := c.FileMatcher()
+	for name, want := range map[string]bool{
+		"src/util/a.ts":         true,
+		"src/util/a.test.ts":    false,
+		"src/generated/e.ts":    false,
+		"src/generated/keep.ts": true,
+		"src/.hidden/c.ts":      false,
+		"scripts/build.ts":      false,
+	} {
+		if got := m.Match(filepath.Join(dir, filepath.FromSlash(name))); got != want {
+			t.Errorf("Match(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFileMatcherDefaults(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"a.ts":                "",
+		"b.js":                "",
+		"dist/a.js":           "",
+		"dist/a.d.ts":         "",
+		"node_modules/m/i.ts": "",
+		"lib/c.jsx":           "",
+	})
+
+	c := &TSConfig{ConfigDir: dir, CompilerOptions: CompilerOptions{AllowJs: true, OutDir: "./dist"}}
+	got := relativeFiles(t, c.FileMatcher(), dir)
+	want := []string{"a.ts", "b.js", "lib/c.jsx"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+
+	// A files list without include only checks the listed files
+	c = &TSConfig{ConfigDir: dir, Files: []string{"a.ts"}}
+	if got := relativeFiles(t, c.FileMatcher(), dir); !reflect.DeepEqual(got, []string{"a.ts"}) {
+		t.Errorf("files = %v, want [a.ts]", got)
+	}
+
+	c = &TSConfig{ConfigDir: dir, Files: []string{"missing.ts"}}
+	if _, err := c.FileMatcher().Files(); err == nil {
+		t.Error("expected an error for a missing file in the files list")
+	}
+}
+
+func TestFileMatcherExtendsResolvesListsAgainstDeclaringConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"base/tsconfig.json":     `{"include": ["src"]}`,
+		"base/src/shared.ts":     "",
+		"app/tsconfig.json":      `{"extends": "../base/tsconfig.json", "exclude": ["src/skip.ts"]}`,
+		"app/src/main.ts":        "",
+		"app/src/skip.ts":        "",
+		"app/other/unrelated.ts": "",
+	})
+
+	c, err := LoadTSConfig(filepath.Join(dir, "app"))
+	if err != nil {
+		t.Fatalf("LoadTSConfig failed: %v", err)
+	}
+	if got := relativeFiles(t, c.FileMatcher(), dir); !reflect.DeepEqual(got, []string{"base/src/shared.ts"}) {
+		t.Errorf("files = %v, want [base/src/shared.ts]", got)
+	}
+
+	// Checking a directory replaces include but keeps the excludes
+	got := relativeFiles(t, c.DirectoryFileMatcher(filepath.Join(dir, "app")), dir)
+	want := []string{"app/other/unrelated.ts", "app/src/main.ts"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("directory files = %v, want %v", got, want)
+	}
+}
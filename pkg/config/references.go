@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectReference is an entry of a tsconfig's references list
+type ProjectReference struct {
+	// Path is the referenced tsconfig file, or the directory containing its
+	// tsconfig.json, relative to the referencing config
+	Path    string `json:"path"`
+	Prepend bool   `json:"prepend"`
+}
+
+// Project is a loaded tsconfig and the projects it references
+type Project struct {
+	ConfigPath string
+	Config     *TSConfig
+	References []*Project
+}
+
+// SourceDir returns the directory the project's outputs mirror: rootDir, or
+// the directory of its tsconfig
+func (p *Project) SourceDir() string {
+	if p.Config.CompilerOptions.RootDir != "" {
+		return p.Config.CompilerOptions.RootDir
+	}
+	return p.Config.ConfigDir
+}
+
+// OutputDirs returns the directories the project emits JavaScript and
+// declarations to, when they are not written next to the sources
+func (p *Project) OutputDirs() []string {
+	var dirs []string
+	for _, dir := range []string{p.Config.CompilerOptions.OutDir, p.Config.CompilerOptions.DeclarationDir} {
+		if dir != "" && dir != p.SourceDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// Dependencies returns every project p references, directly or not, in
+// build order
+func (p *Project) Dependencies() []*Project {
+	var result []*Project
+	seen := make(map[*Project]bool)
+	var visit func(*Project)
+	visit = func(project *Project) {
+		for _, ref := range project.References {
+			if !seen[ref] {
+				seen[ref] = true
+				visit(ref)
+				result = append(result, ref)
+			}
+		}
+	}
+	visit(p)
+	return result
+}
+
+// LoadProjectGraph loads the project at path (a tsconfig file or the
+// directory containing tsconfig.json) and every project it references,
+// directly or not. Projects are returned in build order: each one after the
+// projects it references, with the project at path last.
+func LoadProjectGraph(path string) ([]*Project, error) {
+	g := &projectGraph{projects: make(map[string]*Project), loading: make(map[string]bool)}
+	if _, err := g.load(configFilePath(path), nil); err != nil {
+		return nil, err
+	}
+	return g.order, nil
+}
+
+// projectGraph loads projects depth first, so a project is appended to
+// order once all of its references are
+type projectGraph struct {
+	projects map[string]*Project
+	loading  map[string]bool
+	order    []*Project
+}
+
+func (g *projectGraph) load(configPath string, stack []string) (*Project, error) {
+	if project, ok := g.projects[configPath]; ok {
+		return project, nil
+	}
+	stack = append(stack, configPath)
+	if g.loading[configPath] {
+		return nil, fmt.Errorf("error TS6202: Project references may not form a circular graph. Cycle detected: %s",
+			strings.Join(stack, "\n"))
+	}
+	g.loading[configPath] = true
+
+	if info, err := os.Stat(configPath); err != nil || info.IsDir() {
+		return nil, fmt.Errorf("error TS6053: File '%s' not found.", configPath)
+	}
+	config, err := LoadTSConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	project := &Project{ConfigPath: configPath, Config: config}
+	for _, ref := range config.References {
+		refPath := configFilePath(absPath(ref.Path, config.ConfigDir))
+		referenced, err := g.load(refPath, stack)
+		if err != nil {
+			return nil, err
+		}
+		if !referenced.Config.CompilerOptions.Composite {
+			return nil, fmt.Errorf("%s: error TS6306: Referenced project '%s' must have setting \"composite\": true.",
+				configPath, refPath)
+		}
+		project.References = append(project.References, referenced)
+	}
+
+	g.projects[configPath] = project
+	g.order = append(g.order, project)
+	return project, nil
+}
+
+// configFilePath returns path itself when it names a file and the
+// tsconfig.json inside it when it is a directory
+func configFilePath(path string) string {
+	path, _ = filepath.Abs(path)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, "tsconfig.json")
+	}
+	return path
+}
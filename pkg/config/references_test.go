@@ -0,0 +1,98 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProjectGraphBuildOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"tsconfig.base.json":                `{"compilerOptions": {"composite": true, "outDir": "dist"}}`,
+		"tsconfig.json":                     `{"files": [], "references": [{"path": "packages/app"}, {"path": "packages/util"}]}`,
+		"packages/app/tsconfig.json":        `{"extends": "../../tsconfig.base.json", "references": [{"path": "../core"}, {"path": "../util/tsconfig.build.json"}]}`,
+		"packages/core/tsconfig.json":       `{"extends": "../../tsconfig.base.json", "compilerOptions": {"rootDir": "src"}, "references": [{"path": "../util/tsconfig.build.json"}]}`,
+		"packages/util/tsconfig.json":       `{"compilerOptions": {"composite": true}}`,
+		"packages/util/tsconfig.build.json": `{"compilerOptions": {"composite": true, "declarationDir": "types"}}`,
+	})
+
+	projects, err := LoadProjectGraph(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectGraph failed: %v", err)
+	}
+
+	var order []string
+	for _, p := range projects {
+		rel, _ := filepath.Rel(dir, p.ConfigPath)
+		order = append(order, filepath.ToSlash(rel))
+	}
+	want := "packages/util/tsconfig.build.json packages/core/tsconfig.json packages/app/tsconfig.json packages/util/tsconfig.json tsconfig.json"
+	if got := strings.Join(order, " "); got != want {
+		t.Errorf("build order = %s, want %s", got, want)
+	}
+
+	app := projects[2]
+	if deps := app.Dependencies(); len(deps) != 2 || deps[0] != projects[0] || deps[1] != projects[1] {
+		t.Errorf("app dependencies = %v, want util build and core", deps)
+	}
+
+	// Output directories are resolved against the config that declares them
+	core := projects[1]
+	if got, want := core.OutputDirs(), filepath.Join(dir, "dist"); len(got) != 1 || got[0] != want {
+		t.Errorf("core outputs = %v, want [%s]", got, want)
+	}
+	if got, want := core.SourceDir(), filepath.Join(dir, "packages", "core", "src"); got != want {
+		t.Errorf("core sources = %s, want %s", got, want)
+	}
+	if len(projects[4].References) != 2 || projects[4].Config.References == nil {
+		t.Errorf("root project should keep its own references")
+	}
+	if core.Config.References[0].Path != "../util/tsconfig.build.json" || app.Config.References[0].Path != "../core" {
+		t.Errorf("references should not be inherited through extends")
+	}
+}
+
+func TestLoadProjectGraphErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		code  string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"tsconfig.json":   `{"references": [{"path": "a"}]}`,
+				"a/tsconfig.json": `{"compilerOptions": {"composite": true}, "references": [{"path": "../b"}]}`,
+				"b/tsconfig.json": `{"compilerOptions": {"composite": true}, "references": [{"path": "../a"}]}`,
+			},
+			code: "TS6202",
+		},
+		{
+			name: "missing",
+			files: map[string]string{
+				"tsconfig.json": `{"references": [{"path": "missing"}]}`,
+			},
+			code: "TS6053",
+		},
+		{
+			name: "not composite",
+			files: map[string]string{
+				"tsconfig.json":   `{"references": [{"path": "a"}]}`,
+				"a/tsconfig.json": `{}`,
+			},
+			code: "TS6306",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+			_, err := LoadProjectGraph(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.code) {
+				t.Errorf("expected %s error, got %v", tt.code, err)
+			}
+		})
+	}
+}
@@ -5,15 +5,31 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // TSConfig represents a TypeScript configuration file
 type TSConfig struct {
-	CompilerOptions CompilerOptions `json:"compilerOptions"`
-	Include         []string        `json:"include"`
-	Exclude         []string        `json:"exclude"`
-	Files           []string        `json:"files"`
-	Extends         string          `json:"extends"`
+	CompilerOptions CompilerOptions    `json:"compilerOptions"`
+	Include         []string           `json:"include"`
+	Exclude         []string           `json:"exclude"`
+	Files           []string           `json:"files"`
+	Extends         ExtendsList        `json:"extends"`
+	References      []ProjectReference `json:"references"`
+
+	// ConfigDir is the directory of the tsconfig.json; relative paths in it
+	// are resolved against this directory
+	ConfigDir string `json:"-"`
+
+	// Directories of the configs that declared include, exclude and files,
+	// which differ from ConfigDir when a list is inherited through extends
+	includeDir, excludeDir, filesDir string
+
+	// The compiler options as set by the config and the configs it extends
+	options map[string]json.RawMessage
+
+	// Path of the tsconfig.json the config was loaded from, if any
+	configPath string
 }
 
 // CompilerOptions represents the compiler options in tsconfig.json
@@ -25,6 +41,7 @@ type CompilerOptions struct {
 	TypeRoots        []string            `json:"typeRoots"`
 	Types            []string            `json:"types"`
 	ModuleResolution string              `json:"moduleResolution"`
+	CustomConditions []string            `json:"customConditions"`
 
 	// Type checking - Strict mode flags
 	Strict                       bool `json:"strict"`
@@ -37,16 +54,16 @@ type CompilerOptions struct {
 	AlwaysStrict                 bool `json:"alwaysStrict"`
 
 	// Additional type checking
-	NoUnusedLocals                     bool `json:"noUnusedLocals"`
-	NoUnusedParameters                 bool `json:"noUnusedParameters"`
-	NoImplicitReturns                  bool `json:"noImplicitReturns"`
-	NoFallthroughCasesInSwitch         bool `json:"noFallthroughCasesInSwitch"`
-	NoUncheckedIndexedAccess           bool `json:"noUncheckedIndexedAccess"`
-	NoImplicitOverride                 bool `json:"noImplicitOverride"`
-	NoPropertyAccessFromIndexSignature bool `json:"noPropertyAccessFromIndexSignature"`
-	AllowUnusedLabels                  bool `json:"allowUnusedLabels"`
-	AllowUnreachableCode               bool `json:"allowUnreachableCode"`
-	ExactOptionalPropertyTypes         bool `json:"exactOptionalPropertyTypes"`
+	NoUnusedLocals                     bool  `json:"noUnusedLocals"`
+	NoUnusedParameters                 bool  `json:"noUnusedParameters"`
+	NoImplicitReturns                  bool  `json:"noImplicitReturns"`
+	NoFallthroughCasesInSwitch         bool  `json:"noFallthroughCasesInSwitch"`
+	NoUncheckedIndexedAccess           bool  `json:"noUncheckedIndexedAccess"`
+	NoImplicitOverride                 bool  `json:"noImplicitOverride"`
+	NoPropertyAccessFromIndexSignature bool  `json:"noPropertyAccessFromIndexSignature"`
+	AllowUnusedLabels                  bool  `json:"allowUnusedLabels"`
+	AllowUnreachableCode               *bool `json:"allowUnreachableCode,omitempty"` // nil reports unreachable code as a warning
+	ExactOptionalPropertyTypes         bool  `json:"exactOptionalPropertyTypes"`
 
 	// Module & Resolution
 	Module                   string   `json:"module"`
@@ -61,6 +78,7 @@ type CompilerOptions struct {
 	MaxNodeModuleJsDepth int  `json:"maxNodeModuleJsDepth"`
 
 	// Emit
+	Composite           bool   `json:"composite"`
 	Declaration         bool   `json:"declaration"`
 	DeclarationMap      bool   `json:"declarationMap"`
 	EmitDeclarationOnly bool   `json:"emitDeclarationOnly"`
@@ -134,36 +152,31 @@ func LoadTSConfig(path string) (*TSConfig, error) {
 	if info.IsDir() {
 		configPath = filepath.Join(path, "tsconfig.json")
 	}
+	configPath, _ = filepath.Abs(configPath)
+	configDir := filepath.Dir(configPath)
 
 	// Check if file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		// No tsconfig.json found, return default config
-		return GetDefaultConfig(), nil
+		config := GetDefaultConfig()
+		config.ConfigDir = configDir
+		return config, nil
 	}
 
-	// Read file
-	data, err := os.ReadFile(configPath)
+	layer, err := loadConfigLayer(configPath, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read tsconfig.json: %w", err)
+		return nil, err
 	}
+	layer.substituteConfigDir(configDir)
 
-	// Parse JSON
-	var config TSConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	// Compiler options are decoded once the whole extends chain is merged
+	config := layer.config
+	if err := json.Unmarshal(layer.encodedOptions(), &config.CompilerOptions); err != nil {
 		return nil, fmt.Errorf("failed to parse tsconfig.json: %w", err)
 	}
-
-	// Handle extends
-	if config.Extends != "" {
-		baseConfigPath := filepath.Join(filepath.Dir(configPath), config.Extends)
-		baseConfig, err := LoadTSConfig(baseConfigPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load extended config: %w", err)
-		}
-
-		// Merge configs (current config overrides base)
-		config = mergeConfigs(baseConfig, &config)
-	}
+	config.options = layer.options
+	config.ConfigDir = configDir
+	config.configPath = configPath
 
 	// Apply defaults for unset values
 	applyDefaults(&config)
@@ -190,49 +203,6 @@ func GetDefaultConfig() *TSConfig {
 	}
 }
 
-// mergeConfigs merges two configs, with the second one taking precedence
-func mergeConfigs(base, override *TSConfig) TSConfig {
-	result := *base
-
-	// Merge compiler options
-	if override.CompilerOptions.BaseUrl != "" {
-		result.CompilerOptions.BaseUrl = override.CompilerOptions.BaseUrl
-	}
-	if override.CompilerOptions.Paths != nil {
-		result.CompilerOptions.Paths = override.CompilerOptions.Paths
-	}
-	if override.CompilerOptions.RootDir != "" {
-		result.CompilerOptions.RootDir = override.CompilerOptions.RootDir
-	}
-	if override.CompilerOptions.TypeRoots != nil {
-		result.CompilerOptions.TypeRoots = override.CompilerOptions.TypeRoots
-	}
-	if override.CompilerOptions.Types != nil {
-		result.CompilerOptions.Types = override.CompilerOptions.Types
-	}
-	if override.CompilerOptions.ModuleResolution != "" {
-		result.CompilerOptions.ModuleResolution = override.CompilerOptions.ModuleResolution
-	}
-
-	// Merge type checking options (explicit values override)
-	result.CompilerOptions.Strict = override.CompilerOptions.Strict || base.CompilerOptions.Strict
-	result.CompilerOptions.NoImplicitAny = override.CompilerOptions.NoImplicitAny || base.CompilerOptions.NoImplicitAny
-	result.CompilerOptions.StrictNullChecks = override.CompilerOptions.StrictNullChecks || base.CompilerOptions.StrictNullChecks
-
-	// Merge arrays
-	if len(override.Include) > 0 {
-		result.Include = override.Include
-	}
-	if len(override.Exclude) > 0 {
-		result.Exclude = override.Exclude
-	}
-	if len(override.Files) > 0 {
-		result.Files = override.Files
-	}
-
-	return result
-}
-
 // applyDefaults applies default values to unset config options
 func applyDefaults(config *TSConfig) {
 	if config.CompilerOptions.Target == "" {
@@ -242,13 +212,15 @@ func applyDefaults(config *TSConfig) {
 		config.CompilerOptions.Module = "commonjs"
 	}
 	if config.CompilerOptions.ModuleResolution == "" {
-		config.CompilerOptions.ModuleResolution = "node"
-	}
-	if len(config.Include) == 0 {
-		config.Include = []string{"**/*"}
-	}
-	if len(config.Exclude) == 0 {
-		config.Exclude = []string{"node_modules"}
+		// node16 and nodenext imply the resolution of the same name
+		switch strings.ToLower(config.CompilerOptions.Module) {
+		case "node16", "nodenext":
+			config.CompilerOptions.ModuleResolution = strings.ToLower(config.CompilerOptions.Module)
+		case "preserve":
+			config.CompilerOptions.ModuleResolution = "bundler"
+		default:
+			config.CompilerOptions.ModuleResolution = "node"
+		}
 	}
 
 	// No automatic implications - use exactly what's in the config file
@@ -279,16 +251,16 @@ func (c *TSConfig) ResolvePathAlias(importPath string) []string {
 					// Replace * with the matched suffix
 					if replacement[len(replacement)-1] == '*' {
 						resolved := replacement[:len(replacement)-1] + suffix
-						results = append(results, filepath.Join(baseUrl, resolved))
+						results = append(results, joinBaseUrl(baseUrl, resolved))
 					} else {
-						results = append(results, filepath.Join(baseUrl, replacement))
+						results = append(results, joinBaseUrl(baseUrl, replacement))
 					}
 				}
 			}
 		} else if pattern == importPath {
 			// Exact match
 			for _, replacement := range replacements {
-				results = append(results, filepath.Join(baseUrl, replacement))
+				results = append(results, joinBaseUrl(baseUrl, replacement))
 			}
 		}
 	}
@@ -296,6 +268,15 @@ func (c *TSConfig) ResolvePathAlias(importPath string) []string {
 	return results
 }
 
+// joinBaseUrl resolves a paths target against baseUrl. Targets that started
+// with ${configDir} are already absolute.
+func joinBaseUrl(baseUrl, target string) string {
+	if filepath.IsAbs(target) {
+		return target
+	}
+	return filepath.Join(baseUrl, target)
+}
+
 // GetTypeRoots returns the directories to search for type definitions
 func (c *TSConfig) GetTypeRoots() []string {
 	if len(c.CompilerOptions.TypeRoots) > 0 {
@@ -308,71 +289,7 @@ func (c *TSConfig) GetTypeRoots() []string {
 
 // ShouldCheckFile determines if a file should be type checked based on include/exclude patterns
 func (c *TSConfig) ShouldCheckFile(filePath string) bool {
-	// Check exclude patterns first
-	for _, pattern := range c.Exclude {
-		if matchPattern(filePath, pattern) {
-			return false
-		}
-	}
-
-	// If files array is specified, only check those files
-	if len(c.Files) > 0 {
-		for _, file := range c.Files {
-			if filePath == file {
-				return true
-			}
-		}
-		return false
-	}
-
-	// Check include patterns
-	for _, pattern := range c.Include {
-		if matchPattern(filePath, pattern) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// matchPattern performs simple glob pattern matching
-func matchPattern(path, pattern string) bool {
-	// Normalize paths
-	path = filepath.ToSlash(path)
-	pattern = filepath.ToSlash(pattern)
-
-	// Simple implementation - just check for ** and * wildcards
-	if pattern == "**/*" || pattern == "**" {
-		return true
-	}
-
-	// Check if pattern starts with a directory
-	if len(pattern) > 3 && pattern[len(pattern)-3:] == "/**" {
-		// test/** pattern - match anything under test/
-		prefix := pattern[:len(pattern)-3]
-		return len(path) >= len(prefix) && path[:len(prefix)] == prefix
-	}
-
-	if len(pattern) > 5 && pattern[len(pattern)-5:] == "/**/*" {
-		// test/**/* pattern - match anything under test/
-		prefix := pattern[:len(pattern)-5]
-		return len(path) >= len(prefix) && path[:len(prefix)] == prefix
-	}
-
-	// Check if pattern contains wildcards at the start
-	if len(pattern) > 2 && pattern[0] == '*' && pattern[1] == '*' {
-		// **/*.ts pattern
-		suffix := pattern[3:] // Skip **/
-		if suffix[0] == '*' {
-			// **/* - match all
-			return true
-		}
-		// Check extension
-		return filepath.Ext(path) == filepath.Ext(suffix)
-	}
-
-	// Exact match
-	return path == pattern
+	return c.FileMatcher().Match(filePath)
 }
 
 // IsStrictMode returns true if strict mode is enabled
@@ -422,7 +339,7 @@ func (c *CompilerOptions) ShouldCheckPropertyInitialization() bool {
 
 // ShouldAllowUnreachableCode returns true if unreachable code is allowed
 func (c *CompilerOptions) ShouldAllowUnreachableCode() bool {
-	return c.AllowUnreachableCode
+	return c.AllowUnreachableCode != nil && *c.AllowUnreachableCode
 }
 
 // ShouldAllowUnusedLabels returns true if unused labels are allowed
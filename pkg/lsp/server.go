@@ -0,0 +1,364 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"tstypechecker/pkg/checker"
+)
+
+// Workspace is the project backend behind the server. It owns the long-lived
+// type checker so libs and node_modules types are loaded only once.
+type Workspace interface {
+	// Load prepares the workspace for the given project root
+	Load(rootDir string) error
+	// Check type checks the in-memory content of a document
+	Check(path string, content string) []checker.TypeError
+	// Close forgets the in-memory content of a document
+	Close(path string)
+}
+
+// document is an open editor buffer
+type document struct {
+	uri     string
+	path    string
+	version int
+	text    string
+}
+
+// Server is a Language Server Protocol server speaking JSON-RPC over a stream
+type Server struct {
+	transport   *transport
+	workspace   Workspace
+	documents   map[string]*document // Keyed by URI
+	initialized bool
+	shutdown    bool
+}
+
+// NewServer creates a server reading requests from r and writing responses to w
+func NewServer(r io.Reader, w io.Writer, workspace Workspace) *Server {
+	return &Server{
+		transport: newTransport(r, w),
+		workspace: workspace,
+		documents: make(map[string]*document),
+	}
+}
+
+// Serve processes messages until the client sends "exit" or the stream ends
+func (s *Server) Serve() error {
+	for {
+		body, err := s.transport.readMessage()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.replyError(nil, codeParseError, err.Error())
+			continue
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("exit received before shutdown")
+			}
+			return nil
+		}
+
+		s.handle(&msg)
+	}
+}
+
+// handle dispatches a single request or notification
+func (s *Server) handle(msg *message) {
+	if msg.Method == "" {
+		// Responses to server-initiated requests are not used
+		return
+	}
+
+	if !s.initialized && msg.Method != "initialize" {
+		if msg.ID != nil {
+			s.replyError(msg.ID, codeServerNotReady, "server not initialized")
+		}
+		return
+	}
+
+	switch msg.Method {
+	case "initialize":
+		s.handleInitialize(msg)
+	case "initialized":
+		// Nothing to do
+	case "shutdown":
+		s.shutdown = true
+		s.reply(msg.ID, nil)
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if s.decodeParams(msg, &params) {
+			s.didOpen(params)
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if s.decodeParams(msg, &params) {
+			s.didChange(params)
+		}
+	case "textDocument/didSave":
+		var params didSaveParams
+		if s.decodeParams(msg, &params) {
+			s.didSave(params)
+		}
+	case "textDocument/didClose":
+		var params didCloseParams
+		if s.decodeParams(msg, &params) {
+			s.didClose(params)
+		}
+	default:
+		// Notifications we don't understand are silently ignored
+		if msg.ID != nil {
+			s.replyError(msg.ID, codeMethodNotFound, "method not found: "+msg.Method)
+		}
+	}
+}
+
+func (s *Server) handleInitialize(msg *message) {
+	var params initializeParams
+	if !s.decodeParams(msg, &params) {
+		return
+	}
+
+	rootDir := ""
+	switch {
+	case params.RootURI != "":
+		rootDir = uriToPath(params.RootURI)
+	case len(params.WorkspaceFolders) > 0:
+		rootDir = uriToPath(params.WorkspaceFolders[0].URI)
+	case params.RootPath != "":
+		rootDir = params.RootPath
+	}
+
+	if err := s.workspace.Load(rootDir); err != nil {
+		s.replyError(msg.ID, codeInvalidRequest, err.Error())
+		return
+	}
+
+	s.initialized = true
+	s.reply(msg.ID, initializeResult{
+		Capabilities: serverCapabilities{
+			TextDocumentSync: textDocumentSyncOptions{
+				OpenClose: true,
+				Change:    textDocumentSyncFull,
+				Save:      saveOptions{IncludeText: true},
+			},
+		},
+		ServerInfo: serverInfo{Name: "tscheck"},
+	})
+}
+
+func (s *Server) didOpen(params didOpenParams) {
+	doc := &document{
+		uri:     params.TextDocument.URI,
+		path:    uriToPath(params.TextDocument.URI),
+		version: params.TextDocument.Version,
+		text:    params.TextDocument.Text,
+	}
+	s.documents[doc.uri] = doc
+	s.recheck(doc)
+}
+
+func (s *Server) didChange(params didChangeParams) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok || len(params.ContentChanges) == 0 {
+		return
+	}
+	// Full sync: the last change holds the complete text
+	doc.text = params.ContentChanges[len(params.ContentChanges)-1].Text
+	doc.version = params.TextDocument.Version
+	s.recheck(doc)
+}
+
+func (s *Server) didSave(params didSaveParams) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return
+	}
+	if params.Text != nil {
+		doc.text = *params.Text
+	}
+	s.recheck(doc)
+}
+
+func (s *Server) didClose(params didCloseParams) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return
+	}
+	delete(s.documents, doc.uri)
+	s.workspace.Close(doc.path)
+	s.publish(doc, []diagnostic{})
+}
+
+// recheck checks the changed document first and then every other open document,
+// since any of them may import the one that changed
+func (s *Server) recheck(changed *document) {
+	s.checkDocument(changed)
+
+	uris := make([]string, 0, len(s.documents))
+	for uri := range s.documents {
+		if uri != changed.uri {
+			uris = append(uris, uri)
+		}
+	}
+	sort.Strings(uris)
+
+	for _, uri := range uris {
+		s.checkDocument(s.documents[uri])
+	}
+}
+
+func (s *Server) checkDocument(doc *document) {
+	errors := s.workspace.Check(doc.path, doc.text)
+
+	lines := strings.Split(doc.text, "\n")
+	diagnostics := make([]diagnostic, 0, len(errors))
+	for _, e := range errors {
+		if e.File != "" && e.File != doc.path {
+			continue
+		}
+		diagnostics = append(diagnostics, toDiagnostic(e, lines))
+	}
+	s.publish(doc, diagnostics)
+}
+
+func (s *Server) publish(doc *document, diagnostics []diagnostic) {
+	version := doc.version
+	s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     &version,
+		Diagnostics: diagnostics,
+	})
+}
+
+func (s *Server) decodeParams(msg *message, v interface{}) bool {
+	if len(msg.Params) == 0 {
+		return true
+	}
+	if err := json.Unmarshal(msg.Params, v); err != nil {
+		if msg.ID != nil {
+			s.replyError(msg.ID, codeInvalidParams, err.Error())
+		}
+		return false
+	}
+	return true
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}) {
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	_ = s.transport.writeMessage(&message{ID: id, Result: result})
+}
+
+func (s *Server) replyError(id *json.RawMessage, code int, text string) {
+	if id == nil {
+		null := json.RawMessage("null")
+		id = &null
+	}
+	_ = s.transport.writeMessage(&message{ID: id, Error: &responseError{Code: code, Message: text}})
+}
+
+func (s *Server) notify(method string, params interface{}) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	_ = s.transport.writeMessage(&message{Method: method, Params: raw})
+}
+
+// toDiagnostic converts a 1-based TypeError into a 0-based, UTF-16 LSP diagnostic
+// spanning the token at the error position
+func toDiagnostic(e checker.TypeError, lines []string) diagnostic {
+	line := e.Line - 1
+	if line < 0 {
+		line = 0
+	}
+	startByte := e.Column - 1
+	if startByte < 0 {
+		startByte = 0
+	}
+
+	text := ""
+	if line < len(lines) {
+		text = strings.TrimRight(lines[line], "\r")
+	}
+	if startByte > len(text) {
+		startByte = len(text)
+	}
+	endByte := tokenEnd(text, startByte)
+
+	severity := severityError
+	if e.Severity == "warning" {
+		severity = severityWarning
+	}
+
+	return diagnostic{
+		Range: lspRange{
+			Start: position{Line: line, Character: utf16Length(text[:startByte])},
+			End:   position{Line: line, Character: utf16Length(text[:endByte])},
+		},
+		Severity: severity,
+		Code:     e.Code,
+		Source:   "tscheck",
+		Message:  e.Message,
+	}
+}
+
+// tokenEnd returns the byte offset just past the identifier-like token at start,
+// or start+1 when the position is on punctuation
+func tokenEnd(text string, start int) int {
+	end := start
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !(r == '_' || r == '$' || r >= utf8.RuneSelf || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')) {
+			break
+		}
+		end += size
+	}
+	if end == start && start < len(text) {
+		_, size := utf8.DecodeRuneInString(text[start:])
+		end += size
+	}
+	return end
+}
+
+// utf16Length counts UTF-16 code units, the unit LSP uses for character offsets
+func utf16Length(s string) int {
+	n := 0
+	for _, r := range s {
+		n += len(utf16.Encode([]rune{r}))
+	}
+	return n
+}
+
+// uriToPath converts a file:// URI into a local file path
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	// file:///C:/dir on Windows parses to /C:/dir
+	if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
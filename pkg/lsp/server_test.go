@@ -0,0 +1,107 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"tstypechecker/pkg/checker"
+)
+
+type fakeWorkspace struct {
+	loadedRoot string
+	checked    []string
+}
+
+func (w *fakeWorkspace) Load(rootDir string) error {
+	w.loadedRoot = rootDir
+	return nil
+}
+
+func (w *fakeWorkspace) Check(path string, content string) []checker.TypeError {
+	w.checked = append(w.checked, content)
+	if !strings.Contains(content, "bad") {
+		return nil
+	}
+	return []checker.TypeError{{
+		File:     path,
+		Line:     2,
+		Column:   9,
+		Message:  "Cannot find name 'bad'.",
+		Code:     "TS2304",
+		Severity: "error",
+	}}
+}
+
+func (w *fakeWorkspace) Close(path string) {}
+
+func frame(t *testing.T, msg map[string]interface{}) string {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func TestServerPublishesDiagnostics(t *testing.T) {
+	uri := "file:///project/a.ts"
+	input := frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]interface{}{"rootUri": "file:///project"}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "languageId": "typescript", "version": 1, "text": "const a = 1;\nlet x = bad;\n"},
+		}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didChange", "params": map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+			"contentChanges": []map[string]interface{}{{"text": "const a = 1;\n"}},
+		}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "shutdown"}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "exit"})
+
+	var out bytes.Buffer
+	ws := &fakeWorkspace{}
+	if err := NewServer(strings.NewReader(input), &out, ws).Serve(); err != nil {
+		t.Fatalf("Serve returned error: %v", err)
+	}
+
+	if ws.loadedRoot != "/project" {
+		t.Errorf("expected workspace root /project, got %q", ws.loadedRoot)
+	}
+	if len(ws.checked) != 2 || ws.checked[1] != "const a = 1;\n" {
+		t.Errorf("expected the changed buffer to be checked, got %q", ws.checked)
+	}
+
+	tr := newTransport(&out, nil)
+	var published []publishDiagnosticsParams
+	for {
+		body, err := tr.readMessage()
+		if err != nil {
+			break
+		}
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Method == "textDocument/publishDiagnostics" {
+			var params publishDiagnosticsParams
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				t.Fatal(err)
+			}
+			published = append(published, params)
+		}
+	}
+
+	if len(published) != 2 {
+		t.Fatalf("expected 2 publishDiagnostics notifications, got %d", len(published))
+	}
+	if len(published[0].Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic after open, got %d", len(published[0].Diagnostics))
+	}
+	d := published[0].Diagnostics[0]
+	if d.Range.Start != (position{Line: 1, Character: 8}) || d.Range.End != (position{Line: 1, Character: 11}) {
+		t.Errorf("unexpected range %+v", d.Range)
+	}
+	if len(published[1].Diagnostics) != 0 {
+		t.Errorf("expected diagnostics to be cleared after the fix, got %d", len(published[1].Diagnostics))
+	}
+}
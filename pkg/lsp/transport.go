@@ -0,0 +1,81 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// transport reads and writes JSON-RPC messages framed with Content-Length headers
+type transport struct {
+	reader *bufio.Reader
+	writer io.Writer
+	mu     sync.Mutex // Serializes writes so messages never interleave
+}
+
+func newTransport(r io.Reader, w io.Writer) *transport {
+	return &transport{
+		reader: bufio.NewReader(r),
+		writer: w,
+	}
+}
+
+// readMessage reads the next framed message body
+func (t *transport) readMessage() ([]byte, error) {
+	contentLength := -1
+
+	for {
+		line, err := t.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("malformed header: %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length: %w", err)
+			}
+			contentLength = n
+		}
+	}
+
+	if contentLength < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	body := make([]byte, contentLength)
+	if _, err := io.ReadFull(t.reader, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// writeMessage encodes msg as JSON and writes it with its header
+func (t *transport) writeMessage(msg *message) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, err := fmt.Fprintf(t.writer, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = t.writer.Write(body)
+	return err
+}
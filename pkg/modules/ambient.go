@@ -0,0 +1,292 @@
+package modules
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"tstypechecker/pkg/ast"
+	"tstypechecker/pkg/types"
+)
+
+// moduleDeclaration is a declare module 'name' { ... } block of a project file.
+// In a script it declares an ambient module, which imports of name resolve to.
+// In a module it augments the module name resolves to from that file, or the
+// ambient module a script declares with that name.
+type moduleDeclaration struct {
+	decl *ast.ModuleDeclaration
+	file string
+
+	// The block is declared in a module
+	augmentation bool
+
+	// Path of the module an augmentation adds to, empty for ambient modules
+	// and augmentations of ambient modules
+	target string
+}
+
+// DeclareModules registers the declare module blocks of filePath, replacing
+// the ones registered for it before. isModule tells whether the file has
+// imports or exports, which makes its blocks augmentations. Only scripts
+// declare ambient modules: an augmentation of a name that neither resolves
+// nor is declared by a script adds nothing.
+func (r *ModuleResolver) DeclareModules(filePath string, decls []*ast.ModuleDeclaration, isModule bool) {
+	r.mu.RLock()
+	registered := r.moduleDeclarations[filePath]
+	unchanged := sameModuleDeclarations(registered, decls) && (len(decls) == 0 || r.augmentingFiles[filePath] == isModule)
+	r.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	declared := make([]moduleDeclaration, 0, len(decls))
+	for _, decl := range decls {
+		md := moduleDeclaration{decl: decl, file: filePath, augmentation: isModule}
+		if isModule {
+			if path, err := r.resolvePath(decl.Name, filepath.Dir(filePath), filePath); err == nil {
+				md.target = path
+			}
+		}
+		declared = append(declared, md)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(declared) == 0 {
+		delete(r.moduleDeclarations, filePath)
+		delete(r.augmentingFiles, filePath)
+	} else {
+		r.moduleDeclarations[filePath] = declared
+		r.augmentingFiles[filePath] = isModule
+	}
+	// Any resolution may change, so start over
+	r.moduleCache = make(map[string]*ResolvedModule)
+	r.ambientModules = make(map[string]*ResolvedModule)
+	r.notFoundCache = make(map[string]bool)
+}
+
+// IsUnresolvedAugmentation reports whether decl, a declare module block of
+// the module filePath, names neither a module that resolves from filePath
+// nor an ambient module
+func (r *ModuleResolver) IsUnresolvedAugmentation(filePath string, decl *ast.ModuleDeclaration) bool {
+	r.mu.RLock()
+	registered := r.moduleDeclarations[filePath]
+	r.mu.RUnlock()
+	// The blocks registered may come from an earlier parse of the file
+	for _, md := range registered {
+		if md.decl.Name == decl.Name && md.decl.Position == decl.Position {
+			return md.augmentation && md.target == "" && !r.declaresAmbientModule(decl.Name)
+		}
+	}
+	return false
+}
+
+// declaresAmbientModule reports whether a script declares the module name
+func (r *ModuleResolver) declaresAmbientModule(name string) bool {
+	decls := r.declarationsOf(func(md moduleDeclaration) bool {
+		return !md.augmentation && md.decl.Name == name
+	})
+	return len(decls) > 0
+}
+
+// sameModuleDeclarations reports whether decls are the blocks registered
+// before, so that checking a file again keeps the modules resolved so far
+func sameModuleDeclarations(registered []moduleDeclaration, decls []*ast.ModuleDeclaration) bool {
+	if len(registered) != len(decls) {
+		return false
+	}
+	for i, md := range registered {
+		decl := decls[i]
+		if md.decl.Name != decl.Name || md.decl.Position != decl.Position || md.decl.EndPos != decl.EndPos {
+			return false
+		}
+	}
+	return true
+}
+
+// declarationsOf returns the registered blocks that match, ordered by file
+func (r *ModuleResolver) declarationsOf(match func(md moduleDeclaration) bool) []moduleDeclaration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files := make([]string, 0, len(r.moduleDeclarations))
+	for file := range r.moduleDeclarations {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var found []moduleDeclaration
+	for _, file := range files {
+		for _, md := range r.moduleDeclarations[file] {
+			if match(md) {
+				found = append(found, md)
+			}
+		}
+	}
+	return found
+}
+
+// ambientModule returns the module the ambient declarations of name declare,
+// merged with the augmentations of it, or nil when no script declares it
+func (r *ModuleResolver) ambientModule(name string) *ResolvedModule {
+	r.mu.RLock()
+	cached, exists := r.ambientModules[name]
+	r.mu.RUnlock()
+	if exists {
+		return cached
+	}
+
+	decls := r.declarationsOf(func(md moduleDeclaration) bool {
+		return md.target == "" && md.decl.Name == name
+	})
+	var module *ResolvedModule
+	if r.declaresAmbientModule(name) {
+		// The module is located at its declaration rather than an augmentation
+		sort.SliceStable(decls, func(i, j int) bool {
+			return !decls[i].augmentation && decls[j].augmentation
+		})
+		module = r.declaredModule(name, decls)
+	}
+
+	r.mu.Lock()
+	r.ambientModules[name] = module
+	r.mu.Unlock()
+	return module
+}
+
+// wildcardModule returns the ambient module whose pattern, such as *.svg,
+// matches specifier. As in tsc, the pattern with the longest prefix wins.
+func (r *ModuleResolver) wildcardModule(specifier string) *ResolvedModule {
+	patterns := r.declarationsOf(func(md moduleDeclaration) bool {
+		return !md.augmentation && strings.Contains(md.decl.Name, "*")
+	})
+	pattern, longest := "", -1
+	for _, md := range patterns {
+		name := md.decl.Name
+		star := strings.Index(name, "*")
+		prefix, suffix := name[:star], name[star+1:]
+		if star > longest && len(specifier) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(specifier, prefix) && strings.HasSuffix(specifier, suffix) {
+			pattern, longest = name, star
+		}
+	}
+	if pattern == "" {
+		return nil
+	}
+	return r.ambientModule(pattern)
+}
+
+// declaredModule builds the module the bodies of decls declare
+func (r *ModuleResolver) declaredModule(name string, decls []moduleDeclaration) *ResolvedModule {
+	module := &ResolvedModule{
+		AbsolutePath:  decls[0].file,
+		RelativePath:  r.getRelativePath(decls[0].file),
+		Specifier:     name,
+		ModuleSymbols: r.symbolTable,
+		Exports:       make(map[string]*ExportInfo),
+		IsTypeScript:  true,
+	}
+	for _, md := range decls {
+		if md.decl.Shorthand {
+			// declare module 'name'; leaves every import untyped
+			module.Untyped = true
+			module.DefaultExport = &ExportInfo{
+				Name:         "default",
+				Type:         "default",
+				Node:         md.decl,
+				Position:     md.decl.Pos(),
+				ResolvedType: types.Any,
+			}
+			return module
+		}
+	}
+
+	NewModuleAnalyzer(r).AnalyzeModule(module, declaredBody(decls))
+	return module
+}
+
+// declaredBody returns a file with the statements of the bodies of decls.
+// Declarations in a declare module block are exported without the export
+// keyword, unless the block picks what it exports with export { ... }.
+func declaredBody(decls []moduleDeclaration) *ast.File {
+	file := &ast.File{Name: decls[0].file, Position: decls[0].decl.Position}
+	for _, md := range decls {
+		file.Body = append(file.Body, md.decl.Body...)
+	}
+	for _, stmt := range file.Body {
+		if export, ok := stmt.(*ast.ExportDeclaration); ok && export.Declaration == nil {
+			return file
+		}
+	}
+
+	body := make([]ast.Statement, len(file.Body))
+	for i, stmt := range file.Body {
+		switch stmt.(type) {
+		case *ast.FunctionDeclaration, *ast.VariableDeclaration, *ast.TypeAliasDeclaration, *ast.InterfaceDeclaration:
+			body[i] = &ast.ExportDeclaration{Declaration: stmt, Position: stmt.Pos(), EndPos: stmt.End()}
+		default:
+			body[i] = stmt
+		}
+	}
+	file.Body = body
+	return file
+}
+
+// augmentedModule returns module with the members the augmentations of the
+// project add to it, or module itself when nothing augments it
+func (r *ModuleResolver) augmentedModule(module *ResolvedModule) *ResolvedModule {
+	decls := r.declarationsOf(func(md moduleDeclaration) bool {
+		return md.target == module.AbsolutePath
+	})
+	if len(decls) == 0 {
+		return module
+	}
+
+	additions := &ResolvedModule{
+		AbsolutePath:  decls[0].file,
+		ModuleSymbols: r.symbolTable,
+		Exports:       make(map[string]*ExportInfo),
+	}
+	NewModuleAnalyzer(r).AnalyzeModule(additions, declaredBody(decls))
+
+	augmented := *module
+	augmented.Exports = make(map[string]*ExportInfo, len(module.Exports)+len(additions.Exports))
+	for name, export := range module.Exports {
+		augmented.Exports[name] = export
+	}
+	for name, addition := range additions.Exports {
+		export, exists := augmented.Exports[name]
+		if !exists {
+			// A module that did not parse has lost its exports, and an
+			// interface it declares would be replaced by the augmentation
+			// alone, so it stays unresolved instead
+			if _, addsInterface := addition.Node.(*ast.InterfaceDeclaration); addsInterface && module.ModuleAST == nil {
+				continue
+			}
+			augmented.Exports[name] = addition
+			continue
+		}
+		// An interface of the module merges with the one the augmentation declares
+		original, isInterface := export.Node.(*ast.InterfaceDeclaration)
+		added, addsInterface := addition.Node.(*ast.InterfaceDeclaration)
+		if isInterface && addsInterface {
+			merged := *export
+			merged.Node = mergeInterfaces(original, added)
+			merged.IsReExport = false
+			merged.ResolvedType = nil
+			augmented.Exports[name] = &merged
+		}
+	}
+	if augmented.DefaultExport == nil {
+		augmented.DefaultExport = additions.DefaultExport
+	}
+	return &augmented
+}
+
+// mergeInterfaces returns an interface with the members of both declarations
+func mergeInterfaces(original, added *ast.InterfaceDeclaration) *ast.InterfaceDeclaration {
+	merged := *original
+	merged.Members = append(append([]ast.TypeMember{}, original.Members...), added.Members...)
+	merged.Extends = append(append([]ast.TypeNode{}, original.Extends...), added.Extends...)
+	return &merged
+}
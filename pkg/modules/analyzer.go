@@ -2,6 +2,7 @@ package modules
 
 import (
 	"fmt"
+	"strings"
 	"tstypechecker/pkg/ast"
 )
 
@@ -32,6 +33,17 @@ func (a *ModuleAnalyzer) AnalyzeModule(module *ResolvedModule, file *ast.File) e
 		}
 	}
 
+	// Un componente Vue siempre exporta por defecto el componente, aunque
+	// <script setup> no tenga export default
+	if module.DefaultExport == nil && strings.HasSuffix(module.AbsolutePath, ".vue") {
+		module.DefaultExport = &ExportInfo{
+			Name:     "default",
+			Type:     "default",
+			Node:     file,
+			Position: file.Pos(),
+		}
+	}
+
 	return nil
 }
 
@@ -65,7 +77,9 @@ func (a *ModuleAnalyzer) analyzeStatement(module *ResolvedModule, stmt ast.State
 	case *ast.ModuleDeclaration:
 		// Process statements inside declare module blocks
 		// This handles files like: declare module 'foo' { export type Bar = ... }
-		if s.Body != nil {
+		// A module file augments other modules with them instead, and declare
+		// global adds to the global scope
+		if s.Body != nil && !s.Global && !module.isModuleFile() {
 			for _, innerStmt := range s.Body {
 				if err := a.analyzeStatement(module, innerStmt); err != nil {
 					return err
@@ -182,10 +196,19 @@ func (a *ModuleAnalyzer) analyzeExportDeclaration(module *ResolvedModule, export
 			}
 		default:
 			// This might be a default export (e.g., export default expression)
+			node := ast.Node(export.Declaration)
+			// export default name exports the declaration of name
+			if expr, ok := export.Declaration.(*ast.ExpressionStatement); ok {
+				if id, ok := expr.Expression.(*ast.Identifier); ok {
+					if decl := a.findDeclaration(module, id.Name); decl != nil {
+						node = decl
+					}
+				}
+			}
 			module.DefaultExport = &ExportInfo{
 				Name:     "default",
 				Type:     "default",
-				Node:     export.Declaration,
+				Node:     node,
 				Position: export.Pos(),
 			}
 		}
@@ -210,6 +233,9 @@ func (a *ModuleAnalyzer) findDeclaration(module *ResolvedModule, name string) as
 
 	// Search through all top-level statements
 	for _, stmt := range module.ModuleAST.Body {
+		if export, ok := stmt.(*ast.ExportDeclaration); ok && export.Declaration != nil {
+			stmt = export.Declaration
+		}
 		switch s := stmt.(type) {
 		case *ast.FunctionDeclaration:
 			if s.ID != nil && s.ID.Name == name {
@@ -226,3 +252,17 @@ func (a *ModuleAnalyzer) findDeclaration(module *ResolvedModule, name string) as
 
 	return nil
 }
+
+// isModuleFile reports whether the file of a module has imports or exports
+func (m *ResolvedModule) isModuleFile() bool {
+	if m.ModuleAST == nil {
+		return false
+	}
+	for _, stmt := range m.ModuleAST.Body {
+		switch stmt.(type) {
+		case *ast.ImportDeclaration, *ast.ExportDeclaration:
+			return true
+		}
+	}
+	return false
+}
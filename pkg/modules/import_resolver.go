@@ -175,6 +175,9 @@ func (ir *ImportResolver) ResolveImport(importDecl *ast.ImportDeclaration) (map[
 
 		// Create namespace type as an ObjectType with exported members
 		namespaceType := types.NewObjectType("typeof "+namespaceSpecifier.Local.Name, exportProperties)
+		if resolvedModule.Untyped {
+			namespaceType = types.Any
+		}
 
 		// Crear un símbolo que represente el namespace completo
 		namespaceSymbol := &symbols.Symbol{
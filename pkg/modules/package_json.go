@@ -0,0 +1,558 @@
+package modules
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Package resolution through package.json, following the moduleResolution
+// algorithm selected in tsconfig:
+//
+//   - node10 reads "types", "typings" and "main"
+//   - node16/nodenext and bundler resolve "exports" and "imports" maps with
+//     condition matching, and only fall back to the fields above when a
+//     package has no "exports"
+//
+// Every algorithm applies "typesVersions" redirects.
+
+// Module resolution algorithms
+const (
+	ResolutionNode10  = "node10"
+	ResolutionNode16  = "node16"
+	ResolutionBundler = "bundler"
+)
+
+// TypeScriptVersion is the compiler version typesVersions ranges are matched against
+const TypeScriptVersion = "5.6.0"
+
+// packageJSON holds the fields of a package.json that drive resolution
+type packageJSON struct {
+	dir string
+
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	Types         string          `json:"types"`
+	Typings       string          `json:"typings"`
+	Main          string          `json:"main"`
+	Exports       json.RawMessage `json:"exports"`
+	Imports       json.RawMessage `json:"imports"`
+	TypesVersions json.RawMessage `json:"typesVersions"`
+}
+
+// jsonEntry is a member of a JSON object, kept in document order because
+// conditions are matched in the order a package lists them
+type jsonEntry struct {
+	key   string
+	value json.RawMessage
+}
+
+// NormalizeModuleResolution maps a moduleResolution value to the algorithm
+// it selects
+func NormalizeModuleResolution(kind string) string {
+	switch strings.ToLower(kind) {
+	case "node16", "nodenext":
+		return ResolutionNode16
+	case "bundler":
+		return ResolutionBundler
+	default:
+		// node, node10 and classic
+		return ResolutionNode10
+	}
+}
+
+// SetModuleResolution selects the resolution algorithm and the custom
+// conditions matched in exports and imports maps
+func (r *ModuleResolver) SetModuleResolution(kind string, customConditions []string) {
+	r.moduleResolution = NormalizeModuleResolution(kind)
+	r.customConditions = customConditions
+}
+
+// usesExportsMaps reports whether the algorithm reads exports and imports
+func (r *ModuleResolver) usesExportsMaps() bool {
+	return r.moduleResolution == ResolutionNode16 || r.moduleResolution == ResolutionBundler
+}
+
+// resolutionConditions returns the conditions matched for imports in
+// fromFile, besides "default" which always matches
+func (r *ModuleResolver) resolutionConditions(fromFile string) []string {
+	conditions := []string{"types"}
+	switch r.moduleResolution {
+	case ResolutionNode16:
+		conditions = append(conditions, "node")
+		if r.isESModule(fromFile) {
+			conditions = append(conditions, "import")
+		} else {
+			conditions = append(conditions, "require")
+		}
+	case ResolutionBundler:
+		conditions = append(conditions, "import")
+	}
+	return append(conditions, r.customConditions...)
+}
+
+// isESModule reports whether Node treats file as an ES module: .mts and .mjs
+// files always are, .cts and .cjs never, and other files when the nearest
+// package.json has "type": "module"
+func (r *ModuleResolver) isESModule(file string) bool {
+	switch {
+	case strings.HasSuffix(file, ".mts"), strings.HasSuffix(file, ".mjs"):
+		return true
+	case strings.HasSuffix(file, ".cts"), strings.HasSuffix(file, ".cjs"):
+		return false
+	}
+	pkg := r.nearestPackageJSON(filepath.Dir(file))
+	return pkg != nil && pkg.Type == "module"
+}
+
+// readPackageJSON returns the package.json in dir, or nil when there is none
+func (r *ModuleResolver) readPackageJSON(dir string) *packageJSON {
+	r.mu.RLock()
+	pkg, cached := r.packageCache[dir]
+	r.mu.RUnlock()
+	if cached {
+		return pkg
+	}
+
+	if data, err := r.readFile(filepath.Join(dir, "package.json")); err == nil {
+		pkg = &packageJSON{dir: dir}
+		if json.Unmarshal(data, pkg) != nil {
+			pkg = nil
+		}
+	}
+
+	r.mu.Lock()
+	r.packageCache[dir] = pkg
+	r.mu.Unlock()
+	return pkg
+}
+
+// nearestPackageJSON returns the package.json of the package dir is in
+func (r *ModuleResolver) nearestPackageJSON(dir string) *packageJSON {
+	for {
+		if pkg := r.readPackageJSON(dir); pkg != nil {
+			return pkg
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// splitPackageSpecifier splits "pkg/sub/path" or "@scope/pkg/sub/path" into
+// the package name and the subpath as exports spells it ("." or "./sub/path")
+func splitPackageSpecifier(specifier string) (string, string) {
+	parts := strings.SplitN(specifier, "/", 3)
+	n := 1
+	if strings.HasPrefix(specifier, "@") && len(parts) > 1 {
+		n = 2
+	}
+	if len(parts) <= n {
+		return specifier, "."
+	}
+	name := strings.Join(parts[:n], "/")
+	return name, "." + specifier[len(name):]
+}
+
+// typesPackageName returns the DefinitelyTyped package of a package:
+// @types/lodash for lodash and @types/babel__core for @babel/core
+func typesPackageName(name string) string {
+	if strings.HasPrefix(name, "@") {
+		return "@types/" + strings.Replace(name[1:], "/", "__", 1)
+	}
+	return "@types/" + name
+}
+
+// resolvePackage resolves subpath inside the package in pkgDir
+func (r *ModuleResolver) resolvePackage(pkgDir, subpath, fromFile string) (string, bool) {
+	pkg := r.readPackageJSON(pkgDir)
+	if pkg != nil && r.usesExportsMaps() && len(pkg.Exports) > 0 && string(pkg.Exports) != "null" {
+		// A package with exports hides every path it does not export
+		defer r.traceRule("%s exports", filepath.Join(pkgDir, "package.json"))()
+		conditions := r.resolutionConditions(fromFile)
+		target, ok := resolvePackageMap(pkg.Exports, subpath, conditions, false)
+		if !ok {
+			r.traceStep(subpath, "not exported under conditions "+strings.Join(append(conditions, "default"), ", "))
+			return "", false
+		}
+		return r.resolvePackageTarget(filepath.Join(pkgDir, filepath.FromSlash(target)))
+	}
+
+	if subpath == "." {
+		if pkg != nil {
+			for _, field := range []struct{ name, entry string }{{"types", pkg.Types}, {"typings", pkg.Typings}, {"main", pkg.Main}} {
+				if field.entry == "" {
+					continue
+				}
+				restoreRule := r.traceRule("%s %q", filepath.Join(pkgDir, "package.json"), field.name)
+				if resolved, ok := r.resolvePackageTarget(filepath.Join(pkgDir, filepath.FromSlash(r.typesVersionsPath(pkg, field.entry)))); ok {
+					return resolved, true
+				}
+				restoreRule()
+			}
+		}
+		resolved, err := r.resolveFilePath(pkgDir)
+		return resolved, err == nil
+	}
+
+	path := strings.TrimPrefix(subpath, "./")
+	if pkg != nil {
+		path = r.typesVersionsPath(pkg, path)
+	}
+	return r.resolvePackageTarget(filepath.Join(pkgDir, filepath.FromSlash(path)))
+}
+
+// typesVersionsPath applies the typesVersions of pkg to path, tracing the
+// redirect
+func (r *ModuleResolver) typesVersionsPath(pkg *packageJSON, path string) string {
+	redirected := pkg.applyTypesVersions(path)
+	if redirected != path {
+		r.traceStep(path, "typesVersions for TypeScript "+TypeScriptVersion+" redirects it to "+redirected)
+	}
+	return redirected
+}
+
+// resolvePackageImport resolves a "#name" specifier through the imports map
+// of the package fromFile belongs to
+func (r *ModuleResolver) resolvePackageImport(specifier, fromFile string) (string, bool) {
+	if !r.usesExportsMaps() {
+		r.traceStep("", "imports maps need moduleResolution node16, nodenext or bundler")
+		return "", false
+	}
+	pkg := r.nearestPackageJSON(filepath.Dir(fromFile))
+	if pkg == nil || len(pkg.Imports) == 0 {
+		r.traceStep("", "no package.json with imports above the importing file")
+		return "", false
+	}
+	conditions := r.resolutionConditions(fromFile)
+	target, ok := resolvePackageMap(pkg.Imports, specifier, conditions, true)
+	if !ok {
+		r.traceStep(filepath.Join(pkg.dir, "package.json"), "no imports entry under conditions "+strings.Join(append(conditions, "default"), ", "))
+		return "", false
+	}
+	if !strings.HasPrefix(target, "./") {
+		// Imports may point at another package: "#dep": "some-package"
+		resolved, err := r.resolveNodeModule(target, pkg.dir, fromFile)
+		return resolved, err == nil
+	}
+	return r.resolvePackageTarget(filepath.Join(pkg.dir, filepath.FromSlash(target)))
+}
+
+// declarationExtensions maps the extension of an emitted file to the
+// extensions of the files that declare its types, by priority
+var declarationExtensions = map[string][]string{
+	".js":  {".ts", ".tsx", ".d.ts"},
+	".jsx": {".ts", ".tsx", ".d.ts"},
+	".mjs": {".mts", ".d.mts"},
+	".cjs": {".cts", ".d.cts"},
+}
+
+// resolvePackageTarget resolves a file named by package.json: the types of
+// an emitted .js file are in the .ts or .d.ts next to it
+func (r *ModuleResolver) resolvePackageTarget(path string) (string, bool) {
+	ext := filepath.Ext(path)
+	if declarations, ok := declarationExtensions[ext]; ok {
+		stem := strings.TrimSuffix(path, ext)
+		for _, declExt := range declarations {
+			if r.fileExists(stem + declExt) {
+				return stem + declExt, true
+			}
+		}
+	}
+	if resolved, err := r.resolveFilePath(path); err == nil {
+		return resolved, true
+	}
+	for _, declExt := range []string{".d.mts", ".d.cts", ".mts", ".cts"} {
+		if r.fileExists(path + declExt) {
+			return path + declExt, true
+		}
+	}
+	return "", false
+}
+
+// resolvePackageMap finds the target of subpath in an exports or imports
+// map: exact keys first, then the "*" pattern with the longest prefix, then
+// legacy folder mappings ending in "/"
+func resolvePackageMap(raw json.RawMessage, subpath string, conditions []string, imports bool) (string, bool) {
+	entries, isObject := orderedEntries(raw)
+	if !imports && (!isObject || (len(entries) > 0 && !strings.HasPrefix(entries[0].key, "."))) {
+		// "exports": "./index.js" and condition objects describe "." only
+		if subpath != "." {
+			return "", false
+		}
+		return resolveMapTarget(raw, "", conditions, imports)
+	}
+
+	bestKey, bestMatch := "", ""
+	for _, entry := range entries {
+		if entry.key == subpath && !strings.Contains(entry.key, "*") {
+			return resolveMapTarget(entry.value, "", conditions, imports)
+		}
+		star := strings.Index(entry.key, "*")
+		if star < 0 || strings.Count(entry.key, "*") > 1 {
+			continue
+		}
+		prefix, suffix := entry.key[:star], entry.key[star+1:]
+		if len(subpath) >= len(entry.key) && strings.HasPrefix(subpath, prefix) && strings.HasSuffix(subpath, suffix) &&
+			patternKeyLess(bestKey, entry.key) {
+			bestKey, bestMatch = entry.key, subpath[len(prefix):len(subpath)-len(suffix)]
+		}
+	}
+	if bestKey != "" {
+		for _, entry := range entries {
+			if entry.key == bestKey {
+				return resolveMapTarget(entry.value, bestMatch, conditions, imports)
+			}
+		}
+	}
+
+	// Deprecated folder mappings: "./lib/": "./dist/lib/"
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.key, "/") && strings.HasPrefix(subpath, entry.key) {
+			var target string
+			if json.Unmarshal(entry.value, &target) == nil && strings.HasSuffix(target, "/") {
+				return target + subpath[len(entry.key):], true
+			}
+		}
+	}
+	return "", false
+}
+
+// patternKeyLess orders pattern keys the way Node picks the best match: the
+// longer prefix before "*" wins, then the longer key
+func patternKeyLess(current, candidate string) bool {
+	if current == "" {
+		return true
+	}
+	currentStar, candidateStar := strings.Index(current, "*"), strings.Index(candidate, "*")
+	if candidateStar != currentStar {
+		return candidateStar > currentStar
+	}
+	return len(candidate) > len(current)
+}
+
+// resolveMapTarget resolves one exports or imports value: a path, an array
+// of fallbacks, or an object of conditions tried in order. match replaces
+// the "*" of a pattern target.
+func resolveMapTarget(raw json.RawMessage, match string, conditions []string, imports bool) (string, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "", false
+	}
+
+	switch raw[0] {
+	case '"':
+		var target string
+		if json.Unmarshal(raw, &target) != nil {
+			return "", false
+		}
+		if !strings.HasPrefix(target, "./") && !(imports && !strings.HasPrefix(target, "/") && !strings.HasPrefix(target, "../")) {
+			// Exports targets must stay inside the package
+			return "", false
+		}
+		return strings.ReplaceAll(target, "*", match), true
+	case '[':
+		var fallbacks []json.RawMessage
+		if json.Unmarshal(raw, &fallbacks) != nil {
+			return "", false
+		}
+		for _, fallback := range fallbacks {
+			if target, ok := resolveMapTarget(fallback, match, conditions, imports); ok {
+				return target, true
+			}
+		}
+	case '{':
+		entries, _ := orderedEntries(raw)
+		for _, entry := range entries {
+			if entry.key == "default" || containsString(conditions, entry.key) {
+				if target, ok := resolveMapTarget(entry.value, match, conditions, imports); ok {
+					return target, true
+				}
+			}
+		}
+	}
+	// null blocks the subpath
+	return "", false
+}
+
+// orderedEntries returns the members of a JSON object in document order, or
+// false when raw is not an object
+func orderedEntries(raw json.RawMessage) ([]jsonEntry, bool) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+	var entries []jsonEntry
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			break
+		}
+		entries = append(entries, jsonEntry{key: key, value: value})
+	}
+	return entries, true
+}
+
+// applyTypesVersions redirects path through the typesVersions mapping that
+// applies to TypeScriptVersion, if any
+func (p *packageJSON) applyTypesVersions(path string) string {
+	ranges, ok := orderedEntries(p.TypesVersions)
+	if !ok {
+		return path
+	}
+	for _, r := range ranges {
+		if !versionInRange(TypeScriptVersion, r.key) {
+			continue
+		}
+		// The first matching range is the only one used
+		var mapping map[string][]string
+		if json.Unmarshal(r.value, &mapping) != nil {
+			return path
+		}
+		return mapTypesVersionsPath(mapping, path)
+	}
+	return path
+}
+
+// mapTypesVersionsPath applies a typesVersions path mapping, whose keys may
+// contain one "*", picking the key with the longest prefix
+func mapTypesVersionsPath(mapping map[string][]string, path string) string {
+	path = strings.TrimPrefix(path, "./")
+	bestKey, bestMatch := "", ""
+	for key := range mapping {
+		if key == path {
+			bestKey, bestMatch = key, ""
+			break
+		}
+		star := strings.Index(key, "*")
+		if star < 0 {
+			continue
+		}
+		prefix, suffix := key[:star], key[star+1:]
+		if strings.HasPrefix(path, prefix) && strings.HasSuffix(path, suffix) && len(path) >= len(key)-1 &&
+			(bestKey == "" || len(prefix) > strings.Index(bestKey, "*")) {
+			bestKey, bestMatch = key, path[len(prefix):len(path)-len(suffix)]
+		}
+	}
+	if targets := mapping[bestKey]; bestKey != "" && len(targets) > 0 {
+		return strings.Replace(targets[0], "*", bestMatch, 1)
+	}
+	return path
+}
+
+// versionInRange reports whether version satisfies a semver range such as
+// ">=4.1", "<5 >=3.8", "4.x || >=5" or "*"
+func versionInRange(version, spec string) bool {
+	v := parseVersion(version)
+	for _, alternative := range strings.Split(spec, "||") {
+		satisfied := true
+		for _, comparator := range strings.Fields(alternative) {
+			if !versionSatisfies(v, comparator) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+func versionSatisfies(v [3]int, comparator string) bool {
+	op := strings.TrimRight(comparator, "0123456789.x*")
+	bound := comparator[len(op):]
+	if bound == "" || bound == "*" || bound == "x" {
+		return true
+	}
+
+	// Missing or wildcard parts compare equal: 4.x matches any 4.y.z
+	parts := strings.Split(bound, ".")
+	cmp := 0
+	for i := 0; i < 3 && i < len(parts) && cmp == 0; i++ {
+		if parts[i] == "x" || parts[i] == "*" {
+			break
+		}
+		n, _ := strconv.Atoi(parts[i])
+		switch {
+		case v[i] < n:
+			cmp = -1
+		case v[i] > n:
+			cmp = 1
+		}
+	}
+
+	switch op {
+	case ">=", "^", "~":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// parseVersion reads major.minor.patch
+func parseVersion(version string) [3]int {
+	var v [3]int
+	for i, part := range strings.SplitN(version, ".", 3) {
+		v[i], _ = strconv.Atoi(part)
+	}
+	return v
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// PackageTypesFile returns the file that declares the types of the package
+// in pkgDir, relative to it: its "types" or "typings" field, or the types of
+// its "." export
+func PackageTypesFile(pkgDir string) string {
+	data, err := os.ReadFile(filepath.Join(pkgDir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg packageJSON
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+
+	if pkg.Types != "" {
+		return pkg.Types
+	}
+	if pkg.Typings != "" {
+		return pkg.Typings
+	}
+	if len(pkg.Exports) > 0 {
+		if target, ok := resolvePackageMap(pkg.Exports, ".", []string{"types", "import"}, false); ok {
+			ext := filepath.Ext(target)
+			stem := strings.TrimSuffix(target, ext)
+			for _, declExt := range append(declarationExtensions[ext], ext) {
+				if _, err := os.Stat(filepath.Join(pkgDir, filepath.FromSlash(stem+declExt))); err == nil {
+					return stem + declExt
+				}
+			}
+		}
+	}
+	return ""
+}
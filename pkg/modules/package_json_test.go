@@ -0,0 +1,152 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tstypechecker/pkg/symbols"
+)
+
+// writeTree creates files (slash separated, relative to dir) with contents
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+var packageFixture = map[string]string{
+	"package.json":         `{"type": "module", "imports": {"#utils/*": "./src/utils/*.ts", "#dep": "dual"}}`,
+	"src/main.ts":          "",
+	"src/main.cts":         "",
+	"src/utils/strings.ts": "",
+
+	"node_modules/dual/package.json": `{
+		"name": "dual",
+		"main": "./legacy/index.js",
+		"exports": {
+			".": {
+				"import": { "types": "./dist/index.d.mts", "default": "./dist/index.mjs" },
+				"require": { "types": "./dist/index.d.cts", "default": "./dist/index.cjs" }
+			},
+			"./features/*": "./dist/features/*.js",
+			"./features/internal/*": null,
+			"./package.json": "./package.json"
+		}
+	}`,
+	"node_modules/dual/legacy/index.d.ts":           "",
+	"node_modules/dual/dist/index.d.mts":            "",
+	"node_modules/dual/dist/index.d.cts":            "",
+	"node_modules/dual/dist/features/a.d.ts":        "",
+	"node_modules/dual/dist/features/internal/b.ts": "",
+	"node_modules/dual/dist/private.d.ts":           "",
+
+	"node_modules/versioned/package.json": `{
+		"types": "index.d.ts",
+		"typesVersions": { ">=99": { "*": ["ts99/*"] }, ">=4.2": { "*": ["ts4.2/*"] } }
+	}`,
+	"node_modules/versioned/ts4.2/index.d.ts": "",
+	"node_modules/versioned/ts4.2/sub.d.ts":   "",
+
+	"node_modules/@types/babel__core/index.d.ts": "",
+}
+
+func TestResolvePackageExports(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, packageFixture)
+	main := filepath.Join(dir, "src", "main.ts")
+	mainCjs := filepath.Join(dir, "src", "main.cts")
+
+	tests := []struct {
+		resolution string
+		specifier  string
+		fromFile   string
+		want       string
+	}{
+		{ResolutionNode16, "dual", main, "node_modules/dual/dist/index.d.mts"},
+		{ResolutionNode16, "dual", mainCjs, "node_modules/dual/dist/index.d.cts"},
+		{ResolutionBundler, "dual", mainCjs, "node_modules/dual/dist/index.d.mts"},
+		{ResolutionNode10, "dual", main, "node_modules/dual/legacy/index.d.ts"},
+		{ResolutionNode16, "dual/features/a", main, "node_modules/dual/dist/features/a.d.ts"},
+		{ResolutionNode16, "dual/features/internal/b", main, ""},
+		{ResolutionNode16, "dual/dist/private", main, ""},
+		{ResolutionNode10, "dual/dist/private", main, "node_modules/dual/dist/private.d.ts"},
+		{ResolutionNode16, "#utils/strings", main, "src/utils/strings.ts"},
+		{ResolutionNode16, "#dep", main, "node_modules/dual/dist/index.d.mts"},
+		{ResolutionNode10, "#utils/strings", main, ""},
+		{ResolutionNode10, "versioned", main, "node_modules/versioned/ts4.2/index.d.ts"},
+		{ResolutionNode16, "versioned/sub", main, "node_modules/versioned/ts4.2/sub.d.ts"},
+		{ResolutionNode10, "@babel/core", main, "node_modules/@types/babel__core/index.d.ts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resolution+" "+tt.specifier+" from "+filepath.Base(tt.fromFile), func(t *testing.T) {
+			r := NewModuleResolver(dir, symbols.NewSymbolTable())
+			r.SetModuleResolution(tt.resolution, nil)
+
+			got, err := r.resolveModuleSpecifier(tt.specifier, filepath.Dir(tt.fromFile), tt.fromFile)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("expected %s not to resolve, got %s", tt.specifier, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to resolve %s: %v", tt.specifier, err)
+			}
+			if want := filepath.Join(dir, filepath.FromSlash(tt.want)); got != want {
+				t.Errorf("resolved to %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestResolvePackageCustomConditions(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"node_modules/lib/package.json": `{"exports": {"development": "./dev.d.ts", "types": "./prod.d.ts"}}`,
+		"node_modules/lib/dev.d.ts":     "",
+		"node_modules/lib/prod.d.ts":    "",
+	})
+	from := filepath.Join(dir, "main.ts")
+
+	r := NewModuleResolver(dir, symbols.NewSymbolTable())
+	r.SetModuleResolution("bundler", []string{"development"})
+	if got, _ := r.resolveModuleSpecifier("lib", dir, from); filepath.Base(got) != "dev.d.ts" {
+		t.Errorf("custom condition listed first should win, got %s", got)
+	}
+
+	r = NewModuleResolver(dir, symbols.NewSymbolTable())
+	r.SetModuleResolution("bundler", nil)
+	if got, _ := r.resolveModuleSpecifier("lib", dir, from); filepath.Base(got) != "prod.d.ts" {
+		t.Errorf("expected the types condition, got %s", got)
+	}
+}
+
+func TestVersionInRange(t *testing.T) {
+	tests := []struct {
+		spec string
+		want bool
+	}{
+		{"*", true},
+		{">=4.1", true},
+		{">=5.7", false},
+		{"<5 >=3.8", false},
+		{"<6 >=5.0", true},
+		{"4.x || >=5.6", true},
+		{"5.6", true},
+		{"5.5", false},
+	}
+	for _, tt := range tests {
+		if got := versionInRange("5.6.0", tt.spec); got != tt.want {
+			t.Errorf("versionInRange(5.6.0, %q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"tstypechecker/pkg/ast"
@@ -39,16 +40,61 @@ type ModuleResolver struct {
 	// Type roots for .d.ts files (e.g., ["./node_modules/@types", "./types"])
 	typeRoots []string
 
+	// Output directories of referenced projects, mapped back to their sources
+	projectOutputs []ProjectOutput
+
+	// Resolution algorithm for packages: node10, node16 or bundler
+	moduleResolution string
+
+	// Conditions from tsconfig customConditions, matched in exports and imports
+	customConditions []string
+
+	// Parsed package.json files by directory, nil when a directory has none
+	packageCache map[string]*packageJSON
+
+	// Real paths of resolved files, expressed under rootDir when inside it
+	realPathCache map[string]string
+
+	// Resolution tracing (--traceResolution): traceMu serializes traced
+	// resolutions, trace is the one in progress and traces the finished ones
+	traceEnabled bool
+	traceMu      sync.Mutex
+	trace        *ResolutionTrace
+	traces       []ResolutionTrace
+
 	// Cache for file existence checks to reduce os.Stat calls
 	fileCache map[string]bool
 
 	// Cache for modules that could not be resolved
 	notFoundCache map[string]bool
 
+	// In-memory file contents that take precedence over disk (editor buffers)
+	overlays map[string]string
+
+	// Grafo de imports resueltos: archivo importador -> archivos importados
+	imports map[string]map[string]bool
+
+	// Specifiers each file imports that did not resolve
+	unresolved map[string]map[string]bool
+
+	// declare module blocks of the project by file, whether each file is a
+	// module whose blocks augment other modules, and the ambient modules
+	// built from them by name
+	moduleDeclarations map[string][]moduleDeclaration
+	augmentingFiles    map[string]bool
+	ambientModules     map[string]*ResolvedModule
+
 	// Mutex for thread safety
 	mu sync.RWMutex
 }
 
+// ProjectOutput maps the output directory of a referenced project to the
+// source directory it is built from
+type ProjectOutput struct {
+	OutputDir string
+	SourceDir string
+}
+
 // ResolvedModule representa un módulo resuelto
 type ResolvedModule struct {
 	// Ruta absoluta del archivo
@@ -77,6 +123,9 @@ type ResolvedModule struct {
 
 	// Si es un módulo TypeScript
 	IsTypeScript bool
+
+	// Declared without a body (declare module 'name';), so imports are any
+	Untyped bool
 }
 
 // ExportInfo representa información sobre un export
@@ -110,15 +159,25 @@ type ExportInfo struct {
 // NewModuleResolver crea un nuevo resolver de módulos
 func NewModuleResolver(rootDir string, symbolTable *symbols.SymbolTable) *ModuleResolver {
 	return &ModuleResolver{
-		moduleCache:   make(map[string]*ResolvedModule),
-		symbolTable:   symbolTable,
-		rootDir:       rootDir,
-		extensions:    []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".d.ts"},
-		baseUrl:       "",
-		paths:         make(map[string][]string),
-		typeRoots:     []string{"./node_modules/@types", "./types"},
-		fileCache:     make(map[string]bool),
-		notFoundCache: make(map[string]bool),
+		moduleCache:      make(map[string]*ResolvedModule),
+		symbolTable:      symbolTable,
+		rootDir:          rootDir,
+		extensions:       []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".d.ts"},
+		baseUrl:          "",
+		paths:            make(map[string][]string),
+		typeRoots:        []string{"./node_modules/@types", "./types"},
+		moduleResolution: ResolutionNode10,
+		packageCache:     make(map[string]*packageJSON),
+		realPathCache:    make(map[string]string),
+		fileCache:        make(map[string]bool),
+		notFoundCache:    make(map[string]bool),
+		overlays:         make(map[string]string),
+		imports:          make(map[string]map[string]bool),
+		unresolved:       make(map[string]map[string]bool),
+
+		moduleDeclarations: make(map[string][]moduleDeclaration),
+		augmentingFiles:    make(map[string]bool),
+		ambientModules:     make(map[string]*ResolvedModule),
 	}
 }
 
@@ -135,6 +194,65 @@ func (r *ModuleResolver) SetTypeRoots(typeRoots []string) {
 	}
 }
 
+// SetProjectOutputs configures the outputs of the referenced projects. Imports
+// of their declarations resolve to the built files when they exist and to
+// the sources otherwise, so a reference does not have to be built first.
+func (r *ModuleResolver) SetProjectOutputs(outputs []ProjectOutput) {
+	r.projectOutputs = outputs
+}
+
+// SetOverlay registers in-memory content for filePath, used instead of the
+// file on disk until RemoveOverlay is called. Cached modules for the file are
+// invalidated so importers see the new content.
+func (r *ModuleResolver) SetOverlay(filePath string, content string) {
+	r.mu.Lock()
+	r.overlays[filePath] = content
+	r.mu.Unlock()
+	r.InvalidateFile(filePath)
+}
+
+// RemoveOverlay drops the in-memory content for filePath and falls back to disk
+func (r *ModuleResolver) RemoveOverlay(filePath string) {
+	r.mu.Lock()
+	delete(r.overlays, filePath)
+	r.mu.Unlock()
+	r.InvalidateFile(filePath)
+}
+
+// InvalidateFile removes every cached resolution that points at filePath
+func (r *ModuleResolver) InvalidateFile(filePath string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, mod := range r.moduleCache {
+		if key == filePath || (mod != nil && mod.AbsolutePath == filePath) {
+			delete(r.moduleCache, key)
+		}
+	}
+	delete(r.fileCache, filePath)
+	delete(r.realPathCache, filePath)
+	if filepath.Base(filePath) == "package.json" {
+		delete(r.packageCache, filepath.Dir(filePath))
+	}
+	// Its imports are recorded again the next time it is checked
+	delete(r.imports, filePath)
+	delete(r.unresolved, filePath)
+	// A new file may now satisfy imports that previously failed
+	r.notFoundCache = make(map[string]bool)
+	r.ambientModules = make(map[string]*ResolvedModule)
+}
+
+// readFile returns the overlay content for filePath if any, otherwise the file on disk
+func (r *ModuleResolver) readFile(filePath string) ([]byte, error) {
+	r.mu.RLock()
+	content, ok := r.overlays[filePath]
+	r.mu.RUnlock()
+	if ok {
+		return []byte(content), nil
+	}
+	return os.ReadFile(filePath)
+}
+
 // GetRootDir returns the root directory of the project
 func (r *ModuleResolver) GetRootDir() string {
 	return r.rootDir
@@ -148,12 +266,14 @@ func (r *ModuleResolver) ResolveModule(specifier string, fromFile string) (*Reso
 	r.mu.RLock()
 	if cached, exists := r.moduleCache[cacheKey]; exists {
 		r.mu.RUnlock()
+		r.recordImport(fromFile, cached.AbsolutePath)
 		return cached, nil
 	}
 
 	// Check not found cache
 	if r.notFoundCache[cacheKey] {
 		r.mu.RUnlock()
+		r.recordUnresolved(fromFile, specifier)
 		return nil, fmt.Errorf("module not found (cached): %s", specifier)
 	}
 	r.mu.RUnlock()
@@ -166,40 +286,224 @@ func (r *ModuleResolver) ResolveModule(specifier string, fromFile string) (*Reso
 		basePath = filepath.Dir(fromFile)
 	}
 
-	// Resolver según el tipo de especificador
-	var resolvedPath string
-	var err error
+	// An ambient declaration of a package name, declare module 'name' { ... },
+	// takes precedence over the files of the package. Wildcard declarations
+	// such as declare module '*.svg' only apply to imports that do not resolve.
+	var module *ResolvedModule
+	if !r.isRelativePath(specifier) && !r.isAbsolutePath(specifier) {
+		module = r.ambientModule(specifier)
+	}
+	if module == nil {
+		resolvedPath, err := r.resolvePath(specifier, basePath, fromFile)
+		if err != nil {
+			module = r.wildcardModule(specifier)
+		}
+		if err != nil && module == nil {
+			r.mu.Lock()
+			r.notFoundCache[cacheKey] = true
+			r.mu.Unlock()
+			r.recordUnresolved(fromFile, specifier)
+			return nil, fmt.Errorf("failed to resolve module %s: %w", specifier, err)
+		}
+
+		if module == nil {
+			// Cargar y analizar el módulo
+			module, err = r.LoadModule(resolvedPath, specifier)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load module %s: %w", resolvedPath, err)
+			}
+			module = r.augmentedModule(module)
+		}
+	}
+
+	// Cachear el resultado
+	r.mu.Lock()
+	r.moduleCache[cacheKey] = module
+	r.mu.Unlock()
+
+	r.recordImport(fromFile, module.AbsolutePath)
+
+	return module, nil
+}
+
+// ResolvePath returns the file an import of specifier from fromFile resolves
+// to, without loading it or recording the import
+func (r *ModuleResolver) ResolvePath(specifier string, fromFile string) (string, error) {
+	basePath := r.rootDir
+	if fromFile != "" && fromFile != r.rootDir {
+		basePath = filepath.Dir(fromFile)
+	}
+	return r.resolvePath(specifier, basePath, fromFile)
+}
+
+// resolvePath resolves the file a specifier points to, recording the
+// resolution when it is being traced
+func (r *ModuleResolver) resolvePath(specifier string, basePath string, fromFile string) (string, error) {
+	if !r.tracing() {
+		return r.resolveSpecifier(specifier, basePath, fromFile)
+	}
+	endTrace := r.beginTrace(specifier, fromFile)
+	resolvedPath, err := r.resolveSpecifier(specifier, basePath, fromFile)
+	endTrace(resolvedPath)
+	return resolvedPath, err
+}
 
+// resolveSpecifier resuelve la ruta del archivo al que apunta un especificador.
+// Los imports usan la ruta real del archivo como identidad, para que un
+// paquete enlazado (pnpm, workspaces) no se cargue dos veces con dos nombres.
+func (r *ModuleResolver) resolveSpecifier(specifier string, basePath string, fromFile string) (string, error) {
+	resolvedPath, err := r.resolveSpecifierPath(specifier, basePath, fromFile)
+	if err != nil || fromFile == "" {
+		return resolvedPath, err
+	}
+	return r.canonicalPath(resolvedPath), nil
+}
+
+// resolveSpecifierPath resuelve un especificador según su tipo
+func (r *ModuleResolver) resolveSpecifierPath(specifier string, basePath string, fromFile string) (string, error) {
 	if r.isRelativePath(specifier) {
 		// Path relativo: ./foo, ../bar
-		resolvedPath, err = r.resolveRelativePath(specifier, basePath)
-	} else if r.isAbsolutePath(specifier) {
+		defer r.traceRule("relative path")()
+		return r.resolveRelativePath(specifier, basePath)
+	}
+	if r.isAbsolutePath(specifier) {
 		// Path absoluto: /foo/bar
-		resolvedPath, err = r.resolveAbsolutePath(specifier)
-	} else {
-		// Módulo externo o módulo del proyecto
-		resolvedPath, err = r.resolveModuleSpecifier(specifier, basePath)
+		defer r.traceRule("absolute path")()
+		return r.resolveAbsolutePath(specifier)
 	}
+	// Módulo externo o módulo del proyecto
+	return r.resolveModuleSpecifier(specifier, basePath, fromFile)
+}
 
-	if err != nil {
-		r.mu.Lock()
-		r.notFoundCache[cacheKey] = true
-		r.mu.Unlock()
-		return nil, fmt.Errorf("failed to resolve module %s: %w", specifier, err)
+// canonicalPath returns the real path of a resolved file, following the
+// symlinks package managers such as pnpm install. Paths inside the real root
+// directory are expressed under rootDir, so files of the project keep the
+// names they are checked and reported under.
+func (r *ModuleResolver) canonicalPath(path string) string {
+	r.mu.RLock()
+	canonical, cached := r.realPathCache[path]
+	_, overlay := r.overlays[path]
+	r.mu.RUnlock()
+	if cached {
+		return canonical
+	}
+	if overlay {
+		// Editor buffers may not exist on disk
+		return path
 	}
 
-	// Cargar y analizar el módulo
-	module, err := r.LoadModule(resolvedPath, specifier)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load module %s: %w", resolvedPath, err)
+	canonical = path
+	if realPath, err := filepath.EvalSymlinks(path); err == nil && realPath != path {
+		canonical = realPath
+		if realRoot, err := filepath.EvalSymlinks(r.rootDir); err == nil {
+			if rel, ok := pathWithin(realRoot, realPath); ok {
+				canonical = filepath.Join(r.rootDir, rel)
+			}
+		}
+	}
+	if canonical != path {
+		defer r.traceRule("realpath")()
+		r.traceStep(path, "symlink to "+canonical)
 	}
 
-	// Cachear el resultado
 	r.mu.Lock()
-	r.moduleCache[cacheKey] = module
+	r.realPathCache[path] = canonical
 	r.mu.Unlock()
+	return canonical
+}
 
-	return module, nil
+// recordImport registra que fromFile importa el módulo en importedPath
+func (r *ModuleResolver) recordImport(fromFile string, importedPath string) {
+	if fromFile == "" || importedPath == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deps, ok := r.imports[fromFile]
+	if !ok {
+		deps = make(map[string]bool)
+		r.imports[fromFile] = deps
+	}
+	deps[importedPath] = true
+}
+
+// recordUnresolved records that fromFile imports specifier, which does not resolve
+func (r *ModuleResolver) recordUnresolved(fromFile string, specifier string) {
+	if fromFile == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	specifiers, ok := r.unresolved[fromFile]
+	if !ok {
+		specifiers = make(map[string]bool)
+		r.unresolved[fromFile] = specifiers
+	}
+	specifiers[specifier] = true
+}
+
+// UnresolvedImportsOf returns the specifiers imported by filePath that did
+// not resolve
+func (r *ModuleResolver) UnresolvedImportsOf(filePath string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]string, 0, len(r.unresolved[filePath]))
+	for specifier := range r.unresolved[filePath] {
+		result = append(result, specifier)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ImportsOf returns the resolved paths of the modules imported by filePath
+func (r *ModuleResolver) ImportsOf(filePath string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]string, 0, len(r.imports[filePath]))
+	for dep := range r.imports[filePath] {
+		result = append(result, dep)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ImportersOf returns every file that imports filePath, directly or through
+// other modules, according to the imports resolved so far
+func (r *ModuleResolver) ImportersOf(filePath string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Build the reverse graph once per query
+	reverse := make(map[string][]string)
+	for from, deps := range r.imports {
+		for dep := range deps {
+			reverse[dep] = append(reverse[dep], from)
+		}
+	}
+
+	visited := map[string]bool{filePath: true}
+	queue := []string{filePath}
+	var result []string
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, importer := range reverse[current] {
+			if !visited[importer] {
+				visited[importer] = true
+				result = append(result, importer)
+				queue = append(queue, importer)
+			}
+		}
+	}
+
+	sort.Strings(result)
+	return result
 }
 
 // isRelativePath verifica si un especificador es una ruta relativa
@@ -233,7 +537,16 @@ func (r *ModuleResolver) resolveAbsolutePath(specifier string) (string, error) {
 }
 
 // resolveModuleSpecifier resuelve especificadores de módulos (no paths)
-func (r *ModuleResolver) resolveModuleSpecifier(specifier string, basePath string) (string, error) {
+func (r *ModuleResolver) resolveModuleSpecifier(specifier string, basePath string, fromFile string) (string, error) {
+	// Subpath imports (#internal) del package.json del archivo importador
+	if strings.HasPrefix(specifier, "#") {
+		defer r.traceRule("package.json imports")()
+		if importPath, ok := r.resolvePackageImport(specifier, fromFile); ok {
+			return importPath, nil
+		}
+		return "", fmt.Errorf("module not found: %s", specifier)
+	}
+
 	// Primero intentar resolver con path aliases (e.g., @/foo -> src/foo)
 	if aliasPath, err := r.resolvePathAlias(specifier); err == nil {
 		return aliasPath, nil
@@ -251,7 +564,7 @@ func (r *ModuleResolver) resolveModuleSpecifier(specifier string, basePath strin
 	}
 
 	// Finalmente verificar node_modules (incluyendo @types/*)
-	nodeModule, err := r.resolveNodeModule(specifier, basePath)
+	nodeModule, err := r.resolveNodeModule(specifier, basePath, fromFile)
 	if err == nil {
 		return nodeModule, nil
 	}
@@ -261,9 +574,12 @@ func (r *ModuleResolver) resolveModuleSpecifier(specifier string, basePath strin
 
 // resolvePathAlias intenta resolver un especificador usando path aliases de tsconfig
 func (r *ModuleResolver) resolvePathAlias(specifier string) (string, error) {
+	defer r.traceRule("paths")()
 	if len(r.paths) == 0 {
+		r.traceStep("", "no path aliases configured")
 		return "", fmt.Errorf("no path aliases configured")
 	}
+	matched := false
 
 	// Buscar coincidencias en los path aliases
 	for alias, targets := range r.paths {
@@ -274,21 +590,28 @@ func (r *ModuleResolver) resolvePathAlias(specifier string) (string, error) {
 		if strings.HasPrefix(specifier, aliasPrefix) {
 			// Obtener la parte después del prefijo
 			remainder := strings.TrimPrefix(specifier, aliasPrefix)
+			matched = true
 
 			// Intentar cada target configurado para este alias
 			for _, target := range targets {
 				// Reemplazar el * en el target con el remainder
 				targetPath := strings.Replace(target, "*", remainder, 1)
 
-				// Resolver la ruta completa usando baseUrl
+				// Resolver la ruta completa usando baseUrl (los targets con
+				// ${configDir} ya son absolutos)
 				var fullPath string
-				if r.baseUrl != "" {
+				if filepath.IsAbs(targetPath) {
+					fullPath = targetPath
+				} else if filepath.IsAbs(r.baseUrl) {
+					fullPath = filepath.Join(r.baseUrl, targetPath)
+				} else if r.baseUrl != "" {
 					fullPath = filepath.Join(r.rootDir, r.baseUrl, targetPath)
 				} else {
 					fullPath = filepath.Join(r.rootDir, targetPath)
 				}
 
 				// Intentar resolver el archivo
+				restoreRule := r.traceRule("paths '%s' -> '%s'", alias, target)
 				if resolved, err := r.resolveFilePath(fullPath); err == nil {
 					return resolved, nil
 				}
@@ -301,16 +624,22 @@ func (r *ModuleResolver) resolvePathAlias(specifier string) (string, error) {
 						return jsPath, nil
 					}
 				}
+				restoreRule()
 			}
 		}
 	}
 
+	if !matched {
+		r.traceStep("", "no path alias matches the specifier")
+	}
 	return "", fmt.Errorf("no matching path alias found for: %s", specifier)
 }
 
 // resolveFromTypeRoots intenta resolver un módulo desde los typeRoots
 func (r *ModuleResolver) resolveFromTypeRoots(specifier string) (string, error) {
+	defer r.traceRule("typeRoots")()
 	if len(r.typeRoots) == 0 {
+		r.traceStep("", "no typeRoots configured")
 		return "", fmt.Errorf("no typeRoots configured")
 	}
 
@@ -342,8 +671,10 @@ func (r *ModuleResolver) resolveFromTypeRoots(specifier string) (string, error)
 					if debugParserEnabled {
 						fmt.Fprintf(os.Stderr, "DEBUG: Resolved '%s' from typeRoots: %s\n", specifier, found)
 					}
+					r.traceStep(found, traceFound)
 					return found, nil
 				}
+				r.traceStep(path, "no "+specifier+".d.ts in any subdirectory")
 			} else if r.fileExists(path) {
 				if debugParserEnabled {
 					fmt.Fprintf(os.Stderr, "DEBUG: Resolved '%s' from typeRoots: %s\n", specifier, path)
@@ -378,7 +709,22 @@ func (r *ModuleResolver) findFileRecursive(dir string, filename string) string {
 // fileExists checks if a file exists, using a cache to avoid repeated os.Stat calls
 // Returns true only for files, not directories
 func (r *ModuleResolver) fileExists(path string) bool {
+	exists := r.statFile(path)
+	if exists {
+		r.traceStep(path, traceFound)
+	} else {
+		r.traceStep(path, traceNotFound)
+	}
+	return exists
+}
+
+// statFile checks the overlays, the cache and then the filesystem for path
+func (r *ModuleResolver) statFile(path string) bool {
 	r.mu.RLock()
+	if _, ok := r.overlays[path]; ok {
+		r.mu.RUnlock()
+		return true
+	}
 	if exists, ok := r.fileCache[path]; ok {
 		r.mu.RUnlock()
 		// Cache hit - no I/O needed
@@ -414,6 +760,7 @@ func (r *ModuleResolver) resolveFilePath(basePath string) (string, error) {
 	// If it has .js/.jsx/.mjs extension, try TypeScript equivalents
 	if ext == ".js" || ext == ".jsx" || ext == ".mjs" {
 		baseWithoutExt := basePath[:len(basePath)-len(ext)]
+		restoreRule := r.traceDetail(ext + " import rewritten to its TypeScript source")
 
 		// Try .ts first (most common)
 		tsPath := baseWithoutExt + ".ts"
@@ -426,6 +773,7 @@ func (r *ModuleResolver) resolveFilePath(basePath string) (string, error) {
 		if r.fileExists(tsxPath) {
 			return tsxPath, nil
 		}
+		restoreRule()
 	}
 
 	// Try with extensions (.ts, .tsx, .d.ts are most common for TypeScript projects)
@@ -467,11 +815,48 @@ func (r *ModuleResolver) resolveFilePath(basePath string) (string, error) {
 		return indexDts, nil
 	}
 
+	// Outputs of a referenced project that was not built yet
+	if sourcePath, ok := r.projectSourcePath(basePath); ok {
+		defer r.traceDetail("output of a referenced project, redirected to its source")()
+		return r.resolveFilePath(sourcePath)
+	}
+
 	return "", fmt.Errorf("file not found: %s", basePath)
 }
 
+// projectSourcePath maps a path inside the output directory of a referenced
+// project to the corresponding path in its sources
+func (r *ModuleResolver) projectSourcePath(outputPath string) (string, bool) {
+	for _, output := range r.projectOutputs {
+		rel, ok := pathWithin(output.OutputDir, outputPath)
+		if _, sourcesInside := pathWithin(output.OutputDir, output.SourceDir); !ok || sourcesInside {
+			continue
+		}
+		// dist/index.d.ts and dist/index.js are built from src/index.ts
+		sourcePath := filepath.Join(output.SourceDir, rel)
+		for _, ext := range []string{".d.ts", ".js", ".jsx", ".mjs"} {
+			if strings.HasSuffix(sourcePath, ext) {
+				sourcePath = strings.TrimSuffix(sourcePath, ext)
+				break
+			}
+		}
+		return sourcePath, true
+	}
+	return "", false
+}
+
+// pathWithin returns path relative to dir when it is inside dir
+func pathWithin(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
 // resolveProjectModule intenta resolver un módulo del proyecto actual
 func (r *ModuleResolver) resolveProjectModule(specifier string, basePath string) (string, error) {
+	defer r.traceRule("project module")()
 	// Buscar en el proyecto actual - asumimos que los módulos del proyecto
 	// están en el directorio raíz o en subdirectorios específicos
 	possiblePaths := []string{
@@ -490,31 +875,32 @@ func (r *ModuleResolver) resolveProjectModule(specifier string, basePath string)
 }
 
 // resolveNodeModule intenta resolver un módulo de node_modules
-func (r *ModuleResolver) resolveNodeModule(specifier string, basePath string) (string, error) {
+func (r *ModuleResolver) resolveNodeModule(specifier string, basePath string, fromFile string) (string, error) {
 	// Buscar en node_modules comenzando desde basePath y subiendo
+	defer r.traceRule("node_modules")()
 	currentDir := basePath
+	name, subpath := splitPackageSpecifier(specifier)
 
 	for {
-		// Intentar node_modules/specifier
-		nodeModulesPath := filepath.Join(currentDir, "node_modules", specifier)
-		if resolved, err := r.resolveFilePath(nodeModulesPath); err == nil {
-			return resolved, nil
+		if r.trace != nil && !isDir(filepath.Join(currentDir, "node_modules")) {
+			r.traceStep(filepath.Join(currentDir, "node_modules"), "directory does not exist, skipping all lookups in it")
+			if parentDir := filepath.Dir(currentDir); parentDir != currentDir {
+				currentDir = parentDir
+				continue
+			}
+			break
 		}
 
-		// Intentar node_modules/@types/specifier para tipos globales
-		typesPath := filepath.Join(currentDir, "node_modules", "@types", specifier)
-		if resolved, err := r.resolveFilePath(typesPath); err == nil {
+		// Intentar node_modules/pkg con su package.json
+		pkgDir := filepath.Join(currentDir, "node_modules", filepath.FromSlash(name))
+		if resolved, ok := r.resolvePackage(pkgDir, subpath, fromFile); ok {
 			return resolved, nil
 		}
 
-		// Si el specifier tiene un scope (ej: @angular/core), intentar @types/@angular__core
-		if strings.HasPrefix(specifier, "@") {
-			// Reemplazar / con __ para @types
-			typesScoped := strings.Replace(specifier, "/", "__", 1)
-			typesScopedPath := filepath.Join(currentDir, "node_modules", "@types", typesScoped)
-			if resolved, err := r.resolveFilePath(typesScopedPath); err == nil {
-				return resolved, nil
-			}
+		// Intentar node_modules/@types/pkg (@types/scope__pkg para paquetes con scope)
+		typesDir := filepath.Join(currentDir, "node_modules", filepath.FromSlash(typesPackageName(name)))
+		if resolved, ok := r.resolvePackage(typesDir, subpath, fromFile); ok {
+			return resolved, nil
 		}
 
 		// Subir un nivel
@@ -541,7 +927,7 @@ func (r *ModuleResolver) LoadModule(filePath string, specifier string) (module *
 	r.mu.RUnlock()
 
 	// Read file content first to calculate hash
-	content, readErr := os.ReadFile(filePath)
+	content, readErr := r.readFile(filePath)
 	if readErr != nil {
 		return nil, fmt.Errorf("failed to read module %s: %w", filePath, readErr)
 	}
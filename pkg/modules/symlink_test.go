@@ -0,0 +1,66 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tstypechecker/pkg/symbols"
+)
+
+// symlink creates link (relative to dir) pointing at target, skipping the
+// test where symlinks are not available
+func symlink(t *testing.T, dir, target, link string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(link))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.FromSlash(target), path); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+}
+
+func TestResolvePnpmWorkspace(t *testing.T) {
+	// The workspace is reached through a symlink, as with /tmp on macOS
+	realRoot := t.TempDir()
+	writeTree(t, realRoot, map[string]string{
+		"packages/app/src/main.ts":  `import { lib } from "@ws/lib";`,
+		"packages/lib/package.json": `{"name": "@ws/lib", "types": "index.d.ts"}`,
+		"packages/lib/index.d.ts":   `export declare const lib: number;`,
+
+		"node_modules/.pnpm/dep@1.0.0/node_modules/dep/package.json": `{"name": "dep", "types": "index.d.ts"}`,
+		"node_modules/.pnpm/dep@1.0.0/node_modules/dep/index.d.ts":   `export declare const dep: string;`,
+	})
+	root := filepath.Join(t.TempDir(), "workspace")
+	if err := os.Symlink(realRoot, root); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	symlink(t, root, "../../../lib", "packages/app/node_modules/@ws/lib")
+	symlink(t, root, "../../../node_modules/.pnpm/dep@1.0.0/node_modules/dep", "packages/app/node_modules/dep")
+	symlink(t, root, "../../../node_modules/.pnpm/dep@1.0.0/node_modules/dep", "packages/lib/node_modules/dep")
+
+	r := NewModuleResolver(root, symbols.NewSymbolTable())
+	main := filepath.Join(root, "packages", "app", "src", "main.ts")
+
+	lib, err := r.ResolveModule("@ws/lib", main)
+	if err != nil {
+		t.Fatalf("failed to resolve the workspace package: %v", err)
+	}
+	if want := filepath.Join(root, "packages", "lib", "index.d.ts"); lib.AbsolutePath != want {
+		t.Errorf("workspace package resolved to %s, want its path in the workspace %s", lib.AbsolutePath, want)
+	}
+
+	fromApp, err := r.ResolveModule("dep", main)
+	if err != nil {
+		t.Fatalf("failed to resolve dep from the app: %v", err)
+	}
+	fromLib, err := r.ResolveModule("dep", lib.AbsolutePath)
+	if err != nil {
+		t.Fatalf("failed to resolve dep from the workspace package: %v", err)
+	}
+	want := filepath.Join(root, "node_modules", ".pnpm", "dep@1.0.0", "node_modules", "dep", "index.d.ts")
+	if fromApp.AbsolutePath != want || fromApp != fromLib {
+		t.Errorf("dep should load once from the pnpm store, got %s and %s", fromApp.AbsolutePath, fromLib.AbsolutePath)
+	}
+}
@@ -0,0 +1,121 @@
+package modules
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// ResolutionTrace records how one import specifier was resolved: every
+// candidate path that was tried, the rule that produced it and its outcome
+type ResolutionTrace struct {
+	Specifier string      `json:"specifier"`
+	FromFile  string      `json:"fromFile"`
+	Resolved  string      `json:"resolved,omitempty"`
+	Steps     []TraceStep `json:"steps"`
+
+	// Rule producing the candidates being checked
+	rule string
+}
+
+// TraceStep is a candidate considered while resolving a specifier
+type TraceStep struct {
+	Rule      string `json:"rule"`
+	Candidate string `json:"candidate,omitempty"`
+	Result    string `json:"result"`
+}
+
+// Outcomes of a candidate file
+const (
+	traceFound    = "found"
+	traceNotFound = "does not exist"
+)
+
+// SetTraceResolution enables recording a ResolutionTrace for every specifier
+// resolved from then on. Tracing serializes resolution so each trace only
+// holds the candidates of its own specifier.
+func (r *ModuleResolver) SetTraceResolution(enabled bool) {
+	r.mu.Lock()
+	r.traceEnabled = enabled
+	r.mu.Unlock()
+}
+
+// ResolutionTraces returns the recorded traces ordered by importing file and
+// specifier, and clears them
+func (r *ModuleResolver) ResolutionTraces() []ResolutionTrace {
+	r.mu.Lock()
+	traces := r.traces
+	r.traces = nil
+	r.mu.Unlock()
+
+	sort.SliceStable(traces, func(i, j int) bool {
+		if traces[i].FromFile != traces[j].FromFile {
+			return traces[i].FromFile < traces[j].FromFile
+		}
+		return traces[i].Specifier < traces[j].Specifier
+	})
+	return traces
+}
+
+// tracing reports whether resolutions are being traced
+func (r *ModuleResolver) tracing() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.traceEnabled
+}
+
+// beginTrace starts the trace of specifier. The returned function ends it,
+// storing the trace with the path it resolved to. Files the checker loads by
+// path, without an importer, are resolved in turn but not traced.
+func (r *ModuleResolver) beginTrace(specifier, fromFile string) func(resolved string) {
+	r.traceMu.Lock()
+	if fromFile != "" {
+		r.trace = &ResolutionTrace{Specifier: specifier, FromFile: fromFile}
+	}
+	return func(resolved string) {
+		trace := r.trace
+		r.trace = nil
+		r.traceMu.Unlock()
+
+		if trace != nil {
+			trace.Resolved = resolved
+			r.mu.Lock()
+			r.traces = append(r.traces, *trace)
+			r.mu.Unlock()
+		}
+	}
+}
+
+// traceRule names the rule that produces the next candidates and returns a
+// function that restores the previous one
+func (r *ModuleResolver) traceRule(format string, args ...interface{}) func() {
+	if r.trace == nil {
+		return func() {}
+	}
+	previous := r.trace.rule
+	r.trace.rule = fmt.Sprintf(format, args...)
+	return func() { r.trace.rule = previous }
+}
+
+// traceDetail qualifies the current rule with detail, such as the rewriting
+// that produced a candidate, and returns a function that restores it
+func (r *ModuleResolver) traceDetail(detail string) func() {
+	if r.trace == nil {
+		return func() {}
+	}
+	return r.traceRule("%s (%s)", r.trace.rule, detail)
+}
+
+// traceStep records a candidate and why it was accepted or rejected
+func (r *ModuleResolver) traceStep(candidate, result string) {
+	if r.trace == nil {
+		return
+	}
+	r.trace.Steps = append(r.trace.Steps, TraceStep{Rule: r.trace.rule, Candidate: candidate, Result: result})
+}
+
+// isDir reports whether path is a directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
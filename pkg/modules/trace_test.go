@@ -0,0 +1,53 @@
+package modules
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tstypechecker/pkg/symbols"
+)
+
+func TestResolutionTrace(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"src/main.ts":  `import { a } from "@/lib.js";`,
+		"src/lib.ts":   "export const a = 1;",
+		"package.json": `{}`,
+	})
+	main := filepath.Join(dir, "src", "main.ts")
+
+	r := NewModuleResolver(dir, symbols.NewSymbolTable())
+	r.SetPathAliases(".", map[string][]string{"@/*": {"src/*"}})
+	r.SetTraceResolution(true)
+
+	if _, err := r.ResolveModule("@/lib.js", main); err != nil {
+		t.Fatalf("ResolveModule failed: %v", err)
+	}
+	if _, err := r.ResolveModule("./missing", main); err == nil {
+		t.Fatalf("expected ./missing not to resolve")
+	}
+	// Cached resolutions are not traced again
+	_, _ = r.ResolveModule("@/lib.js", main)
+
+	traces := r.ResolutionTraces()
+	if len(traces) != 2 || traces[0].Specifier != "./missing" || traces[1].Specifier != "@/lib.js" {
+		t.Fatalf("expected one trace per specifier sorted by name, got %+v", traces)
+	}
+	if traces[0].Resolved != "" || len(traces[0].Steps) == 0 || traces[0].Steps[0].Rule != "relative path" {
+		t.Errorf("unexpected trace for ./missing: %+v", traces[0])
+	}
+
+	lib := traces[1]
+	if lib.Resolved != filepath.Join(dir, "src", "lib.ts") {
+		t.Errorf("resolved = %s", lib.Resolved)
+	}
+	last := lib.Steps[len(lib.Steps)-1]
+	if last.Result != traceFound || !strings.Contains(last.Rule, "paths '@/*' -> 'src/*'") || !strings.Contains(last.Rule, "rewritten") {
+		t.Errorf("expected the rewritten .ts candidate of the alias to be found last, got %+v", last)
+	}
+
+	if len(r.ResolutionTraces()) != 0 {
+		t.Errorf("ResolutionTraces should clear the recorded traces")
+	}
+}
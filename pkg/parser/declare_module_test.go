@@ -2,6 +2,8 @@ package parser
 
 import (
 	"testing"
+
+	"tstypechecker/pkg/ast"
 )
 
 func TestDeclareModuleStatement(t *testing.T) {
@@ -43,3 +45,59 @@ func TestDeclareModuleStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestDeclareGlobal(t *testing.T) {
+	code := `declare global {
+	interface Window { appVersion: string }
+	var __APP_VERSION__: string;
+}
+export {};`
+	file, err := ParseCode(code, "globals.ts")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	module, ok := file.Body[0].(*ast.ModuleDeclaration)
+	if !ok || !module.Global {
+		t.Fatalf("got %T, want a global module declaration", file.Body[0])
+	}
+	if len(module.Body) != 2 {
+		t.Errorf("got %d statements in the global block, want 2", len(module.Body))
+	}
+}
+
+func TestDeclareModuleBody(t *testing.T) {
+	code := `declare module "*.svg" {
+	const src: string;
+	export default src;
+}
+declare module "untyped-lib";
+declare module "config-lib" {
+	export function load(name: string): boolean;
+}`
+	file, err := ParseCode(code, "env.d.ts")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	if len(file.Body) != 3 {
+		t.Fatalf("got %d statements, want 3", len(file.Body))
+	}
+	tests := []struct {
+		name       string
+		statements int
+		shorthand  bool
+	}{
+		{"*.svg", 2, false},
+		{"untyped-lib", 0, true},
+		{"config-lib", 1, false},
+	}
+	for i, tt := range tests {
+		module, ok := file.Body[i].(*ast.ModuleDeclaration)
+		if !ok {
+			t.Fatalf("statement %d is %T, want a module declaration", i, file.Body[i])
+		}
+		if module.Name != tt.name || module.Shorthand != tt.shorthand || len(module.Body) != tt.statements {
+			t.Errorf("got module %q (shorthand %v) with %d statements, want %q (shorthand %v) with %d",
+				module.Name, module.Shorthand, len(module.Body), tt.name, tt.shorthand, tt.statements)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"testing"
+
+	"tstypechecker/pkg/ast"
+)
+
+func TestForOfAndForInStatements(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantType  string
+		wantLeft  string
+		wantAwait bool
+	}{
+		{
+			name:     "for of with const",
+			code:     `for (const item of items) { console.log(item); }`,
+			wantType: "ForOfStatement",
+			wantLeft: "item",
+		},
+		{
+			name:     "for in with let",
+			code:     `for (let key in obj) {}`,
+			wantType: "ForInStatement",
+			wantLeft: "key",
+		},
+		{
+			name:     "for of with existing variable",
+			code:     `for (item of getItems()) {}`,
+			wantType: "ForOfStatement",
+			wantLeft: "item",
+		},
+		{
+			name:     "for in with existing variable",
+			code:     `for (key in obj) {}`,
+			wantType: "ForInStatement",
+			wantLeft: "key",
+		},
+		{
+			name:      "for await of",
+			code:      `async function f() { for await (const chunk of stream) {} }`,
+			wantType:  "ForOfStatement",
+			wantLeft:  "chunk",
+			wantAwait: true,
+		},
+		{
+			name:     "classic for with 'in' inside identifiers",
+			code:     `for (let i = begin; i < domain; i++) {}`,
+			wantType: "ForStatement",
+			wantLeft: "i",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := ParseCode(tt.code, "test.ts")
+			if err != nil {
+				t.Fatalf("ParseCode() error = %v", err)
+			}
+
+			stmt := file.Body[0]
+			if fn, ok := stmt.(*ast.FunctionDeclaration); ok {
+				stmt = fn.Body.Body[0]
+			}
+			if stmt.Type() != tt.wantType {
+				t.Fatalf("statement type = %s, want %s", stmt.Type(), tt.wantType)
+			}
+
+			var left ast.Node
+			switch s := stmt.(type) {
+			case *ast.ForOfStatement:
+				left = s.Left
+				if s.Right == nil {
+					t.Errorf("iterated expression was not parsed")
+				}
+				if s.Await != tt.wantAwait {
+					t.Errorf("Await = %v, want %v", s.Await, tt.wantAwait)
+				}
+			case *ast.ForInStatement:
+				left = s.Left
+				if s.Right == nil {
+					t.Errorf("iterated expression was not parsed")
+				}
+			case *ast.ForStatement:
+				left = s.Init
+			}
+
+			name := ""
+			switch l := left.(type) {
+			case *ast.VariableDeclaration:
+				name = l.Decls[0].ID.Name
+			case *ast.ExpressionStatement:
+				if id, ok := l.Expression.(*ast.Identifier); ok {
+					name = id.Name
+				}
+			}
+			if name != tt.wantLeft {
+				t.Errorf("loop variable = %q, want %q", name, tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestForOfIterableExpression(t *testing.T) {
+	file, err := ParseCode(`for (const [k, v] of Object.entries(map)) {}`, "test.ts")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+
+	stmt, ok := file.Body[0].(*ast.ForOfStatement)
+	if !ok {
+		t.Fatalf("expected ForOfStatement, got %s", file.Body[0].Type())
+	}
+	if _, ok := stmt.Right.(*ast.CallExpression); !ok {
+		t.Errorf("Right = %T, want *ast.CallExpression", stmt.Right)
+	}
+	decl := stmt.Left.(*ast.VariableDeclaration)
+	if len(decl.Decls) != 2 {
+		t.Errorf("expected 2 declarators from the destructuring pattern, got %d", len(decl.Decls))
+	}
+}
+
+func TestDoWhileStatement(t *testing.T) {
+	file, err := ParseCode("do { i++; } while (i < 3)\nfinish();", "test.ts")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	if len(file.Body) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(file.Body))
+	}
+
+	stmt, ok := file.Body[0].(*ast.DoWhileStatement)
+	if !ok {
+		t.Fatalf("expected DoWhileStatement, got %s", file.Body[0].Type())
+	}
+	if _, ok := stmt.Body.(*ast.BlockStatement); !ok {
+		t.Errorf("Body = %T, want *ast.BlockStatement", stmt.Body)
+	}
+	if _, ok := stmt.Test.(*ast.BinaryExpression); !ok {
+		t.Errorf("Test = %T, want *ast.BinaryExpression", stmt.Test)
+	}
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"testing"
+
+	"tstypechecker/pkg/ast"
+)
+
+func TestParseJSXElement(t *testing.T) {
+	code := `const el = <Layout.Panel title="it's" data-id='x' open {...rest}>
+  Hello {name}!
+  <img src={url} />
+  {/* comment */}
+</Layout.Panel>;`
+
+	file, err := ParseCode(code, "test.tsx")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	decl := file.Body[0].(*ast.VariableDeclaration)
+	elem, ok := decl.Decls[0].Init.(*ast.JSXElement)
+	if !ok {
+		t.Fatalf("expected JSXElement, got %T", decl.Decls[0].Init)
+	}
+
+	if got := JSXTagName(elem.Name); got != "Layout.Panel" {
+		t.Errorf("tag name = %q, want %q", got, "Layout.Panel")
+	}
+	if elem.SelfClosing {
+		t.Error("element should not be self-closing")
+	}
+	if len(elem.Attributes) != 4 {
+		t.Fatalf("expected 4 attributes, got %d", len(elem.Attributes))
+	}
+	if attr := elem.Attributes[0].(*ast.JSXAttribute); attr.Name.Name != "title" || attr.Value.(*ast.Literal).Value != "it's" {
+		t.Errorf("unexpected first attribute %s=%v", attr.Name.Name, attr.Value)
+	}
+	if attr := elem.Attributes[1].(*ast.JSXAttribute); attr.Name.Name != "data-id" {
+		t.Errorf("attribute name = %q, want %q", attr.Name.Name, "data-id")
+	}
+	if attr := elem.Attributes[2].(*ast.JSXAttribute); attr.Value != nil {
+		t.Errorf("bare attribute should have no value, got %v", attr.Value)
+	}
+	if _, ok := elem.Attributes[3].(*ast.JSXSpreadAttribute); !ok {
+		t.Errorf("expected JSXSpreadAttribute, got %T", elem.Attributes[3])
+	}
+
+	var kinds []string
+	for _, child := range elem.Children {
+		kinds = append(kinds, child.Type())
+	}
+	want := []string{"JSXText", "JSXExpressionContainer", "JSXText", "JSXElement", "JSXExpressionContainer"}
+	if len(kinds) != len(want) {
+		t.Fatalf("children = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("child %d = %s, want %s", i, kinds[i], want[i])
+		}
+	}
+	if img := elem.Children[3].(*ast.JSXElement); !img.SelfClosing {
+		t.Error("<img /> should be self-closing")
+	}
+}
+
+func TestParseJSXFragmentAndGenericArrow(t *testing.T) {
+	code := `const list = <><li>a</li><li>b</li></>;
+const id = <T,>(value: T) => value;
+const bound = <T extends object>(value: T) => value;
+const half = <span /> && total / 2;`
+
+	file, err := ParseCode(code, "test.tsx")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	if len(file.Body) != 4 {
+		t.Fatalf("expected 4 statements, got %d", len(file.Body))
+	}
+
+	wantInit := []string{"JSXFragment", "ArrowFunctionExpression", "ArrowFunctionExpression", "BinaryExpression"}
+	for i, want := range wantInit {
+		init := file.Body[i].(*ast.VariableDeclaration).Decls[0].Init
+		if init.Type() != want {
+			t.Errorf("statement %d init = %s, want %s", i, init.Type(), want)
+		}
+	}
+	if fragment := file.Body[0].(*ast.VariableDeclaration).Decls[0].Init.(*ast.JSXFragment); len(fragment.Children) != 2 {
+		t.Errorf("fragment children = %d, want 2", len(fragment.Children))
+	}
+}
+
+func TestParseJSXTypeArguments(t *testing.T) {
+	code := `const el = <Table<Map<string, Array<number>>, (row: Row) => string> rows={rows} />;`
+
+	file, err := ParseCode(code, "test.tsx")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	elem, ok := file.Body[0].(*ast.VariableDeclaration).Decls[0].Init.(*ast.JSXElement)
+	if !ok {
+		t.Fatalf("expected JSXElement, got %T", file.Body[0].(*ast.VariableDeclaration).Decls[0].Init)
+	}
+	if len(elem.TypeArguments) != 2 {
+		t.Fatalf("expected 2 type arguments, got %d", len(elem.TypeArguments))
+	}
+	if ref, ok := elem.TypeArguments[0].(*ast.TypeReference); !ok || ref.Name != "Map" || len(ref.TypeArguments) != 2 {
+		t.Errorf("first type argument = %#v, want Map<string, Array<number>>", elem.TypeArguments[0])
+	}
+	if _, ok := elem.TypeArguments[1].(*ast.FunctionType); !ok {
+		t.Errorf("second type argument = %T, want *ast.FunctionType", elem.TypeArguments[1])
+	}
+	if len(elem.Attributes) != 1 || !elem.SelfClosing {
+		t.Errorf("expected a self-closing element with 1 attribute, got %d attributes", len(elem.Attributes))
+	}
+}
+
+func TestParseJSXOnlyInJSXFiles(t *testing.T) {
+	// <T>expr is a type assertion in .ts files
+	if _, err := ParseCode(`const n = <number>value;`, "test.ts"); err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	if _, err := ParseCode(`const el = <div></span>;`, "test.tsx"); err == nil {
+		t.Fatal("expected an error for a mismatched closing tag")
+	}
+}
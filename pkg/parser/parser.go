@@ -31,6 +31,14 @@ func ParseCode(code, filename string) (*ast.File, error) {
 
 // Simple TypeScript parser implementation
 func parseTypeScript(source, filename string) (*ast.File, error) {
+	var vue *vueScript
+	if isVueFile(filename) {
+		// Only the <script> blocks of a Vue SFC are code
+		script := extractVueScript(source)
+		source = script.source
+		vue = &script
+	}
+
 	p := &parser{
 		source:          source,
 		filename:        filename,
@@ -39,6 +47,10 @@ func parseTypeScript(source, filename string) (*ast.File, error) {
 		column:          1,
 		compilerOptions: make(map[string]string),
 		virtualFiles:    make(map[string]string),
+		jsx:             isJSXFile(filename),
+	}
+	if vue != nil {
+		p.jsx = vue.lang == "tsx" || vue.lang == "jsx"
 	}
 
 	// Pre-process compiler directives
@@ -49,7 +61,19 @@ func parseTypeScript(source, filename string) (*ast.File, error) {
 		fmt.Printf("=== Source after directive extraction ===\n%s\n=== End source ===\n", p.source)
 	}
 
-	return p.parseFile()
+	file, err := p.parseFile()
+	if err != nil {
+		return nil, err
+	}
+	if vue != nil {
+		file.ScriptLang = vue.lang
+		file.ScriptSetup = vue.setup
+	}
+	if len(p.syntaxErrors) > 0 {
+		// The file is partial but usable: broken statements were skipped
+		return file, SyntaxErrors(p.syntaxErrors)
+	}
+	return file, nil
 }
 
 const (
@@ -58,6 +82,16 @@ const (
 	maxNestedDepth      = 1000
 )
 
+// stalled reports whether a list loop consumed nothing since its previous
+// iteration, so it can stop instead of spinning until maxParserIterations
+func (p *parser) stalled(lastPos *int) bool {
+	if p.pos == *lastPos {
+		return true
+	}
+	*lastPos = p.pos
+	return false
+}
+
 // Cache DEBUG_PARSER environment variable to avoid expensive os.Getenv calls
 // This was consuming 84% of CPU time according to profiling
 var debugParserEnabled = os.Getenv("DEBUG_PARSER") == "1"
@@ -70,6 +104,11 @@ type parser struct {
 	column          int
 	compilerOptions map[string]string // Para almacenar directivas como @allowJs, @checkJs, etc.
 	virtualFiles    map[string]string // Para almacenar archivos virtuales definidos con @filename
+	directives      []*ast.CommentDirective
+	syntaxErrors    []*SyntaxError // Diagnostics collected while recovering from syntax errors
+	lineStarts      []int          // Byte offset of each line, built lazily
+	noIn            bool           // 'in' is not a binary operator (left side of for-in)
+	jsx             bool           // JSX syntax is enabled (.tsx and .jsx files)
 }
 
 func (p *parser) parseFile() (*ast.File, error) {
@@ -87,17 +126,17 @@ func (p *parser) parseFile() (*ast.File, error) {
 		if p.pos == lastPos {
 			stuckCount++
 			if stuckCount > 3 {
-				return nil, fmt.Errorf("parser stuck at line %d, col %d, char: '%c' (pos %d)", p.line, p.column, p.source[p.pos], p.pos)
+				// Skip the offending character and keep going
+				p.addSyntaxError(fmt.Sprintf("unexpected character '%c'", p.source[p.pos]), p.currentPos())
+				p.advance()
+				stuckCount = 0
 			}
 		} else {
 			stuckCount = 0
 		}
 		lastPos = p.pos
 
-		stmt, err := p.parseStatement()
-		if err != nil {
-			return nil, err
-		}
+		stmt := p.parseStatementWithRecovery(false)
 		if stmt != nil {
 			statements = append(statements, stmt)
 		}
@@ -107,11 +146,12 @@ func (p *parser) parseFile() (*ast.File, error) {
 	endPos := p.currentPos()
 
 	return &ast.File{
-		Name:     p.filename,
-		Source:   p.source,
-		Body:     statements,
-		Position: startPos,
-		EndPos:   endPos,
+		Name:       p.filename,
+		Source:     p.source,
+		Body:       statements,
+		Directives: p.directives,
+		Position:   startPos,
+		EndPos:     endPos,
 	}, nil
 }
 
@@ -120,6 +160,7 @@ type ParserState struct {
 	Pos    int
 	Line   int
 	Column int
+	Errors int // Syntax errors recorded so far; later ones are dropped on restore
 }
 
 func (p *parser) saveState() ParserState {
@@ -127,6 +168,7 @@ func (p *parser) saveState() ParserState {
 		Pos:    p.pos,
 		Line:   p.line,
 		Column: p.column,
+		Errors: len(p.syntaxErrors),
 	}
 }
 
@@ -134,6 +176,9 @@ func (p *parser) restoreState(state ParserState) {
 	p.pos = state.Pos
 	p.line = state.Line
 	p.column = state.Column
+	if len(p.syntaxErrors) > state.Errors {
+		p.syntaxErrors = p.syntaxErrors[:state.Errors]
+	}
 }
 
 func (p *parser) parseStatement() (ast.Statement, error) {
@@ -198,6 +243,10 @@ func (p *parser) parseStatement() (ast.Statement, error) {
 		return p.parseWhileStatement()
 	}
 
+	if p.matchKeyword("do") {
+		return p.parseDoWhileStatement()
+	}
+
 	if p.matchKeyword("switch") {
 		return p.parseSwitchStatement()
 	}
@@ -666,6 +715,13 @@ func (p *parser) parseVariableDeclaration() (*ast.VariableDeclaration, error) {
 		var init ast.Expression
 		p.skipWhitespaceAndComments()
 
+		// Definite assignment assertion (let x!: T)
+		definite := false
+		if p.match("!") && p.pos+1 < len(p.source) && p.source[p.pos+1] == ':' {
+			p.advance()
+			definite = true
+		}
+
 		// Parse type annotation if present (: Type)
 		if p.match(":") {
 			p.advance()
@@ -690,6 +746,7 @@ func (p *parser) parseVariableDeclaration() (*ast.VariableDeclaration, error) {
 			ID:             id,
 			TypeAnnotation: typeAnnotation,
 			Init:           init,
+			Definite:       definite,
 			Position:       id.Pos(),
 			EndPos:         p.currentPos(),
 		})
@@ -800,52 +857,60 @@ func (p *parser) parseIfStatement() (*ast.IfStatement, error) {
 	}, nil
 }
 
-func (p *parser) parseForStatement() (*ast.ForStatement, error) {
+func (p *parser) parseForStatement() (ast.Statement, error) {
 	startPos := p.currentPos()
 
 	p.consumeKeyword("for")
 	p.skipWhitespaceAndComments()
 
+	// for await (const x of asyncIterable)
+	isAwait := false
+	if p.matchKeyword("await") {
+		p.advanceWord()
+		p.skipWhitespaceAndComments()
+		isAwait = true
+	}
+
 	p.expect("(")
 	p.skipWhitespaceAndComments()
 
-	// Simple approach: Skip the entire for header for for-in/for-of loops
 	// Look ahead to see if we have a for-in or for-of loop
 	headerStart := p.pos
+	headerState := p.saveState()
 
-	// Skip until we find ) to check if there's 'in' or 'of'
-	hasInOrOf := false
+	// Scan the first clause of the header (up to ';' or ')') for 'in' or 'of'
+	loopKeyword := ""
 	depth := 0
 	iterations := 0
-	for !p.isAtEnd() && (depth > 0 || !p.match(")")) && iterations < maxParserIterations {
+	for !p.isAtEnd() && iterations < maxParserIterations {
 		iterations++
-		if p.match("(") {
+		if p.match("(") || p.match("[") || p.match("{") {
 			depth++
-		} else if p.match(")") && depth > 0 {
+		} else if p.match(")") || p.match("]") || p.match("}") {
+			if depth == 0 {
+				break
+			}
 			depth--
+		} else if depth == 0 && p.match(";") {
+			break
 		}
 
-		// Check for 'in' or 'of' keywords (not inside nested parens)
-		if depth == 0 && (p.matchKeyword("in") || p.matchKeyword("of")) {
-			hasInOrOf = true
+		// Only whole words count: 'in' inside 'domain' is not a keyword
+		atWordStart := p.pos == headerStart || !(isLetter(p.source[p.pos-1]) || isDigit(p.source[p.pos-1]))
+		if depth == 0 && atWordStart && (p.matchKeyword("in") || p.matchKeyword("of")) {
+			loopKeyword = p.peekWord()
+			break
 		}
 
 		p.advance()
-
-		if depth == 0 && p.match(")") {
-			break
-		}
 	}
 
 	// Restore position
-	p.pos = headerStart
+	p.restoreState(headerState)
 
-	if hasInOrOf {
-		// Parse for-in/for-of loop properly
+	if loopKeyword != "" {
 		// Example: for (const item of items) { ... }
-		// We need to parse the variable declaration
-
-		var init ast.Node
+		var left ast.Node
 
 		// Check for variable declaration (const, let, var)
 		if p.matchKeyword("const", "let", "var") {
@@ -853,48 +918,35 @@ func (p *parser) parseForStatement() (*ast.ForStatement, error) {
 			if err != nil {
 				return nil, err
 			}
-			init = varDecl
+			left = varDecl
 		} else {
-			// Parse identifier or pattern (for cases like: for (item of items))
+			// Assignment target (for cases like: for (item of items)).
+			// 'in' must not be read as the binary operator here.
+			p.noIn = true
 			expr, err := p.parseExpression()
+			p.noIn = false
 			if err != nil {
 				return nil, err
 			}
-			init = &ast.ExpressionStatement{Expression: expr}
+			left = &ast.ExpressionStatement{Expression: expr, Position: expr.Pos(), EndPos: expr.End()}
 		}
 
 		p.skipWhitespaceAndComments()
-
-		// Skip 'in' or 'of' keyword
-		if p.matchKeyword("in") || p.matchKeyword("of") {
-			p.advanceWord()
+		if !p.matchKeyword(loopKeyword) {
+			return nil, fmt.Errorf("expected '%s' in for-%s loop at %s", loopKeyword, loopKeyword, p.currentPos())
 		}
-
+		p.advanceWord()
 		p.skipWhitespaceAndComments()
 
-		// Skip the iterable expression until )
-		depth = 0
-		maxIterations := 1000
-		iterations := 0
-		for !p.isAtEnd() && (depth > 0 || !p.match(")")) && iterations < maxIterations {
-			iterations++
-			if p.match("(") {
-				depth++
-			} else if p.match(")") && depth > 0 {
-				depth--
-			}
-			p.advance()
-			if depth == 0 && p.match(")") {
-				break
-			}
-		}
-
-		if iterations >= maxIterations {
-			return nil, fmt.Errorf("infinite loop detected in for-in/for-of header")
+		// Parse the iterated expression
+		right, err := p.parseExpression()
+		if err != nil {
+			return nil, err
 		}
 
+		p.skipWhitespaceAndComments()
 		if !p.match(")") {
-			return nil, fmt.Errorf("expected ')' in for-in/for-of loop")
+			return nil, fmt.Errorf("expected ')' in for-%s loop at %s", loopKeyword, p.currentPos())
 		}
 		p.advance()
 		p.skipWhitespaceAndComments()
@@ -905,12 +957,20 @@ func (p *parser) parseForStatement() (*ast.ForStatement, error) {
 			return nil, err
 		}
 
-		// Return with init so the binder can register the variable
-		return &ast.ForStatement{
-			Init:     init,
-			Test:     nil,
-			Update:   nil,
+		if loopKeyword == "in" {
+			return &ast.ForInStatement{
+				Left:     left,
+				Right:    right,
+				Body:     body,
+				Position: startPos,
+				EndPos:   p.currentPos(),
+			}, nil
+		}
+		return &ast.ForOfStatement{
+			Left:     left,
+			Right:    right,
 			Body:     body,
+			Await:    isAwait,
 			Position: startPos,
 			EndPos:   p.currentPos(),
 		}, nil
@@ -1020,6 +1080,48 @@ func (p *parser) parseWhileStatement() (*ast.WhileStatement, error) {
 	}, nil
 }
 
+func (p *parser) parseDoWhileStatement() (*ast.DoWhileStatement, error) {
+	startPos := p.currentPos()
+
+	p.consumeKeyword("do")
+	p.skipWhitespaceAndComments()
+
+	// Parse body
+	body, err := p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipWhitespaceAndComments()
+	p.consumeKeyword("while")
+	p.skipWhitespaceAndComments()
+
+	p.expect("(")
+	p.skipWhitespaceAndComments()
+
+	// Parse test
+	test, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipWhitespaceAndComments()
+	p.expect(")")
+
+	// Optional semicolon
+	p.skipWhitespaceAndComments()
+	if p.match(";") {
+		p.advance()
+	}
+
+	return &ast.DoWhileStatement{
+		Body:     body,
+		Test:     test,
+		Position: startPos,
+		EndPos:   p.currentPos(),
+	}, nil
+}
+
 func (p *parser) parseSwitchStatement() (*ast.SwitchStatement, error) {
 	startPos := p.currentPos()
 
@@ -1066,18 +1168,7 @@ func (p *parser) parseSwitchStatement() (*ast.SwitchStatement, error) {
 		p.skipWhitespaceAndComments()
 
 		var consequent []ast.Statement
-		for !p.isAtEnd() && !p.match("case") && !p.match("default") && !p.match("}") {
-			// Allow break statements
-			if p.matchKeyword("break") {
-				p.consumeKeyword("break")
-				p.skipWhitespaceAndComments()
-				if p.match(";") {
-					p.advance()
-				}
-				p.skipWhitespaceAndComments()
-				break // Exit statement loop for this case
-			}
-
+		for !p.isAtEnd() && !p.matchKeyword("case") && !p.matchKeyword("default") && !p.match("}") {
 			stmt, err := p.parseStatement()
 			if err != nil {
 				return nil, err
@@ -1117,10 +1208,7 @@ func (p *parser) parseBlockStatement() (*ast.BlockStatement, error) {
 	iterations := 0
 	for !p.match("}") && !p.isAtEnd() && iterations < maxParserIterations {
 		iterations++
-		stmt, err := p.parseStatement()
-		if err != nil {
-			return nil, err
-		}
+		stmt := p.parseStatementWithRecovery(true)
 		if stmt != nil {
 			statements = append(statements, stmt)
 		}
@@ -1128,7 +1216,13 @@ func (p *parser) parseBlockStatement() (*ast.BlockStatement, error) {
 	}
 
 	if p.isAtEnd() {
-		return nil, fmt.Errorf("unexpected end of file, expected '}'")
+		// Keep what was parsed so the rest of the file can still be checked
+		p.addSyntaxError("unexpected end of file, expected '}'", p.currentPos())
+		return &ast.BlockStatement{
+			Body:     statements,
+			Position: startPos,
+			EndPos:   p.currentPos(),
+		}, nil
 	}
 
 	p.expect("}")
@@ -1246,6 +1340,18 @@ func (p *parser) parseConditionalExpression() (ast.Expression, error) {
 	return expr, nil
 }
 
+// binaryPrecedence orders the binary operators from loosest to tightest
+var binaryPrecedence = map[string]int{
+	"??": 1,
+	"||": 2,
+	"&&": 3,
+	"==": 4, "!=": 4, "===": 4, "!==": 4,
+	"<": 5, ">": 5, "<=": 5, ">=": 5, "instanceof": 5, "in": 5,
+	"+": 6, "-": 6,
+	"*": 7, "/": 7, "%": 7,
+	"**": 8,
+}
+
 func (p *parser) parseBinaryExpression() (ast.Expression, error) {
 	left, err := p.parseUnaryExpression()
 	if err != nil {
@@ -1258,7 +1364,27 @@ func (p *parser) parseBinaryExpression() (ast.Expression, error) {
 
 	p.skipWhitespaceAndComments()
 
-	// Handle binary operators (simple left-to-right parsing for now)
+	// Operands and operators waiting for an operator of lower precedence, so
+	// that a || b && c groups as a || (b && c)
+	operands := []ast.Expression{left}
+	ends := []ast.Position{p.currentPos()}
+	var operators []string
+	reduce := func(minPrecedence int) {
+		for len(operators) > 0 && binaryPrecedence[operators[len(operators)-1]] >= minPrecedence {
+			n := len(operands)
+			op := operators[len(operators)-1]
+			operators = operators[:len(operators)-1]
+			operands = append(operands[:n-2], &ast.BinaryExpression{
+				Left:     operands[n-2],
+				Operator: op,
+				Right:    operands[n-1],
+				Position: operands[n-2].Pos(),
+				EndPos:   ends[n-1],
+			})
+			ends = append(ends[:n-2], ends[n-1])
+		}
+	}
+
 	iterations := 0
 	for !p.isAtEnd() && iterations < maxParserIterations {
 		iterations++
@@ -1284,11 +1410,14 @@ func (p *parser) parseBinaryExpression() (ast.Expression, error) {
 			op = "||"
 		} else if p.match("??") {
 			op = "??"
-		} else if p.matchKeyword("in") {
+		} else if p.matchKeyword("in") && !p.noIn {
 			op = "in"
 		} else if p.matchKeyword("instanceof") {
 			op = "instanceof"
 		} else if p.matchKeyword("as") {
+			// as binds like a relational operator: a + b as T is (a + b) as T
+			reduce(binaryPrecedence["<"])
+			left = operands[len(operands)-1]
 			startPos := left.Pos()
 			p.advanceWord() // consume 'as'
 			p.skipWhitespaceAndComments()
@@ -1298,12 +1427,14 @@ func (p *parser) parseBinaryExpression() (ast.Expression, error) {
 				return nil, err
 			}
 
-			left = &ast.AsExpression{
+			operands[len(operands)-1] = &ast.AsExpression{
 				Expression:     left,
 				TypeAnnotation: typeNode,
 				Position:       startPos,
 				EndPos:         p.currentPos(),
 			}
+			ends[len(ends)-1] = p.currentPos()
+			p.skipWhitespaceAndComments()
 			continue
 		} else if p.match("+") && p.peek(1) != "+" && p.peek(1) != "=" {
 			op = "+"
@@ -1324,7 +1455,6 @@ func (p *parser) parseBinaryExpression() (ast.Expression, error) {
 		}
 
 		if op != "" {
-			startPos := left.Pos()
 			p.advanceString(len(op))
 			p.skipWhitespaceAndComments()
 
@@ -1338,13 +1468,15 @@ func (p *parser) parseBinaryExpression() (ast.Expression, error) {
 				return nil, fmt.Errorf("expected right operand for operator %s at %d:%d", op, p.line, p.column)
 			}
 
-			left = &ast.BinaryExpression{
-				Left:     left,
-				Operator: op,
-				Right:    right,
-				Position: startPos,
-				EndPos:   p.currentPos(),
+			// ** is right-associative, the others group to the left
+			if op == "**" {
+				reduce(binaryPrecedence[op] + 1)
+			} else {
+				reduce(binaryPrecedence[op])
 			}
+			operators = append(operators, op)
+			operands = append(operands, right)
+			ends = append(ends, p.currentPos())
 
 			p.skipWhitespaceAndComments()
 		} else {
@@ -1352,7 +1484,8 @@ func (p *parser) parseBinaryExpression() (ast.Expression, error) {
 		}
 	}
 
-	return left, nil
+	reduce(0)
+	return operands[0], nil
 }
 
 func (p *parser) parseCallExpression() (ast.Expression, error) {
@@ -1378,7 +1511,7 @@ func (p *parser) parseCallExpression() (ast.Expression, error) {
 		var typeArgs []ast.TypeNode
 		if p.match("<") {
 			// Try to parse as type arguments - if it fails, it might be a comparison
-			savedPos := p.pos
+			savedPos := p.saveState()
 			p.advance()
 			p.skipWhitespaceAndComments()
 
@@ -1406,7 +1539,7 @@ func (p *parser) parseCallExpression() (ast.Expression, error) {
 				p.advance()
 			} else {
 				// Not type arguments, restore position
-				p.pos = savedPos
+				p.restoreState(savedPos)
 				typeArgs = nil
 			}
 		}
@@ -1418,9 +1551,13 @@ func (p *parser) parseCallExpression() (ast.Expression, error) {
 
 			var args []ast.Expression
 
+			lastPos := -1
 			argIterations := 0
 			for !p.match(")") && !p.isAtEnd() && argIterations < maxParserIterations {
 				argIterations++
+				if p.stalled(&lastPos) {
+					break
+				}
 				p.skipWhitespaceAndComments()
 				arg, err := p.parseExpression()
 				if err != nil {
@@ -1899,6 +2036,11 @@ func (p *parser) parsePrimaryExpression() (ast.Expression, error) {
 		p.restoreState(state)
 	}
 
+	// JSX element or fragment; <T>expr type assertions are not allowed in JSX files
+	if p.jsx && p.match("<") && !p.isGenericArrowStart() {
+		return p.parseJSXElement()
+	}
+
 	// Check for generic arrow function: <T>(x: T) => T or <T = unknown>(x: T) => T
 	if p.match("<") {
 		state := p.saveState()
@@ -2293,16 +2435,7 @@ func (p *parser) parseIdentifier() (*ast.Identifier, error) {
 		return nil, fmt.Errorf("expected identifier at %s", startPos)
 	}
 
-	name := ""
-	for !p.isAtEnd() {
-		ch = p.source[p.pos]
-		if (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') || ch == '_' || ch == '$' || ch > 127 {
-			name += string(ch)
-			p.advance()
-		} else {
-			break
-		}
-	}
+	name := p.advanceWord()
 
 	return &ast.Identifier{
 		Name:     name,
@@ -2356,9 +2489,13 @@ func (p *parser) parseIdentifierWithGenerics() (ast.Expression, error) {
 func (p *parser) parseParameterList() ([]*ast.Parameter, error) {
 	var params []*ast.Parameter
 
+	lastPos := -1
 	iterations := 0
 	for !p.match(")") && !p.isAtEnd() && iterations < maxParserIterations {
 		iterations++
+		if p.stalled(&lastPos) {
+			break
+		}
 		p.skipWhitespaceAndComments()
 
 		// Check for rest parameter: ...identifier
@@ -2594,9 +2731,13 @@ func (p *parser) parseImportDeclaration() (*ast.ImportDeclaration, error) {
 		p.advance() // consume '{'
 		p.skipWhitespaceAndComments()
 
+		lastPos := -1
 		iterations := 0
 		for !p.match("}") && !p.isAtEnd() && iterations < maxParserIterations {
 			iterations++
+			if p.stalled(&lastPos) {
+				break
+			}
 			imported, err := p.parseIdentifier()
 			if err != nil {
 				return nil, err
@@ -2671,9 +2812,13 @@ func (p *parser) parseImportDeclaration() (*ast.ImportDeclaration, error) {
 			p.skipWhitespaceAndComments()
 
 			// Parse named imports
+			lastPos := -1
 			iterations := 0
 			for !p.match("}") && !p.isAtEnd() && iterations < maxParserIterations {
 				iterations++
+				if p.stalled(&lastPos) {
+					break
+				}
 
 				// Check for "type" keyword before individual import
 				if p.matchKeyword("type") {
@@ -2889,9 +3034,13 @@ func (p *parser) parseExportDeclaration() (*ast.ExportDeclaration, error) {
 
 		var specifiers []ast.ExportSpecifier
 
+		lastPos := -1
 		iterations := 0
 		for !p.match("}") && !p.isAtEnd() && iterations < maxParserIterations {
 			iterations++
+			if p.stalled(&lastPos) {
+				break
+			}
 			local, err := p.parseIdentifier()
 			if err != nil {
 				return nil, err
@@ -3094,6 +3243,7 @@ func (p *parser) advance() string {
 		return ""
 	}
 
+	start := p.pos
 	r, width := utf8.DecodeRuneInString(p.source[p.pos:])
 	p.pos += width
 
@@ -3108,7 +3258,8 @@ func (p *parser) advance() string {
 		fmt.Fprintf(os.Stderr, "ADVANCE: char='%c' pos=%d col=%d\n", r, p.pos, p.column)
 	}
 
-	return string(r)
+	// Slicing the source avoids allocating a string per character
+	return p.source[start:p.pos]
 }
 
 func (p *parser) peek(offset int) string {
@@ -3226,30 +3377,25 @@ func (p *parser) expect(expected string) {
 }
 
 func (p *parser) advanceWord() string {
-	var word strings.Builder
+	start := p.pos
 	for !p.isAtEnd() {
 		char := p.source[p.pos]
 		// Caracteres ASCII válidos en identificadores
 		if isLetter(char) || isDigit(char) || char == '_' || char == '$' {
-			word.WriteByte(char)
 			p.advance()
 			continue
 		}
 		// Caracteres Unicode (multibyte)
 		if char >= 0x80 {
-			r, size := utf8.DecodeRuneInString(p.source[p.pos:])
+			r, _ := utf8.DecodeRuneInString(p.source[p.pos:])
 			if r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
-				word.WriteRune(r)
-				// Avanzar size bytes
-				for i := 0; i < size; i++ {
-					p.advance()
-				}
+				p.advance()
 				continue
 			}
 		}
 		break
 	}
-	return word.String()
+	return p.source[start:p.pos]
 }
 
 func (p *parser) advanceString(length int) {
@@ -3599,9 +3745,13 @@ func (p *parser) parseArrayLiteral() (*ast.ArrayExpression, error) {
 
 	var elements []ast.Expression
 
+	lastPos := -1
 	iterations := 0
 	for !p.match("]") && !p.isAtEnd() && iterations < maxParserIterations {
 		iterations++
+		if p.stalled(&lastPos) {
+			break
+		}
 
 		if p.match("...") {
 			startPos := p.currentPos()
@@ -3660,9 +3810,13 @@ func (p *parser) parseObjectLiteral() (*ast.ObjectExpression, error) {
 
 	var properties []ast.ObjectPropertyNode
 
+	lastPos := -1
 	iterations := 0
 	for !p.match("}") && !p.isAtEnd() && iterations < maxParserIterations {
 		iterations++
+		if p.stalled(&lastPos) {
+			break
+		}
 		propStartPos := p.currentPos()
 
 		// Check for spread property: ...identifier
@@ -3763,9 +3917,13 @@ func (p *parser) parseObjectLiteral() (*ast.ObjectExpression, error) {
 
 				// Parse parameters using the same logic as parseParameterList
 				var params []*ast.Parameter
+				paramLastPos := -1
 				paramIterations := 0
 				for !p.match(")") && !p.isAtEnd() && paramIterations < maxParserIterations {
 					paramIterations++
+					if p.stalled(&paramLastPos) {
+						break
+					}
 					p.skipWhitespaceAndComments()
 
 					// Check for destructuring patterns
@@ -4011,7 +4169,7 @@ func (p *parser) parseArrowFunction() (*ast.ArrowFunctionExpression, error) {
 	// Check for generic type parameters <T, U>
 	if p.match("<") {
 		// Try to parse type parameters
-		savedPos := p.pos
+		savedPos := p.saveState()
 		p.advance()
 		p.skipWhitespaceAndComments()
 
@@ -4034,7 +4192,7 @@ func (p *parser) parseArrowFunction() (*ast.ArrowFunctionExpression, error) {
 			p.skipWhitespaceAndComments()
 		} else {
 			// Not type parameters, restore position
-			p.pos = savedPos
+			p.restoreState(savedPos)
 		}
 	}
 
@@ -4044,9 +4202,13 @@ func (p *parser) parseArrowFunction() (*ast.ArrowFunctionExpression, error) {
 		p.skipWhitespaceAndComments()
 
 		// Parse parameter list
+		lastPos := -1
 		iterations := 0
 		for !p.match(")") && !p.isAtEnd() && iterations < maxParserIterations {
 			iterations++
+			if p.stalled(&lastPos) {
+				break
+			}
 			p.skipWhitespaceAndComments()
 
 			// Handle object destructuring - extract individual names
@@ -4391,6 +4553,12 @@ func (p *parser) parseTypePrimaryNode() (ast.TypeNode, error) {
 		// Parse simple type reference (e.g., string, number, MyType)
 		typeName := p.advanceWord()
 
+		// Qualified names: JSX.Element, React.ReactNode
+		for p.match(".") && p.pos+1 < len(p.source) && isLetter(p.source[p.pos+1]) {
+			p.advance()
+			typeName += "." + p.advanceWord()
+		}
+
 		// Special handling for 'infer' keyword
 		if typeName == "infer" {
 			p.skipWhitespaceAndComments()
@@ -4448,6 +4616,27 @@ func (p *parser) parseTypePrimaryNode() (ast.TypeNode, error) {
 	return result, nil
 }
 
+// skipTupleElementLabel skips the label of a named tuple element such as
+// [id: number] or [name?: string]; labels do not change the element type
+func (p *parser) skipTupleElementLabel() {
+	if !p.matchIdentifier() {
+		return
+	}
+	state := p.saveState()
+	p.advanceWord()
+	p.skipWhitespaceAndComments()
+	if p.match("?") {
+		p.advance()
+		p.skipWhitespaceAndComments()
+	}
+	if !p.match(":") {
+		p.restoreState(state)
+		return
+	}
+	p.advance()
+	p.skipWhitespaceAndComments()
+}
+
 // parseTypeAnnotationPrimary parses a primary type (identifier, literal, etc.)
 func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 	startPos := p.currentPos()
@@ -4519,7 +4708,7 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 
 	// Mapped type or object type { [K in T]: U } or { key: Type }
 	if p.match("{") {
-		savedPos := p.pos
+		savedPos := p.saveState()
 		p.advance()
 		p.skipWhitespaceAndComments()
 
@@ -4618,7 +4807,7 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 		}
 
 		// Not a mapped type, restore and parse as object type
-		p.pos = savedPos
+		p.restoreState(savedPos)
 		return p.parseObjectTypeLiteral()
 	}
 
@@ -4645,6 +4834,17 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 		}, nil
 	}
 
+	// Negative number literal type (-1)
+	if p.match("-") && p.pos+1 < len(p.source) && isDigit(p.source[p.pos+1]) {
+		p.advance() // consume -
+		num := p.advanceNumber()
+		return &ast.LiteralType{
+			Value:    "-" + num,
+			Position: startPos,
+			EndPos:   p.currentPos(),
+		}, nil
+	}
+
 	// keyof operator
 	if p.match("keyof") {
 		p.advanceString(5)
@@ -4655,9 +4855,18 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 			return nil, err
 		}
 
-		// Return a type reference with "keyof" prefix
-		return &ast.TypeReference{
-			Name:     "keyof " + operand.(*ast.TypeReference).Name,
+		// Return a type reference with "keyof" prefix for a named type
+		if typeRef, ok := operand.(*ast.TypeReference); ok {
+			return &ast.TypeReference{
+				Name:     "keyof " + typeRef.Name,
+				Position: startPos,
+				EndPos:   p.currentPos(),
+			}, nil
+		}
+		// keyof typeof x, keyof { ... }
+		return &ast.TypeOperator{
+			Operator: "keyof",
+			Target:   operand,
 			Position: startPos,
 			EndPos:   p.currentPos(),
 		}, nil
@@ -4702,8 +4911,15 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 		p.advanceWord() // consume typeof
 		p.skipWhitespaceAndComments()
 
-		// Parse the expression (usually an identifier or qualified name)
-		expr, err := p.parseExpression()
+		// Parse the qualified name (x or x.y.z), so that what follows it,
+		// like the > closing type arguments, is not read as an expression
+		var expr ast.Expression
+		var err error
+		if p.matchIdentifier() {
+			expr, err = p.parseTypeQueryName()
+		} else {
+			expr, err = p.parseExpression()
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -4732,7 +4948,7 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 
 	// Function type or parenthesized type
 	if p.match("(") {
-		savedPos := p.pos
+		savedPos := p.saveState()
 		p.advance()
 		p.skipWhitespaceAndComments()
 
@@ -4746,13 +4962,13 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 			p.skipWhitespaceAndComments()
 			if p.match("=") && p.peek(1) == ">" {
 				isFunctionType = true
-				p.pos = savedPos // Reset to start
+				p.restoreState(savedPos) // Reset to start
 			}
 		} else {
 			// Check for rest parameters (...args)
 			if p.match(".") && p.peek(1) == "." && p.peek(2) == "." {
 				isFunctionType = true
-				p.pos = savedPos // Reset to start
+				p.restoreState(savedPos) // Reset to start
 			} else if p.matchIdentifier() {
 				// Check for parameter with type annotation
 				p.advanceWord()
@@ -4761,9 +4977,9 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 					// Looks like function parameters
 					isFunctionType = true
 				}
-				p.pos = savedPos // Reset to start
+				p.restoreState(savedPos) // Reset to start
 			} else {
-				p.pos = savedPos // Reset to start
+				p.restoreState(savedPos) // Reset to start
 			}
 		}
 
@@ -4884,6 +5100,7 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 				restStartPos := p.currentPos()
 				p.advanceString(3)
 				p.skipWhitespaceAndComments()
+				p.skipTupleElementLabel()
 				// Parse the array type after ...
 				elem, err := p.parseTypeAnnotationFull()
 				if err != nil {
@@ -4897,6 +5114,7 @@ func (p *parser) parseTypeAnnotationPrimary() (ast.TypeNode, error) {
 				}
 				elements = append(elements, restElem)
 			} else {
+				p.skipTupleElementLabel()
 				elem, err := p.parseTypeAnnotationFull()
 				if err != nil {
 					return nil, err
@@ -5135,20 +5353,18 @@ func (p *parser) parseClassDeclaration(isAbstract bool) (*ast.ClassDeclaration,
 	iterations := 0
 	for !p.match("}") && !p.isAtEnd() && iterations < maxParserIterations {
 		iterations++
-		member, err := p.parseClassMember()
-		if err != nil {
-			return nil, err
-		}
+		member := p.parseClassMemberWithRecovery()
 		if member != nil {
 			members = append(members, member)
 		}
 		p.skipWhitespaceAndComments()
 	}
 
-	if !p.match("}") {
-		return nil, fmt.Errorf("expected '}' at end of class body")
+	if p.match("}") {
+		p.advance()
+	} else {
+		p.addSyntaxError("expected '}' at end of class body", p.currentPos())
 	}
-	p.advance()
 
 	return &ast.ClassDeclaration{
 		ID:             className,
@@ -5576,9 +5792,23 @@ func (p *parser) parseNewExpression() (*ast.NewExpression, error) {
 	// Parse the constructor (callee)
 	// For new expressions, handle identifiers that may have generics like Map<number, string>
 	var callee ast.Expression
+	var typeArgs []ast.TypeNode
 	var err error
 	if p.matchIdentifier() {
-		callee, err = p.parseIdentifierWithGenerics()
+		state := p.saveState()
+		identPos := p.currentPos()
+		name := p.advanceWord()
+		p.skipWhitespaceAndComments()
+		if p.match("<") {
+			typeArgs = p.tryParseTypeArguments()
+		}
+		if typeArgs != nil {
+			callee = &ast.Identifier{Name: name, Position: identPos, EndPos: p.currentPos()}
+		} else {
+			// Generics that do not parse as types are skipped
+			p.restoreState(state)
+			callee, err = p.parseIdentifierWithGenerics()
+		}
 	} else {
 		callee, err = p.parsePrimaryExpression()
 	}
@@ -5615,13 +5845,46 @@ func (p *parser) parseNewExpression() (*ast.NewExpression, error) {
 	}
 
 	return &ast.NewExpression{
-		Callee:    callee,
-		Arguments: arguments,
-		Position:  startPos,
-		EndPos:    p.currentPos(),
+		Callee:        callee,
+		TypeArguments: typeArgs,
+		Arguments:     arguments,
+		Position:      startPos,
+		EndPos:        p.currentPos(),
 	}, nil
 }
 
+// tryParseTypeArguments parses a type argument list such as <K, V>. It
+// restores the position and returns nil when the text is not one.
+func (p *parser) tryParseTypeArguments() []ast.TypeNode {
+	state := p.saveState()
+	p.advance() // consume '<'
+	p.skipWhitespaceAndComments()
+
+	var typeArgs []ast.TypeNode
+	for {
+		typeArg, err := p.parseTypeAnnotation()
+		if err != nil || typeArg == nil {
+			p.restoreState(state)
+			return nil
+		}
+		typeArgs = append(typeArgs, typeArg)
+
+		p.skipWhitespaceAndComments()
+		if !p.match(",") {
+			break
+		}
+		p.advance()
+		p.skipWhitespaceAndComments()
+	}
+
+	if !p.match(">") {
+		p.restoreState(state)
+		return nil
+	}
+	p.advance()
+	return typeArgs
+}
+
 // parseObjectTypeLiteral parses an object type literal like { name: string; age?: number }
 func (p *parser) parseObjectTypeLiteral() (ast.TypeNode, error) {
 	startPos := p.currentPos()
@@ -5754,6 +6017,37 @@ func (p *parser) parseObjectTypeLiteral() (ast.TypeNode, error) {
 			}
 		}
 
+		// Call signature: { (e: 'change', id: number): void }
+		if p.match("(") {
+			params, err := p.parseCallParameters()
+			if err != nil {
+				return nil, err
+			}
+			p.skipWhitespaceAndComments()
+
+			var returnType ast.TypeNode
+			if p.match(":") {
+				p.advance()
+				p.skipWhitespaceAndComments()
+				returnType, err = p.parseTypeAnnotationFull()
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			members = append(members, &ast.CallSignature{
+				Parameters: params,
+				ReturnType: returnType,
+				Position:   memberStart,
+				EndPos:     p.currentPos(),
+			})
+			p.skipWhitespaceAndComments()
+			if p.match(";") || p.match(",") {
+				p.advance()
+			}
+			continue
+		}
+
 		var key *ast.Identifier
 		if p.matchString() {
 			str, err := p.parseStringLiteral()
@@ -5837,11 +6131,30 @@ func (p *parser) extractCompilerDirectives() {
 	hasFilenameDirective := false
 	cleanedLines := []string{} // Lines without compiler directives
 
+	// Comment directives are kept in the source; their positions are relative
+	// to the lines that end up being parsed
+	var cleanedDirectives []*ast.CommentDirective
+	virtualDirectives := make(map[string][]*ast.CommentDirective)
+	cleanedOffset, currentOffset := 0, 0
+
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
 
-		// Check for compiler directive comments
-		if strings.HasPrefix(trimmed, "// @") {
+		// Check for // @ts-ignore, // @ts-expect-error, // @ts-nocheck, // @ts-check
+		if kind := commentDirectiveKind(trimmed); kind != "" {
+			column := strings.Index(line, "//")
+			if hasFilenameDirective && currentFile != "" {
+				virtualDirectives[currentFile] = append(virtualDirectives[currentFile], &ast.CommentDirective{
+					Kind:     kind,
+					Position: ast.Position{Line: len(currentContent) + 1, Column: column + 1, Offset: currentOffset + column},
+				})
+			} else {
+				cleanedDirectives = append(cleanedDirectives, &ast.CommentDirective{
+					Kind:     kind,
+					Position: ast.Position{Line: len(cleanedLines) + 1, Column: column + 1, Offset: cleanedOffset + column},
+				})
+			}
+		} else if strings.HasPrefix(trimmed, "// @") {
 			// Extract directive name and value
 			directive := strings.TrimPrefix(trimmed, "// @")
 			parts := strings.SplitN(directive, ":", 2)
@@ -5859,6 +6172,7 @@ func (p *parser) extractCompilerDirectives() {
 					// Start new file
 					currentFile = value
 					currentContent = []string{}
+					currentOffset = 0
 				} else {
 					p.compilerOptions[key] = value
 				}
@@ -5870,12 +6184,16 @@ func (p *parser) extractCompilerDirectives() {
 		// If we're collecting content for a virtual file
 		if hasFilenameDirective && currentFile != "" {
 			currentContent = append(currentContent, line)
+			currentOffset += len(line) + 1
 		} else {
 			// No @filename directive yet, accumulate cleaned lines
 			cleanedLines = append(cleanedLines, line)
+			cleanedOffset += len(line) + 1
 		}
 	}
 
+	p.directives = cleanedDirectives
+
 	// Save the last file if any
 	if currentFile != "" {
 		p.virtualFiles[currentFile] = strings.Join(currentContent, "\n")
@@ -5883,11 +6201,13 @@ func (p *parser) extractCompilerDirectives() {
 		// For multi-file tests, parse only the first .d.ts file (declarations)
 		// If no .d.ts, parse the first .ts file
 		var targetContent string
+		var targetDirectives []*ast.CommentDirective
 		foundDts := false
 
 		for filename, content := range p.virtualFiles {
 			if strings.HasSuffix(filename, ".d.ts") {
 				targetContent = content
+				targetDirectives = virtualDirectives[filename]
 				foundDts = true
 				break
 			}
@@ -5897,6 +6217,7 @@ func (p *parser) extractCompilerDirectives() {
 			for filename, content := range p.virtualFiles {
 				if strings.HasSuffix(filename, ".ts") {
 					targetContent = content
+					targetDirectives = virtualDirectives[filename]
 					break
 				}
 			}
@@ -5904,6 +6225,7 @@ func (p *parser) extractCompilerDirectives() {
 
 		if targetContent != "" {
 			p.source = targetContent
+			p.directives = targetDirectives
 			p.pos = 0
 			p.line = 1
 			p.column = 1
@@ -5917,3 +6239,28 @@ func (p *parser) extractCompilerDirectives() {
 		p.column = 1
 	}
 }
+
+// commentDirectiveKind returns the directive kind of a trimmed line such as
+// "// @ts-expect-error: reason", or "" if the line is not a comment directive
+func commentDirectiveKind(trimmed string) string {
+	if !strings.HasPrefix(trimmed, "//") {
+		return ""
+	}
+	text := strings.TrimSpace(strings.TrimPrefix(trimmed, "//"))
+	if !strings.HasPrefix(text, "@") {
+		return ""
+	}
+	text = text[1:]
+
+	for _, kind := range []string{ast.DirectiveExpectError, ast.DirectiveIgnore, ast.DirectiveNoCheck, ast.DirectiveCheck} {
+		if !strings.HasPrefix(text, kind) {
+			continue
+		}
+		// The directive may be followed by an explanation, but not by more name characters
+		rest := text[len(kind):]
+		if rest == "" || !(isLetter(rest[0]) || isDigit(rest[0]) || rest[0] == '-') {
+			return kind
+		}
+	}
+	return ""
+}
@@ -0,0 +1,402 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"tstypechecker/pkg/ast"
+)
+
+// isJSXFile reports whether JSX syntax is enabled for filename
+func isJSXFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".tsx" || ext == ".jsx"
+}
+
+// isGenericArrowStart reports whether the '<' at the current position opens
+// the type parameters of an arrow function rather than a JSX element. In .tsx
+// files only <T,>(...) and <T extends U>(...) are type parameters.
+func (p *parser) isGenericArrowStart() bool {
+	state := p.saveState()
+	defer p.restoreState(state)
+
+	p.advance() // consume <
+	p.skipWhitespaceAndComments()
+	if !p.matchIdentifier() {
+		return false
+	}
+	p.advanceWord()
+	p.skipWhitespaceAndComments()
+
+	if p.match(",") {
+		return true
+	}
+	if p.matchKeyword("extends") {
+		p.advanceWord()
+		p.skipWhitespaceAndComments()
+		// <T extends={...}> and <T extends> are JSX attributes
+		return !p.match("=") && !p.match(">") && !p.match("/")
+	}
+	return false
+}
+
+// parseJSXElement parses a JSX element or fragment starting at '<'
+func (p *parser) parseJSXElement() (ast.Expression, error) {
+	startPos := p.currentPos()
+	p.advance() // consume <
+	p.skipWhitespaceAndComments()
+
+	// Fragment: <>children</>
+	if p.match(">") {
+		p.advance()
+		children, err := p.parseJSXChildren()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.parseJSXClosingTag(nil); err != nil {
+			return nil, err
+		}
+		return &ast.JSXFragment{
+			Children: children,
+			Position: startPos,
+			EndPos:   p.currentPos(),
+		}, nil
+	}
+
+	name, err := p.parseJSXElementName()
+	if err != nil {
+		return nil, err
+	}
+	p.skipWhitespaceAndComments()
+
+	// Type arguments of a generic component: <Select<Option> ... />
+	var typeArgs []ast.TypeNode
+	if p.match("<") {
+		typeArgs, err = p.parseJSXTypeArguments()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	attributes, err := p.parseJSXAttributes()
+	if err != nil {
+		return nil, err
+	}
+
+	element := &ast.JSXElement{
+		Name:          name,
+		TypeArguments: typeArgs,
+		Attributes:    attributes,
+		Position:      startPos,
+	}
+
+	if p.match("/>") {
+		p.advanceString(2)
+		element.SelfClosing = true
+		element.EndPos = p.currentPos()
+		return element, nil
+	}
+	if !p.match(">") {
+		return nil, fmt.Errorf("expected '>' to close JSX opening tag at %s", p.currentPos())
+	}
+	p.advance()
+
+	children, err := p.parseJSXChildren()
+	if err != nil {
+		return nil, err
+	}
+	element.Children = children
+
+	if err := p.parseJSXClosingTag(name); err != nil {
+		return nil, err
+	}
+	element.EndPos = p.currentPos()
+	return element, nil
+}
+
+// parseJSXTypeArguments parses the type arguments after the name of an
+// element, starting at '<'
+func (p *parser) parseJSXTypeArguments() ([]ast.TypeNode, error) {
+	p.advance() // consume <
+	var typeArgs []ast.TypeNode
+	for {
+		p.skipWhitespaceAndComments()
+		typeArg, err := p.parseTypeAnnotation()
+		if err != nil {
+			return nil, err
+		}
+		typeArgs = append(typeArgs, typeArg)
+		p.skipWhitespaceAndComments()
+		if !p.match(",") {
+			break
+		}
+		p.advance()
+	}
+	if !p.match(">") {
+		return nil, fmt.Errorf("expected '>' after JSX type arguments at %s", p.currentPos())
+	}
+	p.advance()
+	p.skipWhitespaceAndComments()
+	return typeArgs, nil
+}
+
+// parseJSXIdentifier parses a JSX name, which unlike an identifier may contain
+// '-' (data-id, my-element) and ':' (xlink:href)
+func (p *parser) parseJSXIdentifier() (*ast.Identifier, error) {
+	startPos := p.currentPos()
+	start := p.pos
+	if p.isAtEnd() || !isLetter(p.source[p.pos]) {
+		return nil, fmt.Errorf("expected JSX identifier at %s", startPos)
+	}
+	for !p.isAtEnd() {
+		ch := p.source[p.pos]
+		if !isLetter(ch) && !isDigit(ch) && ch != '-' && ch != ':' {
+			break
+		}
+		p.advance()
+	}
+	return &ast.Identifier{
+		Name:     p.source[start:p.pos],
+		Position: startPos,
+		EndPos:   p.currentPos(),
+	}, nil
+}
+
+// parseJSXElementName parses a tag name: div, my-element or Foo.Bar.Baz
+func (p *parser) parseJSXElementName() (ast.Expression, error) {
+	id, err := p.parseJSXIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	var name ast.Expression = id
+	for p.match(".") {
+		p.advance()
+		property, err := p.parseJSXIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		name = &ast.MemberExpression{
+			Object:   name,
+			Property: property,
+			Position: id.Position,
+			EndPos:   p.currentPos(),
+		}
+	}
+	return name, nil
+}
+
+// JSXTagName returns the source form of a JSX element name ("" for a fragment)
+func JSXTagName(name ast.Expression) string {
+	switch n := name.(type) {
+	case *ast.Identifier:
+		return n.Name
+	case *ast.MemberExpression:
+		if property, ok := n.Property.(*ast.Identifier); ok {
+			return JSXTagName(n.Object) + "." + property.Name
+		}
+	}
+	return ""
+}
+
+// parseJSXAttributes parses the attributes of an opening tag up to '>' or '/>'
+func (p *parser) parseJSXAttributes() ([]ast.JSXAttributeNode, error) {
+	var attributes []ast.JSXAttributeNode
+
+	lastPos := -1
+	for !p.isAtEnd() && !p.match(">") && !p.match("/>") {
+		if p.stalled(&lastPos) {
+			break
+		}
+		attrStart := p.currentPos()
+
+		// Spread attribute: {...props}
+		if p.match("{") {
+			p.advance()
+			p.skipWhitespaceAndComments()
+			if !p.match("...") {
+				return nil, fmt.Errorf("expected '...' in JSX spread attribute at %s", p.currentPos())
+			}
+			p.advanceString(3)
+			argument, err := p.parseAssignmentExpression()
+			if err != nil {
+				return nil, err
+			}
+			p.skipWhitespaceAndComments()
+			if !p.match("}") {
+				return nil, fmt.Errorf("expected '}' after JSX spread attribute at %s", p.currentPos())
+			}
+			p.advance()
+			attributes = append(attributes, &ast.JSXSpreadAttribute{
+				Argument: argument,
+				Position: attrStart,
+				EndPos:   p.currentPos(),
+			})
+			p.skipWhitespaceAndComments()
+			continue
+		}
+
+		name, err := p.parseJSXIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		p.skipWhitespaceAndComments()
+
+		attribute := &ast.JSXAttribute{Name: name, Position: attrStart}
+		if p.match("=") {
+			p.advance()
+			p.skipWhitespaceAndComments()
+			value, err := p.parseJSXAttributeValue()
+			if err != nil {
+				return nil, err
+			}
+			attribute.Value = value
+		}
+		attribute.EndPos = p.currentPos()
+		attributes = append(attributes, attribute)
+		p.skipWhitespaceAndComments()
+	}
+
+	if p.isAtEnd() {
+		return nil, fmt.Errorf("unterminated JSX opening tag at %s", p.currentPos())
+	}
+	return attributes, nil
+}
+
+// parseJSXAttributeValue parses "text", 'text', {expr} or a nested element
+func (p *parser) parseJSXAttributeValue() (ast.Expression, error) {
+	startPos := p.currentPos()
+
+	switch {
+	case p.match("\"") || p.match("'"):
+		// JSX strings have no escapes and may span lines
+		quote := p.source[p.pos]
+		p.advance()
+		start := p.pos
+		for !p.isAtEnd() && p.source[p.pos] != quote {
+			p.advance()
+		}
+		if p.isAtEnd() {
+			return nil, fmt.Errorf("unterminated string in JSX attribute at %s", startPos)
+		}
+		value := p.source[start:p.pos]
+		p.advance()
+		return &ast.Literal{
+			Value:    value,
+			Raw:      p.source[startPos.Offset:p.pos],
+			Position: startPos,
+			EndPos:   p.currentPos(),
+		}, nil
+	case p.match("{"):
+		return p.parseJSXExpressionContainer()
+	case p.match("<"):
+		return p.parseJSXElement()
+	}
+	return nil, fmt.Errorf("expected JSX attribute value at %s", startPos)
+}
+
+// parseJSXExpressionContainer parses {expr}; the braces may hold only a comment
+func (p *parser) parseJSXExpressionContainer() (*ast.JSXExpressionContainer, error) {
+	startPos := p.currentPos()
+	p.advance() // consume {
+	p.skipWhitespaceAndComments()
+
+	container := &ast.JSXExpressionContainer{Position: startPos}
+	if !p.match("}") {
+		spreadStart := p.currentPos()
+		spread := p.match("...")
+		if spread {
+			p.advanceString(3)
+		}
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if spread {
+			// Spread children: {...items}
+			expr = &ast.SpreadElement{Argument: expr, Position: spreadStart, EndPos: p.currentPos()}
+		}
+		container.Expression = expr
+		p.skipWhitespaceAndComments()
+		if !p.match("}") {
+			return nil, fmt.Errorf("expected '}' to close JSX expression at %s", p.currentPos())
+		}
+	}
+	p.advance() // consume }
+	container.EndPos = p.currentPos()
+	return container, nil
+}
+
+// parseJSXChildren parses text, {expressions} and elements up to a closing tag
+func (p *parser) parseJSXChildren() ([]ast.Expression, error) {
+	var children []ast.Expression
+
+	for {
+		if p.isAtEnd() {
+			return nil, fmt.Errorf("unterminated JSX contents at %s", p.currentPos())
+		}
+
+		switch {
+		case p.match("</"):
+			return children, nil
+		case p.match("<"):
+			child, err := p.parseJSXElement()
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		case p.match("{"):
+			child, err := p.parseJSXExpressionContainer()
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		default:
+			startPos := p.currentPos()
+			for !p.isAtEnd() && p.source[p.pos] != '<' && p.source[p.pos] != '{' {
+				p.advance()
+			}
+			text := p.source[startPos.Offset:p.pos]
+			// Whitespace-only text spanning lines is formatting, not content
+			if strings.TrimSpace(text) == "" && strings.Contains(text, "\n") {
+				continue
+			}
+			children = append(children, &ast.JSXText{
+				Value:    text,
+				Position: startPos,
+				EndPos:   p.currentPos(),
+			})
+		}
+	}
+}
+
+// parseJSXClosingTag parses </name> (</> when name is nil) and checks that it
+// matches the opening tag
+func (p *parser) parseJSXClosingTag(name ast.Expression) error {
+	closePos := p.currentPos()
+	p.advanceString(2) // consume </
+	p.skipWhitespaceAndComments()
+
+	closing := ""
+	if !p.match(">") {
+		closingName, err := p.parseJSXElementName()
+		if err != nil {
+			return err
+		}
+		closing = JSXTagName(closingName)
+		p.skipWhitespaceAndComments()
+	}
+
+	if opening := JSXTagName(name); closing != opening {
+		if name == nil {
+			return fmt.Errorf("expected corresponding closing tag for JSX fragment at %s", closePos)
+		}
+		return fmt.Errorf("expected corresponding JSX closing tag for '%s' at %s", opening, closePos)
+	}
+	if !p.match(">") {
+		return fmt.Errorf("expected '>' to close JSX closing tag at %s", p.currentPos())
+	}
+	p.advance()
+	return nil
+}
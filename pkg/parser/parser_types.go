@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 	"tstypechecker/pkg/ast"
 )
 
@@ -166,7 +167,7 @@ func (p *parser) parseTypeAnnotationUnary() (ast.TypeNode, error) {
 	// Check for conditional type: T extends U ? X : Y or T extends infer U ? X : Y
 	// Only parse as conditional if we see "extends" followed by "?" or "infer"
 	if p.match("extends") {
-		savedPos := p.pos
+		savedPos := p.saveState()
 		p.advanceString(7)
 		p.skipWhitespaceAndComments()
 
@@ -181,14 +182,14 @@ func (p *parser) parseTypeAnnotationUnary() (ast.TypeNode, error) {
 
 			inferredType, err = p.parseIdentifier()
 			if err != nil {
-				p.pos = savedPos
+				p.restoreState(savedPos)
 				return firstType, nil
 			}
 			// When infer is present, there is no extends type
 		} else {
 			extendsType, err = p.parseTypeAnnotationPrimary()
 			if err != nil {
-				p.pos = savedPos
+				p.restoreState(savedPos)
 				return firstType, nil
 			}
 		}
@@ -238,7 +239,7 @@ func (p *parser) parseTypeAnnotationUnary() (ast.TypeNode, error) {
 			return res, nil
 		} else {
 			// Not a conditional type, restore position
-			p.pos = savedPos
+			p.restoreState(savedPos)
 		}
 	}
 
@@ -379,6 +380,28 @@ func (p *parser) parseDeclareStatement() (ast.Statement, error) {
 	p.advanceString(7)
 	p.skipWhitespaceAndComments()
 
+	// declare global { ... } augments the global scope from a module
+	if p.matchKeyword("global") {
+		p.advanceWord() // consume 'global'
+		p.skipWhitespaceAndComments()
+
+		if !p.match("{") {
+			return nil, fmt.Errorf("expected '{' in global declaration")
+		}
+		body, err := p.parseBlockStatement()
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.ModuleDeclaration{
+			Name:     "global",
+			Global:   true,
+			Body:     body.Body,
+			Position: startPos,
+			EndPos:   p.currentPos(),
+		}, nil
+	}
+
 	// Check if this is a module declaration: declare module 'name' { ... }
 	if p.matchKeyword("module") {
 		p.advanceWord() // consume 'module'
@@ -406,12 +429,33 @@ func (p *parser) parseDeclareStatement() (ast.Statement, error) {
 
 		p.skipWhitespaceAndComments()
 
-		// Parse module body
+		// declare module 'name'; declares a module without giving its types
 		if !p.match("{") {
-			return nil, fmt.Errorf("expected '{' in module declaration")
+			if p.match(";") {
+				p.advance()
+			}
+			return &ast.ModuleDeclaration{
+				Name:      moduleName,
+				Shorthand: true,
+				Position:  startPos,
+				EndPos:    p.currentPos(),
+			}, nil
 		}
 
-		// Skip the entire module body
+		// Parse the module body, or skip it when it uses syntax the parser
+		// does not support
+		state := p.saveState()
+		body, err := p.parseBlockStatement()
+		if err == nil && len(p.syntaxErrors) == state.Errors {
+			return &ast.ModuleDeclaration{
+				Name:     moduleName,
+				Body:     body.Body,
+				Position: startPos,
+				EndPos:   p.currentPos(),
+			}, nil
+		}
+		p.restoreState(state)
+
 		depth := 1
 		p.advance() // consume '{'
 		for depth > 0 && !p.isAtEnd() {
@@ -425,14 +469,18 @@ func (p *parser) parseDeclareStatement() (ast.Statement, error) {
 
 		return &ast.ModuleDeclaration{
 			Name:     moduleName,
-			Body:     []ast.Statement{}, // For now, we just skip the body
+			Body:     []ast.Statement{},
 			Position: startPos,
 			EndPos:   p.currentPos(),
 		}, nil
 	}
 
 	// Parse the actual statement (for other declare statements)
-	return p.parseStatement()
+	stmt, err := p.parseStatement()
+	if decl, ok := stmt.(*ast.VariableDeclaration); ok {
+		decl.Declare = true
+	}
+	return stmt, err
 }
 
 func (p *parser) parseTypeAliasDeclaration() (ast.Declaration, error) {
@@ -687,8 +735,10 @@ func (p *parser) parseInterfaceDeclaration() (ast.Declaration, error) {
 		}
 
 		// Check for index signature: [key: Type]: Type
+		var computed *ast.Identifier
 		if p.match("[") {
 			indexStart := p.currentPos()
+			keyStart := p.pos
 			p.advance() // consume [
 			p.skipWhitespaceAndComments()
 
@@ -742,30 +792,20 @@ func (p *parser) parseInterfaceDeclaration() (ast.Declaration, error) {
 				}
 				p.skipWhitespaceAndComments()
 				continue
-			} else {
-				// Not an index signature, skip it
-				depth := 1
-				for depth > 0 && !p.isAtEnd() {
-					if p.match("[") {
-						depth++
-					} else if p.match("]") {
-						depth--
-					}
-					p.advance()
-				}
-				p.skipWhitespaceAndComments()
-				if p.match(":") {
-					p.advance()
-					p.skipWhitespaceAndComments()
-					p.skipTypeAnnotation()
-				}
-				p.skipWhitespaceAndComments()
-				if p.match(";") || p.match(",") {
-					p.advance()
+			}
+			// A computed name such as [Symbol.iterator], kept as written
+			depth := 1
+			for depth > 0 && !p.isAtEnd() {
+				if p.match("[") {
+					depth++
+				} else if p.match("]") {
+					depth--
 				}
-				p.skipWhitespaceAndComments()
-				continue
+				p.advance()
 			}
+			key := strings.Join(strings.Fields(p.source[keyStart:p.pos]), "")
+			computed = &ast.Identifier{Name: key, Position: indexStart, EndPos: p.currentPos()}
+			p.skipWhitespaceAndComments()
 		}
 
 		// Check for readonly modifier
@@ -777,13 +817,13 @@ func (p *parser) parseInterfaceDeclaration() (ast.Declaration, error) {
 		}
 
 		// Regular property or method
-		if p.matchIdentifier() || p.matchString() {
+		if computed != nil || p.matchIdentifier() || p.matchString() {
 			// Parse property name
-			var name *ast.Identifier
-			if p.matchString() {
+			name := computed
+			if name == nil && p.matchString() {
 				str, _ := p.parseStringLiteral()
 				name = &ast.Identifier{Name: str, Position: memberStart, EndPos: p.currentPos()}
-			} else {
+			} else if name == nil {
 				name, _ = p.parseIdentifier()
 			}
 
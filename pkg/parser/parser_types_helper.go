@@ -17,9 +17,18 @@ func (p *parser) parseTypeParameters() ([]ast.TypeNode, error) {
 	var typeParams []ast.TypeNode
 
 	for !p.match(">") && !p.isAtEnd() {
-		// Parse type parameter: T extends U = V
+		// Parse type parameter: const T extends U = V
+		startPos := p.currentPos()
 
-		// 1. Name
+		// 1. const modifier
+		isConst := false
+		if p.matchKeyword("const") {
+			p.advanceWord()
+			p.skipWhitespaceAndComments()
+			isConst = true
+		}
+
+		// 2. Name
 		id, err := p.parseIdentifier()
 		if err != nil {
 			return nil, err
@@ -27,12 +36,13 @@ func (p *parser) parseTypeParameters() ([]ast.TypeNode, error) {
 
 		typeParam := &ast.TypeParameter{
 			Name:     id,
-			Position: id.Pos(),
+			Const:    isConst,
+			Position: startPos,
 		}
 
 		p.skipWhitespaceAndComments()
 
-		// 2. Constraint (extends)
+		// 3. Constraint (extends)
 		if p.match("extends") {
 			p.advanceString(7)
 			p.skipWhitespaceAndComments()
@@ -44,7 +54,7 @@ func (p *parser) parseTypeParameters() ([]ast.TypeNode, error) {
 			p.skipWhitespaceAndComments()
 		}
 
-		// 3. Default ( = )
+		// 4. Default ( = )
 		if p.match("=") {
 			p.advance()
 			p.skipWhitespaceAndComments()
@@ -74,3 +84,28 @@ func (p *parser) parseTypeParameters() ([]ast.TypeNode, error) {
 
 	return typeParams, nil
 }
+
+// parseTypeQueryName parses the name a type query refers to: x or x.y.z
+func (p *parser) parseTypeQueryName() (ast.Expression, error) {
+	startPos := p.currentPos()
+	id, err := p.parseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	var expr ast.Expression = id
+	for p.match(".") {
+		p.advance() // consume .
+		property, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		expr = &ast.MemberExpression{
+			Object:   expr,
+			Property: property,
+			Position: startPos,
+			EndPos:   p.currentPos(),
+		}
+	}
+	return expr, nil
+}
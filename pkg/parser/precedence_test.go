@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	"tstypechecker/pkg/ast"
+)
+
+// grouping writes an expression with every binary operation parenthesized
+func grouping(expr ast.Expression) string {
+	switch e := expr.(type) {
+	case *ast.BinaryExpression:
+		return "(" + grouping(e.Left) + " " + e.Operator + " " + grouping(e.Right) + ")"
+	case *ast.AsExpression:
+		return "(" + grouping(e.Expression) + " as T)"
+	case *ast.Identifier:
+		return e.Name
+	case *ast.MemberExpression:
+		return grouping(e.Object) + "." + grouping(e.Property)
+	case *ast.Literal:
+		return fmt.Sprint(e.Value)
+	}
+	return expr.Type()
+}
+
+func TestBinaryOperatorPrecedence(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"a || b && c;", "(a || (b && c))"},
+		{"b && e instanceof C && e.status > 400;", "((b && (e instanceof C)) && (e.status > 400))"},
+		{"a + b * c - d;", "((a + (b * c)) - d)"},
+		{"a === 1 || b !== 2;", "((a === 1) || (b !== 2))"},
+		{"a ** b ** c;", "(a ** (b ** c))"},
+		{"a + b as T;", "((a + b) as T)"},
+		{"a && b as T;", "(a && (b as T))"},
+		{`"k" in x && x.k;`, "((k in x) && x.k)"},
+	}
+	for _, tt := range tests {
+		file, err := ParseCode(tt.code, "test.ts")
+		if err != nil {
+			t.Fatalf("ParseCode(%q) error = %v", tt.code, err)
+		}
+		stmt, ok := file.Body[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("%q: got %T, want ExpressionStatement", tt.code, file.Body[0])
+		}
+		if got := grouping(stmt.Expression); got != tt.want {
+			t.Errorf("%q parsed as %s, want %s", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"tstypechecker/pkg/ast"
+)
+
+// SyntaxError is a syntax diagnostic collected while parsing
+type SyntaxError struct {
+	Message  string
+	Position ast.Position
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at %s", e.Message, e.Position)
+}
+
+// SyntaxErrors is returned by ParseFile and ParseCode when the source has
+// syntax errors. The *ast.File returned with it holds every statement that
+// could be parsed, so it can still be type checked.
+type SyntaxErrors []*SyntaxError
+
+func (e SyntaxErrors) Error() string {
+	if len(e) == 0 {
+		return "no syntax errors"
+	}
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more syntax errors)", e[0].Error(), len(e)-1)
+}
+
+// SyntaxErrorsOf returns the syntax diagnostics carried by err, if any
+func SyntaxErrorsOf(err error) []*SyntaxError {
+	var list SyntaxErrors
+	if errors.As(err, &list) {
+		return list
+	}
+	return nil
+}
+
+// Positions embedded in parser error messages: "at 3:5" or "line 3, col 5"
+var (
+	reErrorAtPos   = regexp.MustCompile(`\s*at (\d+):(\d+)`)
+	reErrorLineCol = regexp.MustCompile(`line (\d+), col (\d+)`)
+)
+
+// statementStartKeywords are the words that begin a new statement when they
+// open a line; recovery resumes parsing there
+var statementStartKeywords = []string{
+	"const", "let", "var", "function", "class", "interface", "type", "enum",
+	"export", "import", "if", "for", "while", "do", "return", "switch", "try",
+	"throw", "async", "abstract", "declare", "namespace", "module", "break", "continue",
+}
+
+// addSyntaxError records a diagnostic, ignoring repeats at the same position
+func (p *parser) addSyntaxError(message string, pos ast.Position) {
+	if n := len(p.syntaxErrors); n > 0 && p.syntaxErrors[n-1].Position.Offset == pos.Offset {
+		return
+	}
+	p.syntaxErrors = append(p.syntaxErrors, &SyntaxError{Message: message, Position: pos})
+}
+
+// addSyntaxErrorFrom records a parse failure (an error or the message of a
+// syntax panic), taking the position from the message when it has one
+func (p *parser) addSyntaxErrorFrom(failure interface{}) {
+	message := fmt.Sprint(failure)
+	pos := p.currentPos()
+
+	if m := reErrorAtPos.FindStringSubmatchIndex(message); m != nil {
+		line, _ := strconv.Atoi(message[m[2]:m[3]])
+		column, _ := strconv.Atoi(message[m[4]:m[5]])
+		pos = ast.Position{Line: line, Column: column, Offset: p.offsetOf(line, column)}
+		message = message[:m[0]] + message[m[1]:]
+	} else if m := reErrorLineCol.FindStringSubmatch(message); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		column, _ := strconv.Atoi(m[2])
+		pos = ast.Position{Line: line, Column: column, Offset: p.offsetOf(line, column)}
+	}
+
+	p.addSyntaxError(strings.TrimSpace(message), pos)
+}
+
+// syntaxPanic returns the message of a panic raised by expect or
+// consumeKeyword. Any other panic, such as a runtime error, is a bug in the
+// parser rather than in the source, so it is raised again.
+func syntaxPanic(r interface{}) string {
+	message, ok := r.(string)
+	if !ok {
+		panic(r)
+	}
+	return message
+}
+
+// offsetOf converts a 1-based line and column into a byte offset
+func (p *parser) offsetOf(line, column int) int {
+	starts := p.lineStartOffsets()
+	if line < 1 {
+		return 0
+	}
+	if line > len(starts) {
+		return len(p.source)
+	}
+	offset := starts[line-1] + column - 1
+	if offset > len(p.source) {
+		offset = len(p.source)
+	}
+	return offset
+}
+
+// lineStartOffsets returns the byte offset where each line begins
+func (p *parser) lineStartOffsets() []int {
+	if p.lineStarts == nil {
+		starts := []int{0}
+		for offset := 0; ; {
+			next := strings.IndexByte(p.source[offset:], '\n')
+			if next < 0 {
+				break
+			}
+			offset += next + 1
+			starts = append(starts, offset)
+		}
+		p.lineStarts = starts
+	}
+	return p.lineStarts
+}
+
+// parseStatementWithRecovery parses a statement. On a syntax error it records
+// a diagnostic, skips to the next statement boundary and returns nil.
+func (p *parser) parseStatementWithRecovery(inBlock bool) (stmt ast.Statement) {
+	start := p.saveState()
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.addSyntaxErrorFrom(syntaxPanic(r))
+			p.synchronize(start, inBlock, false)
+			stmt = nil
+		}
+	}()
+
+	stmt, err := p.parseStatement()
+	if err != nil {
+		p.addSyntaxErrorFrom(err)
+		p.synchronize(start, inBlock, false)
+		return nil
+	}
+	return stmt
+}
+
+// parseClassMemberWithRecovery parses a class member, skipping to the next
+// member boundary on a syntax error
+func (p *parser) parseClassMemberWithRecovery() (member ast.ClassMember) {
+	start := p.saveState()
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.addSyntaxErrorFrom(syntaxPanic(r))
+			p.synchronize(start, true, true)
+			member = nil
+		}
+	}()
+
+	member, err := p.parseClassMember()
+	if err != nil {
+		p.addSyntaxErrorFrom(err)
+		p.synchronize(start, true, true)
+		return nil
+	}
+	if member == nil && p.pos == start.Pos {
+		// Nothing the member parser understands, such as a computed name
+		p.addSyntaxError(fmt.Sprintf("unexpected '%s' in class body", p.current()), p.currentPos())
+		p.synchronize(start, true, true)
+	}
+	return member
+}
+
+// synchronize skips from the start of a broken statement (or class member)
+// to where parsing can resume: after a ';' or a closing '}' that balances the
+// statement, before the '}' that closes the enclosing block, or at a line
+// that starts a new statement (a new member when inMembers is set)
+func (p *parser) synchronize(start ParserState, inBlock bool, inMembers bool) {
+	// Rewind without dropping the diagnostic that was just recorded
+	p.pos, p.line, p.column = start.Pos, start.Line, start.Column
+	startLine := p.line
+
+	// Braces decide where blocks end; unbalanced parentheses are common in
+	// half-typed code, so they only matter for ';'
+	braceDepth, parenDepth := 0, 0
+
+	// Always make progress past the token that started the broken statement
+	if p.advanceWord() == "" {
+		p.advance()
+	}
+
+	for !p.isAtEnd() {
+		ch := p.source[p.pos]
+
+		if p.line > startLine && p.atLineStart() {
+			if inMembers && braceDepth == 0 && (isLetter(ch) || ch == '#' || ch == '[') {
+				return
+			}
+			if !inMembers && p.matchKeyword(statementStartKeywords...) {
+				return
+			}
+		}
+
+		switch {
+		case ch == '"' || ch == '\'' || ch == '`':
+			p.skipQuoted(ch)
+			continue
+		case ch == '/' && (p.peek(1) == "/" || p.peek(1) == "*"):
+			p.skipWhitespaceAndComments()
+			continue
+		case ch == '(' || ch == '[':
+			parenDepth++
+		case ch == ')' || ch == ']':
+			if parenDepth > 0 {
+				parenDepth--
+			}
+		case ch == '{':
+			braceDepth++
+		case ch == '}':
+			if braceDepth == 0 {
+				if inBlock {
+					// Leave it for the enclosing block
+					return
+				}
+				// Stray '}' at the top level
+				p.advance()
+				return
+			}
+			braceDepth--
+			if braceDepth == 0 {
+				// The statement's own body ended
+				p.advance()
+				return
+			}
+		case ch == ';' && braceDepth == 0 && parenDepth == 0:
+			p.advance()
+			return
+		}
+
+		p.advance()
+	}
+}
+
+// atLineStart reports whether only whitespace precedes the current position on its line
+func (p *parser) atLineStart() bool {
+	for i := p.pos - 1; i >= 0; i-- {
+		switch p.source[i] {
+		case '\n':
+			return true
+		case ' ', '\t', '\r':
+			continue
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// skipQuoted skips a string or template literal starting at the current position
+func (p *parser) skipQuoted(quote byte) {
+	p.advance()
+	for !p.isAtEnd() {
+		ch := p.source[p.pos]
+		if ch == '\\' {
+			p.advance()
+			p.advance()
+			continue
+		}
+		if ch == '\n' && quote != '`' {
+			// Unterminated string: stop at the end of the line
+			return
+		}
+		p.advance()
+		if ch == quote {
+			return
+		}
+	}
+}
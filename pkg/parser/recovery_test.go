@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"runtime"
+	"testing"
+
+	"tstypechecker/pkg/ast"
+)
+
+func TestRecoveryReportsMultipleSyntaxErrors(t *testing.T) {
+	code := `const a = 1;
+class A {
+  foo(: string {}
+  bar(): number { return 1; }
+}
+function g() {
+  const y = (1;
+  const z = 2;
+}
+const w = 3;
+`
+	file, err := ParseCode(code, "test.ts")
+	if file == nil {
+		t.Fatalf("expected a partial AST, got nil (err = %v)", err)
+	}
+
+	syntaxErrors := SyntaxErrorsOf(err)
+	if len(syntaxErrors) != 2 {
+		t.Fatalf("expected 2 syntax errors, got %d: %v", len(syntaxErrors), err)
+	}
+	if syntaxErrors[0].Position.Line != 3 || syntaxErrors[1].Position.Line != 7 {
+		t.Errorf("syntax errors at lines %d and %d, want 3 and 7",
+			syntaxErrors[0].Position.Line, syntaxErrors[1].Position.Line)
+	}
+
+	if len(file.Body) != 4 {
+		t.Fatalf("expected 4 top-level statements, got %d", len(file.Body))
+	}
+
+	class := file.Body[1].(*ast.ClassDeclaration)
+	if len(class.Body) != 1 {
+		t.Errorf("expected the intact class member to survive, got %d members", len(class.Body))
+	}
+
+	fn := file.Body[2].(*ast.FunctionDeclaration)
+	if len(fn.Body.Body) != 1 {
+		t.Errorf("expected the intact statement in the function body to survive, got %d", len(fn.Body.Body))
+	}
+}
+
+func TestRecoveryAtTopLevel(t *testing.T) {
+	code := `function f( {
+const b = 1;
+const c = 2;
+`
+	file, err := ParseCode(code, "test.ts")
+	if err == nil {
+		t.Fatal("expected syntax errors")
+	}
+	if file == nil {
+		t.Fatal("expected a partial AST")
+	}
+
+	var names []string
+	for _, stmt := range file.Body {
+		if decl, ok := stmt.(*ast.VariableDeclaration); ok {
+			names = append(names, decl.Decls[0].ID.Name)
+		}
+	}
+	if len(names) != 2 || names[0] != "b" || names[1] != "c" {
+		t.Errorf("expected declarations b and c after the broken function, got %v", names)
+	}
+}
+
+func TestValidCodeHasNoSyntaxErrors(t *testing.T) {
+	_, err := ParseCode(`const a = { b: [1, 2] };`, "test.ts")
+	if err != nil {
+		t.Errorf("ParseCode() error = %v", err)
+	}
+}
+
+func TestRecoverySkipsUnparsableClassMembers(t *testing.T) {
+	code := `class A {
+  *gen() {}
+  foo(): void {}
+}
+const x = 1;
+`
+	file, err := ParseCode(code, "test.ts")
+	if file == nil {
+		t.Fatalf("expected a partial AST, got nil (err = %v)", err)
+	}
+
+	syntaxErrors := SyntaxErrorsOf(err)
+	if len(syntaxErrors) != 1 || syntaxErrors[0].Position.Line != 2 {
+		t.Fatalf("expected one syntax error on line 2, got %v", err)
+	}
+	if len(file.Body) != 2 {
+		t.Fatalf("expected 2 top-level statements, got %d", len(file.Body))
+	}
+	if class := file.Body[0].(*ast.ClassDeclaration); len(class.Body) != 1 {
+		t.Errorf("expected the class to keep 1 member, got %d", len(class.Body))
+	}
+}
+
+func TestRecoveryRaisesParserBugs(t *testing.T) {
+	if message := syntaxPanic("expected ';'"); message != "expected ';'" {
+		t.Errorf("syntaxPanic() = %q, want the panic message", message)
+	}
+
+	var bug interface{}
+	func() {
+		defer func() { bug = recover() }()
+		var items []int
+		_ = items[len(items)]
+	}()
+	if _, ok := bug.(runtime.Error); !ok {
+		t.Fatalf("expected a runtime error, got %v", bug)
+	}
+	defer func() {
+		if r := recover(); r != bug {
+			t.Errorf("expected the runtime error to be raised again, got %v", r)
+		}
+	}()
+	syntaxPanic(bug)
+	t.Error("syntaxPanic() recovered from a runtime error")
+}
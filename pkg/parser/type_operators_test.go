@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"testing"
+
+	"tstypechecker/pkg/ast"
+)
+
+func TestConstTypeParameter(t *testing.T) {
+	file, err := ParseCode("function id<const T, U>(x: T, y: U): T { return x; }", "test.ts")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	fn, ok := file.Body[0].(*ast.FunctionDeclaration)
+	if !ok {
+		t.Fatalf("got %T, want FunctionDeclaration", file.Body[0])
+	}
+	if len(fn.TypeParameters) != 2 {
+		t.Fatalf("got %d type parameters, want 2", len(fn.TypeParameters))
+	}
+	for i, want := range []struct {
+		name    string
+		isConst bool
+	}{{"T", true}, {"U", false}} {
+		typeParam := fn.TypeParameters[i].(*ast.TypeParameter)
+		if typeParam.Name.Name != want.name || typeParam.Const != want.isConst {
+			t.Errorf("type parameter %d = %s (const %v), want %s (const %v)",
+				i, typeParam.Name.Name, typeParam.Const, want.name, want.isConst)
+		}
+	}
+}
+
+func TestTypeQueryOperands(t *testing.T) {
+	code := `type Key = keyof typeof routes;
+type Add = ReturnType<typeof actions.add>;
+type Negative = -1;
+`
+	file, err := ParseCode(code, "test.ts")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	if len(file.Body) != 3 {
+		t.Fatalf("got %d statements, want 3", len(file.Body))
+	}
+	annotation := func(i int) ast.TypeNode {
+		alias, ok := file.Body[i].(*ast.TypeAliasDeclaration)
+		if !ok {
+			t.Fatalf("statement %d: got %T, want TypeAliasDeclaration", i, file.Body[i])
+		}
+		return alias.TypeAnnotation
+	}
+
+	keyof, ok := annotation(0).(*ast.TypeOperator)
+	if !ok || keyof.Operator != "keyof" {
+		t.Fatalf("keyof typeof routes: got %T, want keyof TypeOperator", annotation(0))
+	}
+	if query, ok := keyof.Target.(*ast.TypeQuery); !ok {
+		t.Errorf("keyof target: got %T, want TypeQuery", keyof.Target)
+	} else if id, ok := query.ExprName.(*ast.Identifier); !ok || id.Name != "routes" {
+		t.Errorf("typeof routes: got %T", query.ExprName)
+	}
+
+	ref, ok := annotation(1).(*ast.TypeReference)
+	if !ok || ref.Name != "ReturnType" || len(ref.TypeArguments) != 1 {
+		t.Fatalf("ReturnType<typeof actions.add>: got %T", annotation(1))
+	}
+	if query, ok := ref.TypeArguments[0].(*ast.TypeQuery); !ok {
+		t.Errorf("type argument: got %T, want TypeQuery", ref.TypeArguments[0])
+	} else if member, ok := query.ExprName.(*ast.MemberExpression); !ok || member.Property.(*ast.Identifier).Name != "add" {
+		t.Errorf("typeof actions.add: got %T", query.ExprName)
+	}
+
+	if literal, ok := annotation(2).(*ast.LiteralType); !ok || literal.Value != "-1" {
+		t.Errorf("-1: got %#v, want LiteralType -1", annotation(2))
+	}
+}
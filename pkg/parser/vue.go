@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// isVueFile reports whether filename is a Vue single file component
+func isVueFile(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".vue"
+}
+
+// vueScript describes the <script> blocks found in a .vue file
+type vueScript struct {
+	source string // the SFC with everything outside <script> blocks blanked
+	lang   string // lang attribute of the script blocks ("js" when absent)
+	setup  bool   // one of the blocks is <script setup>
+}
+
+// extractVueScript keeps the contents of the <script> and <script setup>
+// blocks of a Vue SFC and replaces every other character except line breaks
+// with a space. Offsets, lines and columns of the script code therefore stay
+// the same as in the .vue file, and the template and styles read as blank
+// lines.
+func extractVueScript(source string) vueScript {
+	blank := []byte(source)
+	for i, ch := range blank {
+		if ch != '\n' && ch != '\r' {
+			blank[i] = ' '
+		}
+	}
+
+	script := vueScript{lang: "js"}
+	for pos := 0; pos < len(source); {
+		next := strings.Index(source[pos:], "<")
+		if next < 0 {
+			break
+		}
+		pos += next
+
+		// Commented out blocks: <!-- <script>...</script> -->
+		if strings.HasPrefix(source[pos:], "<!--") {
+			end := strings.Index(source[pos:], "-->")
+			if end < 0 {
+				break
+			}
+			pos += end + 3
+			continue
+		}
+
+		if !isVueScriptTag(source, pos) {
+			pos++
+			continue
+		}
+
+		tagEnd := vueTagEnd(source, pos)
+		if tagEnd < 0 {
+			break
+		}
+		attrs := source[pos+len("<script") : tagEnd]
+		if strings.HasSuffix(strings.TrimSpace(attrs), "/") {
+			// <script src="./external.ts" />
+			pos = tagEnd + 1
+			continue
+		}
+
+		contentStart := tagEnd + 1
+		closing := strings.Index(strings.ToLower(source[contentStart:]), "</script")
+		contentEnd := len(source)
+		if closing >= 0 {
+			contentEnd = contentStart + closing
+		}
+		copy(blank[contentStart:contentEnd], source[contentStart:contentEnd])
+
+		if lang := vueAttribute(attrs, "lang"); lang != "" {
+			script.lang = strings.ToLower(lang)
+		}
+		if vueHasAttribute(attrs, "setup") {
+			script.setup = true
+		}
+		pos = contentEnd
+	}
+
+	script.source = string(blank)
+	return script
+}
+
+// isVueScriptTag reports whether an opening <script> tag starts at pos
+func isVueScriptTag(source string, pos int) bool {
+	rest := source[pos:]
+	if len(rest) < len("<script")+1 || !strings.EqualFold(rest[:len("<script")], "<script") {
+		return false
+	}
+	switch rest[len("<script")] {
+	case '>', ' ', '\t', '\r', '\n', '/':
+		return true
+	}
+	return false
+}
+
+// vueTagEnd returns the offset of the '>' that closes the tag opened at pos,
+// skipping quoted attribute values
+func vueTagEnd(source string, pos int) int {
+	var quote byte
+	for i := pos; i < len(source); i++ {
+		ch := source[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// vueAttribute returns the value of the named attribute in a tag's attribute text
+func vueAttribute(attrs, name string) string {
+	for _, field := range vueAttributeFields(attrs) {
+		if key, value, ok := strings.Cut(field, "="); ok && strings.EqualFold(key, name) {
+			return strings.Trim(value, `"'`)
+		}
+	}
+	return ""
+}
+
+// vueHasAttribute reports whether a tag has the named attribute, with or without a value
+func vueHasAttribute(attrs, name string) bool {
+	for _, field := range vueAttributeFields(attrs) {
+		key, _, _ := strings.Cut(field, "=")
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// vueAttributeFields splits attribute text on whitespace outside quotes
+func vueAttributeFields(attrs string) []string {
+	var fields []string
+	var quote byte
+	start := -1
+	for i := 0; i < len(attrs); i++ {
+		ch := attrs[i]
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch ch {
+		case '"', '\'':
+			quote = ch
+		case ' ', '\t', '\r', '\n', '/':
+			if start >= 0 {
+				fields = append(fields, attrs[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, attrs[start:])
+	}
+	return fields
+}
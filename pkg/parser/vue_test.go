@@ -2,6 +2,8 @@ package parser
 
 import (
 	"testing"
+
+	"tstypechecker/pkg/ast"
 )
 
 func TestVueComponentSetup(t *testing.T) {
@@ -19,3 +21,66 @@ func TestVueComponentSetup(t *testing.T) {
 		t.Errorf("ParseCode() error = %v", err)
 	}
 }
+
+func TestParseVueSingleFileComponent(t *testing.T) {
+	code := `<template>
+  <div>{{ count }}</div>
+</template>
+
+<!-- <script>broken(</script> -->
+<script lang="ts">
+export default { name: 'Counter' };
+</script>
+
+<script setup lang="ts">
+const count: number = 1;
+</script>
+
+<style>
+.a { color: red; }
+</style>
+`
+	file, err := ParseCode(code, "Counter.vue")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	if file.ScriptLang != "ts" || !file.ScriptSetup {
+		t.Errorf("ScriptLang = %q, ScriptSetup = %v, want \"ts\", true", file.ScriptLang, file.ScriptSetup)
+	}
+	if len(file.Body) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(file.Body))
+	}
+
+	// Positions are those of the .vue file
+	decl, ok := file.Body[1].(*ast.VariableDeclaration)
+	if !ok {
+		t.Fatalf("expected VariableDeclaration, got %T", file.Body[1])
+	}
+	if pos := decl.Decls[0].ID.Pos(); pos.Line != 11 || pos.Column != 7 {
+		t.Errorf("count declared at %d:%d, want 11:7", pos.Line, pos.Column)
+	}
+}
+
+func TestParseVueScriptLang(t *testing.T) {
+	tests := []struct {
+		code      string
+		wantLang  string
+		wantSetup bool
+	}{
+		{`<script>export default {}</script>`, "js", false},
+		{`<script setup>const a = 1</script>`, "js", true},
+		{`<script setup lang='tsx'>const a = <div />;</script>`, "tsx", true},
+		{`<template><p /></template>`, "js", false},
+	}
+
+	for _, tt := range tests {
+		file, err := ParseCode(tt.code, "Comp.vue")
+		if err != nil {
+			t.Fatalf("ParseCode(%q) error = %v", tt.code, err)
+		}
+		if file.ScriptLang != tt.wantLang || file.ScriptSetup != tt.wantSetup {
+			t.Errorf("%q: ScriptLang = %q, ScriptSetup = %v, want %q, %v",
+				tt.code, file.ScriptLang, file.ScriptSetup, tt.wantLang, tt.wantSetup)
+		}
+	}
+}
@@ -58,8 +58,14 @@ func (b *Binder) bindStatement(stmt ast.Statement) {
 		b.bindExportDeclaration(s)
 	case *ast.ForStatement:
 		b.bindForStatement(s)
+	case *ast.ForOfStatement:
+		b.bindForOfStatement(s)
+	case *ast.ForInStatement:
+		b.bindForInStatement(s)
 	case *ast.WhileStatement:
 		b.bindWhileStatement(s)
+	case *ast.DoWhileStatement:
+		b.bindDoWhileStatement(s)
 	case *ast.TypeAliasDeclaration:
 		b.bindTypeAliasDeclaration(s)
 	case *ast.InterfaceDeclaration:
@@ -77,6 +83,17 @@ func (b *Binder) bindStatement(stmt ast.Statement) {
 	case *ast.ContinueStatement:
 		b.bindContinueStatement(s)
 	case *ast.ModuleDeclaration:
+		// declare global { ... } adds its declarations to the global scope,
+		// where they merge with the lib ones, even from a module file
+		if s.Global {
+			current := b.table.Current
+			b.table.Current = b.table.Global
+			for _, stmt := range s.Body {
+				b.bindStatement(stmt)
+			}
+			b.table.Current = current
+			return
+		}
 		// Ambient module declarations (declare module 'name' { ... })
 		// These are type-only declarations and don't need symbol binding
 		// We just skip them silently
@@ -335,6 +352,17 @@ func (b *Binder) bindExpression(expr ast.Expression) {
 		// Bind the expression being asserted
 		b.bindExpression(e.Expression)
 		return
+	case *ast.JSXElement:
+		b.bindJSXElement(e)
+	case *ast.JSXFragment:
+		for _, child := range e.Children {
+			b.bindExpression(child)
+		}
+	case *ast.JSXExpressionContainer:
+		b.bindExpression(e.Expression)
+	case *ast.JSXText:
+		// Text doesn't need binding
+		return
 	default:
 		// Unknown expression type
 		fmt.Printf("Warning: Unknown expression type: %T\n", expr)
@@ -589,6 +617,63 @@ func (b *Binder) bindForStatement(stmt *ast.ForStatement) {
 	b.table.ExitScope()
 }
 
+func (b *Binder) bindForOfStatement(stmt *ast.ForOfStatement) {
+	// The iterable is evaluated outside the loop scope
+	if stmt.Right != nil {
+		b.bindExpression(stmt.Right)
+	}
+
+	b.table.EnterScope(stmt)
+
+	// The element type depends on the iterable, so it is resolved by the checker
+	b.bindLoopVariable(stmt.Left, nil)
+
+	if stmt.Body != nil {
+		b.bindStatement(stmt.Body)
+	}
+
+	b.table.ExitScope()
+}
+
+func (b *Binder) bindForInStatement(stmt *ast.ForInStatement) {
+	if stmt.Right != nil {
+		b.bindExpression(stmt.Right)
+	}
+
+	b.table.EnterScope(stmt)
+
+	// for...in always iterates over string keys
+	b.bindLoopVariable(stmt.Left, types.String)
+
+	if stmt.Body != nil {
+		b.bindStatement(stmt.Body)
+	}
+
+	b.table.ExitScope()
+}
+
+// bindLoopVariable binds the left side of a for...of/for...in loop and gives
+// declared loop variables the element type when it is already known
+func (b *Binder) bindLoopVariable(left ast.Node, elementType *types.Type) {
+	switch l := left.(type) {
+	case *ast.VariableDeclaration:
+		b.bindVariableDeclaration(l)
+		if elementType == nil {
+			return
+		}
+		for _, declarator := range l.Decls {
+			if declarator.ID == nil {
+				continue
+			}
+			if symbol, exists := b.table.Current.Symbols[declarator.ID.Name]; exists {
+				symbol.ResolvedType = elementType
+			}
+		}
+	case *ast.ExpressionStatement:
+		b.bindExpression(l.Expression)
+	}
+}
+
 func (b *Binder) bindWhileStatement(stmt *ast.WhileStatement) {
 	// Bind test
 	b.bindExpression(stmt.Test)
@@ -599,6 +684,16 @@ func (b *Binder) bindWhileStatement(stmt *ast.WhileStatement) {
 	}
 }
 
+func (b *Binder) bindDoWhileStatement(stmt *ast.DoWhileStatement) {
+	// Bind body
+	if stmt.Body != nil {
+		b.bindStatement(stmt.Body)
+	}
+
+	// Bind test
+	b.bindExpression(stmt.Test)
+}
+
 func (b *Binder) bindSwitchStatement(stmt *ast.SwitchStatement) {
 	// Bind discriminant
 	if stmt.Discriminant != nil {
@@ -636,6 +731,24 @@ func (b *Binder) bindConditionalExpression(expr *ast.ConditionalExpression) {
 	}
 }
 
+// bindJSXElement binds the tag name and the expressions in attributes and children
+func (b *Binder) bindJSXElement(elem *ast.JSXElement) {
+	b.bindExpression(elem.Name)
+
+	for _, attr := range elem.Attributes {
+		switch a := attr.(type) {
+		case *ast.JSXAttribute:
+			b.bindExpression(a.Value)
+		case *ast.JSXSpreadAttribute:
+			b.bindExpression(a.Argument)
+		}
+	}
+
+	for _, child := range elem.Children {
+		b.bindExpression(child)
+	}
+}
+
 func (b *Binder) bindFunctionExpression(fnExpr *ast.FunctionExpression) {
 	// Create a new scope for the function expression
 	b.table.EnterScope(fnExpr)
@@ -0,0 +1,629 @@
+package symbols
+
+import (
+	"strings"
+
+	"tstypechecker/pkg/ast"
+)
+
+// referenceMarker walks a bound file and counts the reads of every symbol
+type referenceMarker struct {
+	// Scopes created by the binder keyed by the node that created them
+	scopes  map[ast.Node]*Scope
+	current *Scope
+	visited []*Scope
+
+	// Scopes of the classes being walked, innermost last. Properties read
+	// inside a class body count as reads of its members.
+	classes []*Scope
+}
+
+// MarkReferences walks file, which was bound into scope, and increments
+// References on every symbol it reads. Declarations and identifiers that are
+// only written to are not reads. Symbols of the global scope are shared with
+// other checkers and are never updated.
+//
+// It returns scope and the scopes of file nested in it, in source order.
+func MarkReferences(file *ast.File, scope *Scope) []*Scope {
+	m := &referenceMarker{
+		scopes:  make(map[ast.Node]*Scope),
+		current: scope,
+		visited: []*Scope{scope},
+	}
+	m.collectScopes(scope)
+
+	for _, stmt := range file.Body {
+		m.statement(stmt)
+	}
+	return m.visited
+}
+
+// collectScopes indexes the scopes nested in scope by their node, keeping the
+// first scope of a node, which is the one the binder created
+func (m *referenceMarker) collectScopes(scope *Scope) {
+	for _, child := range scope.Children {
+		if child.Node != nil {
+			if _, exists := m.scopes[child.Node]; !exists {
+				m.scopes[child.Node] = child
+			}
+		}
+		m.collectScopes(child)
+	}
+}
+
+// enter makes the scope created by node current and returns a function that
+// restores the previous one. Nodes without a scope keep the current one.
+func (m *referenceMarker) enter(node ast.Node) func() {
+	scope, ok := m.scopes[node]
+	if !ok {
+		return func() {}
+	}
+	previous := m.current
+	m.current = scope
+	m.visited = append(m.visited, scope)
+	return func() { m.current = previous }
+}
+
+// read records a read of name in the current scope. Class scopes hold
+// members, which are not in lexical scope, so they are skipped. The search
+// stops at the global scope, level 0, which chains to the shared globals.
+// A function or class referring to itself from inside is not a read, so a
+// function that only calls itself stays unused.
+func (m *referenceMarker) read(name string) {
+	for scope := m.current; scope != nil && scope.Level > 0; scope = scope.Parent {
+		if _, isClass := scope.Node.(*ast.ClassDeclaration); isClass {
+			continue
+		}
+		if symbol, ok := scope.Symbols[name]; ok {
+			if !m.inside(symbol.Node) {
+				symbol.References++
+			}
+			return
+		}
+	}
+}
+
+// inside reports whether the current scope is node's scope or nested in it
+func (m *referenceMarker) inside(node ast.Node) bool {
+	for scope := m.current; scope != nil; scope = scope.Parent {
+		if scope.Node != nil && scope.Node == node {
+			return true
+		}
+	}
+	return false
+}
+
+// readMember records a read of the property name inside the enclosing
+// classes. Parameter properties are members bound in the constructor's scope.
+func (m *referenceMarker) readMember(name string) {
+	for _, class := range m.classes {
+		if symbol, ok := class.Symbols[name]; ok {
+			symbol.References++
+		}
+		if ctor, ok := class.Symbols["constructor"]; ok {
+			if scope, ok := m.scopes[ctor.Node]; ok {
+				if symbol, ok := scope.Symbols[name]; ok && IsParameterProperty(symbol.Node) {
+					symbol.References++
+				}
+			}
+		}
+	}
+}
+
+// IsParameterProperty reports whether node is a constructor parameter with
+// an accessibility or readonly modifier, which also declares a class property
+func IsParameterProperty(node ast.Node) bool {
+	param, ok := node.(*ast.Parameter)
+	return ok && (param.Public || param.Private || param.Protected || param.Readonly)
+}
+
+func (m *referenceMarker) statement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.VariableDeclaration:
+		m.variableDeclaration(s)
+	case *ast.FunctionDeclaration:
+		if s.Body == nil {
+			m.signature(s.Params, s.ReturnType, s.TypeParameters)
+			return
+		}
+		defer m.enter(s)()
+		m.signature(s.Params, s.ReturnType, s.TypeParameters)
+		m.block(s.Body)
+	case *ast.BlockStatement:
+		m.block(s)
+	case *ast.ReturnStatement:
+		m.expression(s.Argument)
+	case *ast.ExpressionStatement:
+		m.expressionStatement(s.Expression)
+	case *ast.IfStatement:
+		m.expression(s.Test)
+		m.statement(s.Consequent)
+		m.statement(s.Alternate)
+	case *ast.SwitchStatement:
+		m.expression(s.Discriminant)
+		for _, c := range s.Cases {
+			m.expression(c.Test)
+			for _, consequent := range c.Consequent {
+				m.statement(consequent)
+			}
+		}
+	case *ast.ExportDeclaration:
+		m.exportDeclaration(s)
+	case *ast.ForStatement:
+		defer m.enter(s)()
+		switch init := s.Init.(type) {
+		case *ast.VariableDeclaration:
+			m.variableDeclaration(init)
+		case *ast.ExpressionStatement:
+			m.expressionStatement(init.Expression)
+		}
+		m.expression(s.Test)
+		m.expressionStatement(s.Update)
+		m.statement(s.Body)
+	case *ast.ForOfStatement:
+		m.expression(s.Right)
+		defer m.enter(s)()
+		m.loopVariable(s.Left)
+		m.statement(s.Body)
+	case *ast.ForInStatement:
+		m.expression(s.Right)
+		defer m.enter(s)()
+		m.loopVariable(s.Left)
+		m.statement(s.Body)
+	case *ast.WhileStatement:
+		m.expression(s.Test)
+		m.statement(s.Body)
+	case *ast.DoWhileStatement:
+		m.statement(s.Body)
+		m.expression(s.Test)
+	case *ast.TypeAliasDeclaration:
+		m.typeNodes(s.TypeParameters)
+		m.typeNode(s.TypeAnnotation)
+	case *ast.InterfaceDeclaration:
+		m.typeNodes(s.TypeParameters)
+		m.typeNodes(s.Extends)
+		m.typeMembers(s.Members)
+	case *ast.ClassDeclaration:
+		m.class(s)
+	case *ast.TryStatement:
+		m.block(s.Block)
+		if s.Handler != nil {
+			restore := m.enter(s.Handler)
+			m.block(s.Handler.Body)
+			restore()
+		}
+		m.block(s.Finalizer)
+	case *ast.ThrowStatement:
+		m.expression(s.Argument)
+	case *ast.ModuleDeclaration:
+		for _, stmt := range s.Body {
+			m.statement(stmt)
+		}
+	case *ast.EnumDeclaration:
+		for _, member := range s.Members {
+			m.expression(member.Value)
+		}
+	case *ast.NamespaceDeclaration:
+		defer m.enter(s)()
+		for _, stmt := range s.Body {
+			m.statement(stmt)
+		}
+	}
+}
+
+func (m *referenceMarker) block(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	defer m.enter(block)()
+	for _, stmt := range block.Body {
+		m.statement(stmt)
+	}
+}
+
+func (m *referenceMarker) variableDeclaration(decl *ast.VariableDeclaration) {
+	for _, declarator := range decl.Decls {
+		m.typeNode(declarator.TypeAnnotation)
+		m.expression(declarator.Init)
+	}
+}
+
+// loopVariable walks the left side of a for...of/for...in loop, which is
+// written to on every iteration
+func (m *referenceMarker) loopVariable(left ast.Node) {
+	switch l := left.(type) {
+	case *ast.VariableDeclaration:
+		m.variableDeclaration(l)
+	case *ast.ExpressionStatement:
+		m.assignmentTarget(l.Expression)
+	}
+}
+
+// exportDeclaration walks an export. Exporting a declaration or a local name
+// counts as reading it.
+func (m *referenceMarker) exportDeclaration(decl *ast.ExportDeclaration) {
+	if decl.Declaration != nil {
+		m.statement(decl.Declaration)
+		for _, name := range declaredNames(decl.Declaration) {
+			m.read(name)
+		}
+	}
+	if decl.Source != nil {
+		return
+	}
+	for _, spec := range decl.Specifiers {
+		if spec.Local != nil {
+			m.read(spec.Local.Name)
+		}
+	}
+}
+
+// declaredNames returns the names a declaration statement binds
+func declaredNames(stmt ast.Statement) []string {
+	switch d := stmt.(type) {
+	case *ast.VariableDeclaration:
+		var names []string
+		for _, declarator := range d.Decls {
+			if declarator.ID != nil {
+				names = append(names, declarator.ID.Name)
+			}
+		}
+		return names
+	case *ast.FunctionDeclaration:
+		if d.ID != nil {
+			return []string{d.ID.Name}
+		}
+	case *ast.ClassDeclaration:
+		if d.ID != nil {
+			return []string{d.ID.Name}
+		}
+	case *ast.TypeAliasDeclaration:
+		if d.ID != nil {
+			return []string{d.ID.Name}
+		}
+	case *ast.InterfaceDeclaration:
+		if d.ID != nil {
+			return []string{d.ID.Name}
+		}
+	case *ast.EnumDeclaration:
+		if d.Name != nil {
+			return []string{d.Name.Name}
+		}
+	case *ast.NamespaceDeclaration:
+		if d.Name != nil {
+			return []string{d.Name.Name}
+		}
+	}
+	return nil
+}
+
+func (m *referenceMarker) class(decl *ast.ClassDeclaration) {
+	if decl == nil {
+		return
+	}
+	if decl.SuperClass != nil {
+		m.read(decl.SuperClass.Name)
+	}
+	m.typeNodes(decl.Implements)
+
+	scope, ok := m.scopes[decl]
+	if ok {
+		defer m.enter(decl)()
+		m.classes = append(m.classes, scope)
+		defer func() { m.classes = m.classes[:len(m.classes)-1] }()
+	}
+	m.typeNodes(decl.TypeParameters)
+
+	for _, member := range decl.Body {
+		switch mem := member.(type) {
+		case *ast.MethodDefinition:
+			if mem.Value == nil {
+				continue
+			}
+			restore := m.enter(mem)
+			m.signature(mem.Value.Params, mem.Value.ReturnType, nil)
+			m.block(mem.Value.Body)
+			restore()
+		case *ast.PropertyDefinition:
+			m.typeNode(mem.TypeAnnotation)
+			m.expression(mem.Value)
+		}
+	}
+}
+
+// signature walks the types and default values of a function's parameters
+// and its return type
+func (m *referenceMarker) signature(params []*ast.Parameter, returnType ast.TypeNode, typeParams []ast.TypeNode) {
+	m.typeNodes(typeParams)
+	for _, param := range params {
+		m.typeNode(param.ParamType)
+		m.expression(param.Default)
+	}
+	m.typeNode(returnType)
+}
+
+// expressionStatement walks an expression whose value is discarded, where
+// x++ and x += 1 only write to x
+func (m *referenceMarker) expressionStatement(expr ast.Expression) {
+	switch e := expr.(type) {
+	case *ast.UnaryExpression:
+		if e.Operator == "++" || e.Operator == "--" {
+			m.assignmentTarget(e.Argument)
+			return
+		}
+	case *ast.AssignmentExpression:
+		m.assignmentTarget(e.Left)
+		m.expression(e.Right)
+		return
+	}
+	m.expression(expr)
+}
+
+// assignmentTarget walks the target of a write. A plain identifier is not
+// read, while the objects of member targets are.
+func (m *referenceMarker) assignmentTarget(target ast.Expression) {
+	if _, ok := target.(*ast.Identifier); ok {
+		return
+	}
+	m.expression(target)
+}
+
+func (m *referenceMarker) expression(expr ast.Expression) {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		m.read(e.Name)
+	case *ast.Literal:
+		if strings.HasPrefix(e.Raw, "`") {
+			for _, name := range templateNames(e.Raw) {
+				m.read(name)
+			}
+		}
+	case *ast.CallExpression:
+		m.expression(e.Callee)
+		m.typeNodes(e.TypeArguments)
+		m.expressions(e.Arguments)
+	case *ast.MemberExpression:
+		m.expression(e.Object)
+		if e.Computed {
+			m.expression(e.Property)
+		} else if id, ok := e.Property.(*ast.Identifier); ok {
+			m.readMember(id.Name)
+		}
+	case *ast.AsExpression:
+		m.expression(e.Expression)
+		m.typeNode(e.TypeAnnotation)
+	case *ast.SatisfiesExpression:
+		m.expression(e.Expression)
+		m.typeNode(e.TypeAnnotation)
+	case *ast.ConditionalExpression:
+		m.expression(e.Test)
+		m.expression(e.Consequent)
+		m.expression(e.Alternate)
+	case *ast.BinaryExpression:
+		m.expression(e.Left)
+		m.expression(e.Right)
+	case *ast.ArrayExpression:
+		m.expressions(e.Elements)
+	case *ast.ObjectExpression:
+		for _, prop := range e.Properties {
+			switch p := prop.(type) {
+			case *ast.Property:
+				// Plain keys are names, computed keys are expressions
+				switch p.Key.(type) {
+				case *ast.Identifier, *ast.Literal:
+				default:
+					m.expression(p.Key)
+				}
+				m.expression(p.Value)
+			case *ast.SpreadElement:
+				m.expression(p.Argument)
+			}
+		}
+	case *ast.SpreadElement:
+		m.expression(e.Argument)
+	case *ast.ArrowFunctionExpression:
+		defer m.enter(e)()
+		m.signature(e.Params, e.ReturnType, nil)
+		switch body := e.Body.(type) {
+		case *ast.BlockStatement:
+			m.block(body)
+		case ast.Expression:
+			m.expression(body)
+		}
+	case *ast.FunctionExpression:
+		defer m.enter(e)()
+		m.signature(e.Params, e.ReturnType, nil)
+		m.block(e.Body)
+	case *ast.AssignmentExpression:
+		if e.Operator == "=" {
+			m.assignmentTarget(e.Left)
+		} else {
+			m.expression(e.Left)
+		}
+		m.expression(e.Right)
+	case *ast.UnaryExpression:
+		m.expression(e.Argument)
+	case *ast.NewExpression:
+		m.expression(e.Callee)
+		m.typeNodes(e.TypeArguments)
+		m.expressions(e.Arguments)
+	case *ast.YieldExpression:
+		m.expression(e.Argument)
+	case *ast.ClassExpression:
+		m.class(e.Class)
+	case *ast.TaggedTemplateExpression:
+		m.expression(e.Tag)
+		if e.Quasi != nil {
+			m.expressions(e.Quasi.Expressions)
+		}
+	case *ast.TemplateLiteral:
+		m.expressions(e.Expressions)
+	case *ast.JSXElement:
+		m.jsxElement(e)
+	case *ast.JSXFragment:
+		// The classic runtime compiles fragments to React.Fragment
+		m.read("React")
+		m.expressions(e.Children)
+	case *ast.JSXExpressionContainer:
+		m.expression(e.Expression)
+	}
+}
+
+// templateNames returns the identifiers in the ${...} substitutions of a
+// template literal, which the parser keeps as raw text. Names after a dot are
+// properties and string literals are skipped.
+func templateNames(raw string) []string {
+	var names []string
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '\\' {
+			i++
+			continue
+		}
+		if raw[i] != '$' || i+1 >= len(raw) || raw[i+1] != '{' {
+			continue
+		}
+
+		depth := 1
+		previous := byte(0)
+		for i += 2; i < len(raw) && depth > 0; i++ {
+			c := raw[i]
+			switch {
+			case c == '{':
+				depth++
+			case c == '}':
+				depth--
+			case c == '"' || c == '\'':
+				for i++; i < len(raw) && raw[i] != c; i++ {
+					if raw[i] == '\\' {
+						i++
+					}
+				}
+			case isIdentifierStart(c):
+				start := i
+				for i+1 < len(raw) && isIdentifierPart(raw[i+1]) {
+					i++
+				}
+				if previous != '.' {
+					names = append(names, raw[start:i+1])
+				}
+			}
+			if c != ' ' {
+				previous = c
+			}
+		}
+		i--
+	}
+	return names
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
+
+func (m *referenceMarker) expressions(exprs []ast.Expression) {
+	for _, expr := range exprs {
+		m.expression(expr)
+	}
+}
+
+// jsxElement walks an element. Lowercase tags are intrinsic elements rather
+// than references, and the classic runtime compiles every element to a
+// React.createElement call.
+func (m *referenceMarker) jsxElement(elem *ast.JSXElement) {
+	m.read("React")
+	switch name := elem.Name.(type) {
+	case *ast.Identifier:
+		if name.Name != "" && strings.ToLower(name.Name[:1]) != name.Name[:1] {
+			m.read(name.Name)
+		}
+	default:
+		m.expression(name)
+	}
+
+	for _, attr := range elem.Attributes {
+		switch a := attr.(type) {
+		case *ast.JSXAttribute:
+			m.expression(a.Value)
+		case *ast.JSXSpreadAttribute:
+			m.expression(a.Argument)
+		}
+	}
+	m.expressions(elem.Children)
+}
+
+func (m *referenceMarker) typeNode(node ast.TypeNode) {
+	switch t := node.(type) {
+	case *ast.TypeReference:
+		// Qualified names such as ns.Type read their first segment
+		name := t.Name
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			name = name[:i]
+		}
+		m.read(name)
+		m.typeNodes(t.TypeArguments)
+	case *ast.UnionType:
+		m.typeNodes(t.Types)
+	case *ast.IntersectionType:
+		m.typeNodes(t.Types)
+	case *ast.TupleType:
+		m.typeNodes(t.Elements)
+	case *ast.RestType:
+		m.typeNode(t.TypeAnnotation)
+	case *ast.FunctionType:
+		m.signature(t.Params, t.Return, t.TypeParameters)
+	case *ast.ObjectTypeLiteral:
+		m.typeMembers(t.Members)
+	case *ast.MappedType:
+		m.typeNode(t.Constraint)
+		m.typeNode(t.NameType)
+		m.typeNode(t.MappedType)
+	case *ast.ConditionalType:
+		m.typeNode(t.CheckType)
+		m.typeNode(t.ExtendsType)
+		m.typeNode(t.TrueType)
+		m.typeNode(t.FalseType)
+	case *ast.TemplateLiteralType:
+		m.typeNodes(t.Types)
+	case *ast.IndexedAccessType:
+		m.typeNode(t.ObjectType)
+		m.typeNode(t.IndexType)
+	case *ast.TypeParameter:
+		m.typeNode(t.Constraint)
+		m.typeNode(t.Default)
+	case *ast.TypeQuery:
+		m.expression(t.ExprName)
+	case *ast.TypeOperator:
+		m.typeNode(t.Target)
+	case *ast.TypePredicate:
+		m.typeNode(t.TargetType)
+	}
+}
+
+func (m *referenceMarker) typeNodes(nodes []ast.TypeNode) {
+	for _, node := range nodes {
+		m.typeNode(node)
+	}
+}
+
+func (m *referenceMarker) typeMembers(members []ast.TypeMember) {
+	for _, member := range members {
+		switch mem := member.(type) {
+		case ast.InterfaceProperty:
+			m.typeNode(mem.Value)
+		case *ast.InterfaceProperty:
+			m.typeNode(mem.Value)
+		case *ast.CallSignature:
+			for _, param := range mem.Parameters {
+				m.typeNode(param.ParamType)
+			}
+			m.typeNode(mem.ReturnType)
+		case *ast.IndexSignature:
+			m.typeNode(mem.KeyType)
+			m.typeNode(mem.ValueType)
+		}
+	}
+}
@@ -22,6 +22,10 @@ type Symbol struct {
 	FromDTS      bool // True if this symbol was loaded from a .d.ts file
 	ResolvedType *types.Type
 	UpdateCache  func(*types.Type)
+	References   int // Reads counted by MarkReferences
+	// Declarations merged into the symbol after Node: interface, enum and
+	// namespace blocks declaring the same name
+	Declarations []ast.Node
 }
 
 // SymbolType represents the type of symbol
@@ -159,6 +163,17 @@ func (st *SymbolTable) DefineSymbol(name string, symbolType SymbolType, node ast
 			return existing
 		}
 
+		if node != nil && mergesWith(existing, symbolType, node) {
+			if existing.Node == nil {
+				// A user declaration augments one loaded from a .d.ts file
+				existing.Node = node
+				existing.DeclSpan = node.Pos()
+			} else {
+				existing.Declarations = append(existing.Declarations, node)
+			}
+			return existing
+		}
+
 		// Only add error if node is not nil
 		if node != nil {
 			st.addError(fmt.Sprintf("'%s' is already defined", name),
@@ -186,6 +201,28 @@ func (st *SymbolTable) DefineSymbol(name string, symbolType SymbolType, node ast
 	return symbol
 }
 
+// mergesWith reports whether a declaration of a symbol of type symbolType
+// merges with the existing symbol of the same name instead of redeclaring it:
+// interfaces merge with interfaces, enums with enums, and a namespace with a
+// function, class, enum or namespace
+func mergesWith(existing *Symbol, symbolType SymbolType, node ast.Node) bool {
+	switch node.(type) {
+	case *ast.InterfaceDeclaration:
+		return existing.Type == InterfaceSymbol && symbolType == InterfaceSymbol
+	case *ast.EnumDeclaration:
+		return existing.Type == EnumSymbol && symbolType == EnumSymbol
+	case *ast.NamespaceDeclaration:
+		if existing.Node == nil {
+			return false
+		}
+		switch existing.Node.(type) {
+		case *ast.FunctionDeclaration, *ast.ClassDeclaration, *ast.EnumDeclaration, *ast.NamespaceDeclaration:
+			return true
+		}
+	}
+	return false
+}
+
 // DefineFunction defines a function symbol
 func (st *SymbolTable) DefineFunction(name string, node *ast.FunctionDeclaration) *Symbol {
 	symbol := st.DefineSymbol(name, FunctionSymbol, node, false)
@@ -10,7 +10,12 @@ type TypeInferencer struct {
 	globalEnv    *GlobalEnvironment
 	typeCache    map[ast.Node]*Type
 	varTypeCache map[string]*Type
-	depth        int // Para evitar recursión infinita
+	depth        int   // Para evitar recursión infinita
+	jsxElement   *Type // JSX.Element, the type of JSX expressions (nil when undeclared)
+
+	// callTypes types calls the inferencer cannot, such as compiler macros
+	// typed from their type arguments; it returns nil for other calls
+	callTypes func(*ast.CallExpression) *Type
 }
 
 // NewTypeInferencer crea un nuevo inferenciador de tipos
@@ -32,6 +37,16 @@ func (ti *TypeInferencer) SetVarTypeCache(cache map[string]*Type) {
 	ti.varTypeCache = cache
 }
 
+// SetJSXElementType sets the type of JSX elements and fragments
+func (ti *TypeInferencer) SetJSXElementType(t *Type) {
+	ti.jsxElement = t
+}
+
+// SetCallTypeResolver sets the function consulted first when typing calls
+func (ti *TypeInferencer) SetCallTypeResolver(resolve func(*ast.CallExpression) *Type) {
+	ti.callTypes = resolve
+}
+
 // InferType infiere el tipo de una expresión
 func (ti *TypeInferencer) InferType(expr ast.Expression) *Type {
 	if expr == nil {
@@ -112,78 +127,21 @@ func (ti *TypeInferencer) InferType(expr ast.Expression) *Type {
 		}
 		return Unknown
 	case *ast.AsExpression:
-		// Check for 'as const'
+		// 'as const' infers the deeply readonly literal type of the expression
 		if typeRef, ok := e.TypeAnnotation.(*ast.TypeReference); ok && typeRef.Name == "const" {
-			// Special handling for ArrayExpression to preserve tuple structure
-			if arrayExpr, ok := e.Expression.(*ast.ArrayExpression); ok {
-				// Create readonly tuple type
-				var elementTypes []*Type
-				for _, elem := range arrayExpr.Elements {
-					elemType := ti.InferType(elem)
-					// Convert element type to literal if possible
-					elemType = ti.convertToReadonly(elemType)
-					elementTypes = append(elementTypes, elemType)
-				}
-				tupleType := NewTupleType(elementTypes)
-				tupleType.IsReadonly = true
-				return tupleType
-			}
-
-			// Convert the expression type to readonly literal type
-			baseType := ti.InferType(e.Expression)
-			return ti.convertToReadonly(baseType)
+			return ti.InferConstType(e.Expression)
 		}
 
 		// For type assertions, return the asserted type (not the expression type)
 		// This is critical for double assertions like `value as unknown as number`
 		return ti.convertTypeNode(e.TypeAnnotation)
-	default:
-		return Unknown
-	}
-}
-
-// convertToReadonly converts a type to its readonly/literal equivalent for 'as const'
-func (ti *TypeInferencer) convertToReadonly(t *Type) *Type {
-	if t == nil {
-		return Unknown
-	}
-
-	switch t.Kind {
-	case StringType, NumberType, BooleanType:
-		// Primitives become literals if they have a value
-		// But InferType for literals already returns LiteralType
-		// If it's a generic primitive type without value, we can't make it literal
-		return t
-
-	case ArrayType:
-		// Arrays become readonly tuples
-		// We need to know the elements to create a tuple
-		// Since ArrayType only stores ElementType, we lose the individual element values
-		// But if the original expression was an ArrayExpression, InferType would return ArrayType
-		// We need to handle ArrayExpression specially in InferType or here
-
-		// If we can't recover the tuple structure, at least make it a readonly array
-		newType := *t
-		newType.IsReadonly = true
-		// Recursively make element type readonly
-		newType.ElementType = ti.convertToReadonly(t.ElementType)
-		return &newType
-
-	case ObjectType:
-		// Objects become readonly with literal properties
-		newProps := make(map[string]*Type)
-		for k, v := range t.Properties {
-			newProps[k] = ti.convertToReadonly(v)
-		}
-		return &Type{
-			Kind:       ObjectType,
-			Name:       t.Name,
-			Properties: newProps,
-			IsReadonly: true,
+	case *ast.JSXElement, *ast.JSXFragment:
+		if ti.jsxElement != nil {
+			return ti.jsxElement
 		}
-
+		return Unknown
 	default:
-		return t
+		return Unknown
 	}
 }
 
@@ -236,6 +194,15 @@ func (ti *TypeInferencer) inferMemberExpressionType(expr *ast.MemberExpression)
 		}
 	}
 
+	// The elements of a readonly tuple ('as const') keep their types
+	if objType.Kind == TupleType && objType.IsReadonly && expr.Computed {
+		if lit, ok := expr.Property.(*ast.Literal); ok {
+			if index, ok := numericLiteralValue(lit); ok && index >= 0 && int(index) < len(objType.Types) {
+				return objType.Types[int(index)]
+			}
+		}
+	}
+
 	// Si es un objeto, buscar la propiedad
 	if objType.Kind == ObjectType {
 		if propName != "" {
@@ -251,6 +218,13 @@ func (ti *TypeInferencer) inferMemberExpressionType(expr *ast.MemberExpression)
 			// TODO: Buscar en la cadena de prototipos o tipos heredados
 		}
 	}
+
+	// Static members of a class and the members a namespace adds to a function
+	if objType.Kind == FunctionType && propName != "" {
+		if propType, exists := objType.Properties[propName]; exists {
+			return propType
+		}
+	}
 	// Handle primitive types properties
 	if objType.Kind == StringType {
 		if propName == "length" {
@@ -293,6 +267,12 @@ func (ti *TypeInferencer) inferMemberExpressionType(expr *ast.MemberExpression)
 
 // inferCallExpressionType infiere el tipo de retorno de una llamada a función
 func (ti *TypeInferencer) inferCallExpressionType(call *ast.CallExpression) *Type {
+	if ti.callTypes != nil {
+		if t := ti.callTypes(call); t != nil {
+			return t
+		}
+	}
+
 	// Special handling for Promise static methods like Promise.all, Promise.resolve
 	if member, ok := call.Callee.(*ast.MemberExpression); ok {
 		if objId, ok := member.Object.(*ast.Identifier); ok && objId.Name == "Promise" {
@@ -317,8 +297,7 @@ func (ti *TypeInferencer) inferCallExpressionType(call *ast.CallExpression) *Typ
 				if firstParamType.Kind == TypeParameterType &&
 					firstParamType.Name == calleeType.ReturnType.Name {
 					// Infer type from first argument
-					argType := ti.InferType(call.Arguments[0])
-					return argType
+					return ti.inferTypeArgument(calleeType, firstParamType.Name, call.Arguments[0])
 				}
 			}
 		}
@@ -344,8 +323,7 @@ func (ti *TypeInferencer) inferCallExpressionType(call *ast.CallExpression) *Typ
 						if firstParamType.Kind == TypeParameterType &&
 							firstParamType.Name == varType.ReturnType.Name {
 							// Infer type from first argument
-							argType := ti.InferType(call.Arguments[0])
-							return argType
+							return ti.inferTypeArgument(varType, firstParamType.Name, call.Arguments[0])
 						}
 					}
 				}
@@ -574,7 +552,7 @@ func (ti *TypeInferencer) inferBinaryExpressionType(expr *ast.BinaryExpression)
 		// Operadores aritméticos siempre retornan número
 		return Number
 
-	case "===", "!==", "==", "!=", "<", ">", "<=", ">=":
+	case "===", "!==", "==", "!=", "<", ">", "<=", ">=", "instanceof", "in":
 		// Operadores de comparación siempre retornan boolean
 		return Boolean
 
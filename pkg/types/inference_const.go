@@ -0,0 +1,206 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+
+	"tstypechecker/pkg/ast"
+)
+
+// InferConstType infers the type of an expression under 'as const': literals
+// keep their literal type, arrays become readonly tuples and object
+// properties become readonly, all the way down
+func (ti *TypeInferencer) InferConstType(expr ast.Expression) *Type {
+	switch e := expr.(type) {
+	case *ast.Literal:
+		return ti.inferConstLiteralType(e)
+	case *ast.UnaryExpression:
+		// -1 as const is the literal -1
+		if lit, ok := e.Argument.(*ast.Literal); ok && e.Operator == "-" {
+			if value, ok := numericLiteralValue(lit); ok {
+				return NewLiteralType(-value)
+			}
+		}
+	case *ast.ArrayExpression:
+		var elementTypes []*Type
+		for _, elem := range e.Elements {
+			if spread, ok := elem.(*ast.SpreadElement); ok {
+				spreadType := ti.InferType(spread.Argument)
+				if spreadType.Kind != TupleType {
+					// The length is not known, so it stays a readonly array
+					arrayType := ti.inferArrayType(e)
+					if arrayType.Kind == ArrayType {
+						arrayType.IsReadonly = true
+					}
+					return arrayType
+				}
+				elementTypes = append(elementTypes, spreadType.Types...)
+				continue
+			}
+			elementTypes = append(elementTypes, ti.InferConstType(elem))
+		}
+		tupleType := NewTupleType(elementTypes)
+		tupleType.IsReadonly = true
+		return tupleType
+	case *ast.ObjectExpression:
+		objType := ti.inferObjectType(e)
+		if objType.Kind != ObjectType {
+			return objType
+		}
+		properties := make(map[string]*Type, len(objType.Properties))
+		for name, propType := range objType.Properties {
+			properties[name] = propType
+		}
+		for _, prop := range e.Properties {
+			if p, ok := prop.(*ast.Property); ok {
+				if name := propertyKeyName(p.Key); name != "" {
+					properties[name] = ti.InferConstType(p.Value)
+				}
+			}
+		}
+		for name, propType := range properties {
+			properties[name] = readonlyType(propType)
+		}
+		constType := *objType
+		constType.Properties = properties
+		constType.IsReadonly = true
+		return &constType
+	case *ast.AsExpression:
+		return ti.InferType(e)
+	}
+	return ti.InferType(expr)
+}
+
+// inferConstLiteralType returns the literal type of a literal, numbers and
+// template strings included
+func (ti *TypeInferencer) inferConstLiteralType(lit *ast.Literal) *Type {
+	if value, ok := numericLiteralValue(lit); ok {
+		return NewLiteralType(value)
+	}
+	if str, ok := lit.Value.(string); ok && strings.HasPrefix(lit.Raw, "`") {
+		return ti.inferTemplateType(str)
+	}
+	return ti.inferLiteralType(lit)
+}
+
+// inferTemplateType returns the type of the text of a template string: the
+// string literal it evaluates to when every substitution is a literal, or a
+// template literal type otherwise
+func (ti *TypeInferencer) inferTemplateType(text string) *Type {
+	var parts []string
+	var substitutions []*Type
+	var current strings.Builder
+	for {
+		start := strings.Index(text, "${")
+		if start < 0 {
+			current.WriteString(text)
+			break
+		}
+		current.WriteString(text[:start])
+
+		// Find the brace closing the substitution
+		depth, end := 1, start+2
+		for ; end < len(text) && depth > 0; end++ {
+			switch text[end] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+		if depth > 0 {
+			return String
+		}
+
+		substitution := ti.substitutionType(strings.TrimSpace(text[start+2 : end-1]))
+		if substitution.Kind == LiteralType {
+			current.WriteString(literalText(substitution.Value))
+		} else {
+			parts = append(parts, current.String())
+			substitutions = append(substitutions, substitution)
+			current.Reset()
+		}
+		text = text[end:]
+	}
+
+	if len(substitutions) == 0 {
+		return NewLiteralType(current.String())
+	}
+	return NewTemplateLiteralType(append(parts, current.String()), substitutions)
+}
+
+// substitutionType returns the type of a ${...} substitution of a template
+// string, which is only known for variables
+func (ti *TypeInferencer) substitutionType(source string) *Type {
+	if varType, ok := ti.varTypeCache[source]; ok && varType != nil {
+		switch varType.Kind {
+		case LiteralType, StringType, NumberType, BooleanType, BigIntType:
+			return varType
+		}
+	}
+	return String
+}
+
+// numericLiteralValue returns the value of a number literal
+func numericLiteralValue(lit *ast.Literal) (float64, bool) {
+	raw, ok := lit.Value.(string)
+	if !ok || raw == "" || lit.Raw == "" || !(lit.Raw[0] >= '0' && lit.Raw[0] <= '9' || lit.Raw[0] == '.') {
+		return 0, false
+	}
+	raw = strings.ReplaceAll(raw, "_", "")
+	if strings.HasSuffix(raw, "n") {
+		return 0, false
+	}
+	if value, err := strconv.ParseFloat(raw, 64); err == nil {
+		return value, true
+	}
+	if value, err := strconv.ParseInt(raw, 0, 64); err == nil {
+		return float64(value), true
+	}
+	return 0, false
+}
+
+// literalText returns the text a literal value has inside a string
+func literalText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.Trim(v, `"'`)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// propertyKeyName returns the name of a property key that is not computed
+func propertyKeyName(key ast.Expression) string {
+	switch k := key.(type) {
+	case *ast.Identifier:
+		return k.Name
+	case *ast.Literal:
+		if str, ok := k.Value.(string); ok {
+			return str
+		}
+	}
+	return ""
+}
+
+// readonlyType returns a readonly copy of the type of a property
+func readonlyType(t *Type) *Type {
+	readonly := *t
+	readonly.IsReadonly = true
+	return &readonly
+}
+
+// inferTypeArgument infers the type the type parameter name of a generic
+// function takes from the argument passed for it, as with 'as const' when the
+// type parameter is const
+func (ti *TypeInferencer) inferTypeArgument(fn *Type, name string, arg ast.Expression) *Type {
+	for _, typeParam := range fn.TypeParameters {
+		if typeParam != nil && typeParam.Name == name && typeParam.IsConst {
+			return ti.InferConstType(arg)
+		}
+	}
+	return ti.InferType(arg)
+}
@@ -140,6 +140,7 @@ type Type struct {
 
 	// Para type parameters con constraints
 	Default *Type // Tipo por defecto
+	IsConst bool  // const T: los argumentos se infieren como con 'as const'
 
 	// Index signatures
 	StringIndexType *Type // [key: string]: T
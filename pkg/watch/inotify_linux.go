@@ -0,0 +1,134 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_CREATE |
+	syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// inotifyWatcher watches every directory of the tree with one inotify instance
+type inotifyWatcher struct {
+	fd       int
+	batcher  *batcher
+	mu       sync.Mutex
+	dirs     map[int]string // Watch descriptor -> directory
+	closeErr error
+	once     sync.Once
+}
+
+func newNativeWatcher(root string) (Watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &inotifyWatcher{
+		fd:      fd,
+		batcher: newBatcher(),
+		dirs:    make(map[int]string),
+	}
+	if err := w.addTree(root); err != nil {
+		syscall.Close(fd)
+		w.batcher.close()
+		return nil, err
+	}
+
+	go w.readLoop()
+	return w, nil
+}
+
+func (w *inotifyWatcher) Events() <-chan []string {
+	return w.batcher.out
+}
+
+func (w *inotifyWatcher) Close() error {
+	w.once.Do(func() {
+		w.batcher.close()
+		// Closing the descriptor unblocks the pending read
+		w.closeErr = syscall.Close(w.fd)
+	})
+	return w.closeErr
+}
+
+// addTree registers a watch on dir and all of its subdirectories
+func (w *inotifyWatcher) addTree(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if path != dir && SkipDir(info.Name()) {
+			return filepath.SkipDir
+		}
+
+		wd, err := syscall.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err != nil {
+			// The root must be watchable; unreadable subdirectories are skipped
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		w.mu.Lock()
+		w.dirs[wd] = path
+		w.mu.Unlock()
+		return nil
+	})
+}
+
+func (w *inotifyWatcher) readLoop() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+
+	for {
+		n, err := syscall.Read(w.fd, buf)
+		if err != nil || n <= 0 {
+			if err == syscall.EINTR {
+				continue
+			}
+			w.batcher.close()
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(event.Len)]
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+
+			w.mu.Lock()
+			dir, ok := w.dirs[int(event.Wd)]
+			if event.Mask&syscall.IN_IGNORED != 0 {
+				delete(w.dirs, int(event.Wd))
+			}
+			w.mu.Unlock()
+			if !ok {
+				continue
+			}
+
+			name := string(nameBytes)
+			for i := 0; i < len(name); i++ {
+				if name[i] == 0 {
+					name = name[:i]
+					break
+				}
+			}
+			if name == "" {
+				continue
+			}
+			path := filepath.Join(dir, name)
+
+			if event.Mask&syscall.IN_ISDIR != 0 {
+				// Watch directories created (or moved in) after startup
+				if event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 && !SkipDir(name) {
+					_ = w.addTree(path)
+				}
+				continue
+			}
+
+			w.batcher.add(path)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package watch
+
+import "errors"
+
+// newNativeWatcher is not available on this platform; New falls back to polling
+func newNativeWatcher(root string) (Watcher, error) {
+	return nil, errors.New("native file watching is not supported on this platform")
+}
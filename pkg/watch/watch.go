@@ -0,0 +1,186 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watcher reports paths of files that were created, modified or removed
+// under a directory tree
+type Watcher interface {
+	// Events delivers batches of changed file paths. Bursts of changes that
+	// arrive close together (e.g. an editor save) are coalesced into one batch.
+	Events() <-chan []string
+	// Close stops watching and closes the Events channel
+	Close() error
+}
+
+// debounceInterval is how long to wait for more changes before emitting a batch
+const debounceInterval = 100 * time.Millisecond
+
+// defaultPollInterval is used by the polling watcher
+const defaultPollInterval = 500 * time.Millisecond
+
+// New watches root recursively. It uses the native file notification API when
+// available (inotify on Linux) and falls back to polling otherwise.
+func New(root string) (Watcher, error) {
+	if w, err := newNativeWatcher(root); err == nil {
+		return w, nil
+	}
+	return NewPolling(root, defaultPollInterval)
+}
+
+// SkipDir reports whether a directory should not be watched
+func SkipDir(name string) bool {
+	return name == "node_modules" || (len(name) > 1 && strings.HasPrefix(name, "."))
+}
+
+// batcher collects individual change notifications and emits them as
+// de-duplicated batches once the tree has been quiet for debounceInterval
+type batcher struct {
+	out     chan []string
+	in      chan string
+	done    chan struct{}
+	closeMu sync.Once
+}
+
+func newBatcher() *batcher {
+	b := &batcher{
+		out:  make(chan []string),
+		in:   make(chan string, 256),
+		done: make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *batcher) add(path string) {
+	select {
+	case b.in <- path:
+	case <-b.done:
+	}
+}
+
+func (b *batcher) close() {
+	b.closeMu.Do(func() { close(b.done) })
+}
+
+func (b *batcher) run() {
+	defer close(b.out)
+
+	pending := make(map[string]bool)
+	var order []string
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+
+	for {
+		select {
+		case path := <-b.in:
+			if !pending[path] {
+				pending[path] = true
+				order = append(order, path)
+			}
+			timer.Reset(debounceInterval)
+		case <-timer.C:
+			if len(order) == 0 {
+				continue
+			}
+			batch := order
+			pending = make(map[string]bool)
+			order = nil
+			select {
+			case b.out <- batch:
+			case <-b.done:
+				return
+			}
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// fileState is what the polling watcher compares between scans
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// pollingWatcher rescans the tree at a fixed interval
+type pollingWatcher struct {
+	root     string
+	interval time.Duration
+	batcher  *batcher
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPolling creates a watcher that rescans root every interval
+func NewPolling(root string, interval time.Duration) (Watcher, error) {
+	w := &pollingWatcher{
+		root:     root,
+		interval: interval,
+		batcher:  newBatcher(),
+		stop:     make(chan struct{}),
+	}
+	initial := w.scan()
+	go w.loop(initial)
+	return w, nil
+}
+
+func (w *pollingWatcher) Events() <-chan []string {
+	return w.batcher.out
+}
+
+func (w *pollingWatcher) Close() error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+		w.batcher.close()
+	})
+	return nil
+}
+
+func (w *pollingWatcher) loop(previous map[string]fileState) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			current := w.scan()
+			for path, state := range current {
+				if old, ok := previous[path]; !ok || old != state {
+					w.batcher.add(path)
+				}
+			}
+			for path := range previous {
+				if _, ok := current[path]; !ok {
+					w.batcher.add(path)
+				}
+			}
+			previous = current
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+func (w *pollingWatcher) scan() map[string]fileState {
+	states := make(map[string]fileState)
+	_ = filepath.Walk(w.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != w.root && SkipDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		states[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return states
+}
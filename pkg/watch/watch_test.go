@@ -0,0 +1,42 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPollingWatcherReportsChanges(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.ts")
+	if err := os.WriteFile(file, []byte("const a = 1;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "node_modules"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewPolling(dir, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := os.WriteFile(file, []byte("const a = 12;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Changes inside node_modules must be ignored
+	if err := os.WriteFile(filepath.Join(dir, "node_modules", "x.d.ts"), []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case batch := <-w.Events():
+		if len(batch) != 1 || batch[0] != file {
+			t.Errorf("expected [%s], got %v", file, batch)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for change event")
+	}
+}